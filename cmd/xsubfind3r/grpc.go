@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	hqgologgerformatter "github.com/hueristiq/hq-go-logger/formatter"
+	hqgologgerlevels "github.com/hueristiq/hq-go-logger/levels"
+	"github.com/hueristiq/xsubfind3r/internal/configuration"
+	"github.com/hueristiq/xsubfind3r/internal/rpc"
+	"github.com/hueristiq/xsubfind3r/internal/server"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	xsubfind3rv1 "github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/rpc/v1"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"github.com/logrusorgru/aurora/v4"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// isGRPC reports whether the program was invoked in gRPC server mode, as
+// `xsubfind3r grpc`.
+func isGRPC() bool {
+	return len(os.Args) > 1 && os.Args[1] == "grpc"
+}
+
+// serveGRPC runs the gRPC FinderService until interrupted, as configured by args.
+func serveGRPC(args []string) {
+	var (
+		grpcConfigurationFilePath string
+		grpcListen                string
+		grpcConcurrency           int
+		grpcTLSCert               string
+		grpcTLSKey                string
+		grpcSourcesToUse          []string
+		grpcSourcesToExclude      []string
+		grpcExcludeExpired        bool
+		grpcInclude               []string
+		grpcMonochrome            bool
+		grpcSilent                bool
+		grpcVerbose               bool
+	)
+
+	flags := pflag.NewFlagSet("grpc", pflag.ExitOnError)
+
+	flags.StringVarP(&grpcConfigurationFilePath, "configuration", "c", configuration.DefaultConfigurationFilePath, "")
+	flags.StringVar(&grpcListen, "listen", "127.0.0.1:9091", "")
+	flags.IntVarP(&grpcConcurrency, "concurrency", "C", 5, "")
+	flags.StringVar(&grpcTLSCert, "tls-cert", "", "")
+	flags.StringVar(&grpcTLSKey, "tls-key", "", "")
+	flags.StringSliceVarP(&grpcSourcesToUse, "sources-to-use", "u", []string{}, "")
+	flags.StringSliceVarP(&grpcSourcesToExclude, "sources-to-exclude", "e", []string{}, "")
+	flags.BoolVar(&grpcExcludeExpired, "exclude-expired", false, "")
+	flags.StringSliceVarP(&grpcInclude, "include", "i", []string{}, "")
+	flags.BoolVarP(&grpcMonochrome, "monochrome", "m", false, "")
+	flags.BoolVarP(&grpcSilent, "silent", "s", false, "")
+	flags.BoolVarP(&grpcVerbose, "verbose", "v", false, "")
+
+	flags.Usage = func() {
+		hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+		h := "USAGE:\n"
+		h += fmt.Sprintf(" %s grpc [OPTIONS]\n", configuration.NAME)
+
+		h += "\nCONFIGURATION:\n"
+
+		defaultConfigurationFilePath := strings.ReplaceAll(configuration.DefaultConfigurationFilePath, configuration.UserDotConfigDirectoryPath, "$HOME/.config")
+
+		h += fmt.Sprintf(" -c, --configuration string           (default: %v)\n", au.Underline(defaultConfigurationFilePath).Bold())
+
+		h += "\nSERVER:\n"
+		h += "     --listen string                  address to listen on (default: 127.0.0.1:9091)\n"
+		h += " -C, --concurrency int                number of runs to run concurrently, others wait (default: 5)\n"
+		h += "     --tls-cert string                serve over TLS with this PEM certificate (chain), requires --tls-key\n"
+		h += "     --tls-key string                 PEM private key of --tls-cert\n"
+
+		h += "\nSOURCES:\n"
+		h += " -u, --sources-to-use string[]        comma(,) separated sources runs may use\n"
+		h += " -e, --sources-to-exclude string[]    comma(,) separated sources runs may not use\n"
+		h += "     --exclude-expired bool           drop names found only in expired certificates\n"
+
+		h += "\nOUTPUT:\n"
+		h += " -i, --include string[]               comma(,) separated result types to stream besides subdomains (ip, url, record, asn)\n"
+		h += " -m, --monochrome bool                stdout in monochrome\n"
+		h += " -s, --silent bool                    stdout in silent mode\n"
+		h += " -v, --verbose bool                   stdout in verbose mode\n"
+
+		hqgologger.Info(h, hqgologger.WithLabel(""))
+		hqgologger.Print("")
+	}
+
+	_ = flags.Parse(args)
+
+	hqgologger.DefaultLogger.SetFormatter(
+		hqgologgerformatter.NewConsoleFormatter(&hqgologgerformatter.ConsoleFormatterConfiguration{
+			Colorize: !grpcMonochrome,
+		}),
+	)
+
+	if grpcSilent {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelSilent)
+	}
+
+	if grpcVerbose {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelDebug)
+	}
+
+	au = aurora.New(aurora.WithColors(!grpcMonochrome))
+
+	hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+	if err := configuration.CreateOrUpdate(grpcConfigurationFilePath); err != nil {
+		hqgologger.Fatal("failed creating or updating Configuration!", hqgologger.WithError(err))
+	}
+
+	cfg, err := loadConfiguration(grpcConfigurationFilePath)
+	if err != nil {
+		hqgologger.Fatal("failed reading in Configuration!", hqgologger.WithError(err))
+	}
+
+	types := []sources.ResultType{}
+
+	for _, name := range grpcInclude {
+		t, err := sources.ParseResultType(name)
+		if err != nil {
+			hqgologger.Fatal("invalid result type!", hqgologger.WithError(err))
+		}
+
+		types = append(types, t)
+	}
+
+	finderCFG := newFinderConfiguration(cfg)
+
+	finderCFG.SourcesToUSe = grpcSourcesToUse
+	finderCFG.SourcesToExclude = grpcSourcesToExclude
+	finderCFG.ResultTypes = types
+	finderCFG.ExcludeExpired = grpcExcludeExpired
+
+	finder, err := xsubfind3r.New(finderCFG)
+	if err != nil {
+		hqgologger.Fatal("failed creating finder!", hqgologger.WithError(err))
+	}
+
+	tenants, err := server.New(finder, grpcConcurrency, cfg.Server.Tenants)
+	if err != nil {
+		hqgologger.Fatal("failed creating server!", hqgologger.WithError(err))
+	}
+
+	if len(cfg.Server.Tenants) == 0 {
+		hqgologger.Warn("no tenants configured, the service is open to anyone reaching it!")
+	}
+
+	var options []grpc.ServerOption
+
+	if checkTLS(grpcListen, grpcTLSCert, grpcTLSKey, len(cfg.Server.Tenants) > 0) {
+		creds, err := credentials.NewServerTLSFromFile(grpcTLSCert, grpcTLSKey)
+		if err != nil {
+			hqgologger.Fatal("failed loading TLS certificate!", hqgologger.WithError(err))
+		}
+
+		options = append(options, grpc.Creds(creds))
+	}
+
+	s := rpc.New(finder, tenants)
+
+	srv := grpc.NewServer(options...)
+
+	xsubfind3rv1.RegisterFinderServiceServer(srv, s)
+
+	listener, err := net.Listen("tcp", grpcListen)
+	if err != nil {
+		hqgologger.Fatal("failed listening!", hqgologger.WithError(err), hqgologger.WithString("address", grpcListen))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		stop()
+
+		hqgologger.Info("stopping, cancelling runs...")
+
+		// Cancelling the runs ends their calls, and closing the tenants' server the calls
+		// waiting on a slot, so that the graceful stop does not wait on them.
+		s.Close()
+
+		tenants.Close()
+
+		srv.GracefulStop()
+	}()
+
+	hqgologger.Info(fmt.Sprintf("listening on %v...", au.Underline(grpcListen).Bold()))
+
+	if err := srv.Serve(listener); err != nil {
+		hqgologger.Fatal("failed serving!", hqgologger.WithError(err))
+	}
+
+	hqgologger.Info("stopped serving.")
+}
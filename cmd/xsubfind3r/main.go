@@ -2,20 +2,31 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	hqgologger "github.com/hueristiq/hq-go-logger"
 	hqgologgerformatter "github.com/hueristiq/hq-go-logger/formatter"
 	hqgologgerlevels "github.com/hueristiq/hq-go-logger/levels"
+	"github.com/hueristiq/xsubfind3r/internal/cluster"
 	"github.com/hueristiq/xsubfind3r/internal/configuration"
+	"github.com/hueristiq/xsubfind3r/internal/diff"
 	"github.com/hueristiq/xsubfind3r/internal/input"
+	"github.com/hueristiq/xsubfind3r/internal/notify"
 	"github.com/hueristiq/xsubfind3r/internal/output"
+	"github.com/hueristiq/xsubfind3r/internal/store"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
 	"github.com/logrusorgru/aurora/v4"
@@ -27,12 +38,31 @@ var (
 	configurationFilePath string
 	domains               []string
 	domainsFilePath       string
+	registrable           bool
+	stream                bool
+	concurrency           int
+	maxAge                string
+	excludeExpired        bool
+	minScore              float64
+	queuePath             string
+	coordinateAddress     string
 	listSupportedSources  bool
 	sourcesToUse          []string
 	sourcesToExclude      []string
+	importHosts           []string
+	importJSONL           []string
+	importZones           []string
+	importFDNS            []string
+	resultTypes           []string
+	outputFormat          string
+	outputCSVColumns      []string
 	outputInJSONL         bool
 	outputFilePath        string
 	outputDirectoryPath   string
+	storeFilePath         string
+	diffBaseline          string
+	diffRemoved           bool
+	notifyOnFinish        bool
 	monochrome            bool
 	silent                bool
 	verbose               bool
@@ -41,15 +71,40 @@ var (
 )
 
 func init() {
+	if isQuery() || isMonitor() || isServe() || isWorker() || isGRPC() {
+		return
+	}
+
 	pflag.StringVarP(&configurationFilePath, "configuration", "c", configuration.DefaultConfigurationFilePath, "")
 	pflag.StringSliceVarP(&domains, "domain", "d", []string{}, "")
 	pflag.StringVarP(&domainsFilePath, "list", "l", "", "")
+	pflag.BoolVar(&registrable, "registrable", false, "")
+	pflag.BoolVar(&stream, "stream", false, "")
+	pflag.IntVarP(&concurrency, "concurrency", "C", 5, "")
+	pflag.StringVar(&maxAge, "max-age", "", "")
+	pflag.BoolVar(&excludeExpired, "exclude-expired", false, "")
+	pflag.Float64Var(&minScore, "min-score", 0, "")
+	pflag.StringVar(&queuePath, "queue", "", "")
+	pflag.Lookup("queue").NoOptDefVal = configuration.DefaultQueuePath
+	pflag.StringVar(&coordinateAddress, "coordinate", "", "")
 	pflag.BoolVar(&listSupportedSources, "sources", false, "")
 	pflag.StringSliceVarP(&sourcesToUse, "sources-to-use", "u", []string{}, "")
 	pflag.StringSliceVarP(&sourcesToExclude, "sources-to-exclude", "e", []string{}, "")
+	pflag.StringSliceVar(&importHosts, "import-hosts", []string{}, "")
+	pflag.StringSliceVar(&importJSONL, "import-jsonl", []string{}, "")
+	pflag.StringSliceVar(&importZones, "import-zone", []string{}, "")
+	pflag.StringSliceVar(&importFDNS, "import-fdns", []string{}, "")
+	pflag.StringSliceVarP(&resultTypes, "include", "i", []string{}, "")
+	pflag.StringVarP(&outputFormat, "format", "f", "txt", "")
+	pflag.StringSliceVar(&outputCSVColumns, "csv-columns", []string{}, "")
 	pflag.BoolVar(&outputInJSONL, "jsonl", false, "")
 	pflag.StringVarP(&outputFilePath, "output", "o", "", "")
 	pflag.StringVarP(&outputDirectoryPath, "output-directory", "O", "", "")
+	pflag.StringVar(&storeFilePath, "store", "", "")
+	pflag.Lookup("store").NoOptDefVal = configuration.DefaultStoreFilePath
+	pflag.StringVar(&diffBaseline, "diff", "", "")
+	pflag.BoolVar(&diffRemoved, "diff-removed", false, "")
+	pflag.BoolVar(&notifyOnFinish, "notify", false, "")
 	pflag.BoolVarP(&monochrome, "monochrome", "m", false, "")
 	pflag.BoolVarP(&silent, "silent", "s", false, "")
 	pflag.BoolVarP(&verbose, "verbose", "v", false, "")
@@ -69,23 +124,71 @@ func init() {
 		h += "\nINPUT:\n"
 		h += " -d, --domain string[]                target domain\n"
 		h += " -l, --list string                    target domains file path\n"
+		h += "     --registrable bool               reduce targets to their registrable domain\n"
 
 		h += "\n For multiple domains, use comma(,) separated value with `--domain`,\n"
 		h += " specify multiple `--domains`, load from file with `--list` or load from stdin.\n"
+		h += " URLs, e-mail addresses, wildcards and IDNs are reduced to their hostname.\n"
+
+		h += "\nENUMERATION:\n"
+		h += "     --stream bool                    enumerate domains as they are read from stdin (or `--list` FIFO)\n"
+		h += " -C, --concurrency int                number of domains to enumerate concurrently (default: 5)\n"
+		h += "     --max-age string                 drop results last seen longer ago than this, e.g. 90d or 12h\n"
+		h += "     --exclude-expired bool           drop names found only in expired certificates\n"
+		h += "     --min-score float                drop subdomains with a confidence score below this (0-1)\n"
+
+		defaultQueuePath := strings.ReplaceAll(configuration.DefaultQueuePath, configuration.UserDotConfigDirectoryPath, "$HOME/.config")
+
+		h += fmt.Sprintf("     --queue string                   persist the batch in a directory, resuming it if interrupted (default: %v)\n", au.Underline(defaultQueuePath).Bold())
+		h += "     --coordinate string              hand domains out to workers, listening on this address, e.g. 0.0.0.0:9090\n"
 
 		h += "\nSOURCES:\n"
 		h += "     --sources bool                   list supported sources\n"
 		h += " -u, --sources-to-use string[]        comma(,) separated sources to use\n"
 		h += " -e, --sources-to-exclude string[]    comma(,) separated sources to exclude\n"
 
+		h += "\nIMPORT:\n"
+		h += "     --import-hosts string[]          host list file(s) to import\n"
+		h += "     --import-jsonl string[]          JSONL output file(s) of other tools to import\n"
+		h += "     --import-zone string[]           BIND zone file(s) to import\n"
+		h += "     --import-fdns string[]           forward DNS (FDNS JSON) dataset file(s) to import\n"
+
+		h += "\n Import files may be gzip-compressed.\n"
+
 		h += "\nOUTPUT:\n"
-		h += "     --jsonl bool                     output in JSONL(ines)\n"
+		h += " -i, --include string[]               comma(,) separated result types to output besides subdomains (ip, url, record, asn)\n"
+		h += " -f, --format string                  output format: txt, jsonl, json or csv (default: txt)\n"
+		h += "     --csv-columns string[]           comma(,) separated CSV columns (default: domain,type,value,sources,first_seen,last_seen,score)\n"
+		h += "     --jsonl bool                     output in JSONL(ines), same as `--format jsonl`\n"
 		h += " -o, --output string                  output write file path\n"
 		h += " -O, --output-directory string        output write directory path\n"
+		h += "     --store string                   record results in a SQLite database (default: $HOME/.config/xsubfind3r/results.db)\n"
 		h += " -m, --monochrome bool                stdout in monochrome\n"
 		h += " -s, --silent bool                    stdout in silent mode\n"
 		h += " -v, --verbose bool                   stdout in verbose mode\n"
 
+		h += "\nDIFF:\n"
+		h += "     --diff string                    output only subdomains new since the baseline: a file, a directory or `store`\n"
+		h += "     --diff-removed bool              also output subdomains of the baseline no longer found, prefixed with `-`\n"
+
+		h += "\nNOTIFY:\n"
+		h += "     --notify bool                    send the subdomains output, per domain, to the `notifiers` of the configuration once the run completes\n"
+
+		h += "\nQUERY:\n"
+		h += fmt.Sprintf(" %s query [OPTIONS]                list recorded subdomains, see `%s query --help`\n", configuration.NAME, configuration.NAME)
+
+		h += "\nMONITOR:\n"
+		h += fmt.Sprintf(" %s monitor [OPTIONS]              enumerate domains on a schedule and report new subdomains, see `%s monitor --help`\n", configuration.NAME, configuration.NAME)
+
+		h += "\nSERVE:\n"
+		h += fmt.Sprintf(" %s serve [OPTIONS]                run an HTTP API enumerating domains as jobs, see `%s serve --help`\n", configuration.NAME, configuration.NAME)
+
+		h += "\nGRPC:\n"
+		h += fmt.Sprintf(" %s grpc [OPTIONS]                 run a gRPC service streaming enumerations, see `%s grpc --help`\n", configuration.NAME, configuration.NAME)
+
+		h += "\nWORKER:\n"
+		h += fmt.Sprintf(" %s worker [OPTIONS]               run the jobs of a `--coordinate` run, see `%s worker --help`\n", configuration.NAME, configuration.NAME)
+
 		hqgologger.Info(h, hqgologger.WithLabel(""))
 		hqgologger.Print("")
 	}
@@ -125,6 +228,36 @@ func init() {
 }
 
 func main() {
+	if isQuery() {
+		query(os.Args[2:])
+
+		return
+	}
+
+	if isMonitor() {
+		runMonitor(os.Args[2:])
+
+		return
+	}
+
+	if isServe() {
+		serve(os.Args[2:])
+
+		return
+	}
+
+	if isWorker() {
+		work(os.Args[2:])
+
+		return
+	}
+
+	if isGRPC() {
+		serveGRPC(os.Args[2:])
+
+		return
+	}
+
 	hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
 
 	var cfg *configuration.Configuration
@@ -134,8 +267,9 @@ func main() {
 	}
 
 	if listSupportedSources {
-		hqgologger.Info(fmt.Sprintf("listing, %v, current supported sources.", au.Underline(strconv.Itoa(len(cfg.Sources))).Bold()))
+		hqgologger.Info(fmt.Sprintf("listing, %v, current supported sources.", au.Underline(strconv.Itoa(len(cfg.Sources)+len(sources.OptIn)+len(sources.Active))).Bold()))
 		hqgologger.Info(fmt.Sprintf("sources marked with %v take in key(s) or token(s).", au.Underline("*").Bold()))
+		hqgologger.Info(fmt.Sprintf("sources marked with %v are costly or interact with the target, and are only used when named with `-u`.", au.Underline("!").Bold()))
 		hqgologger.Print("")
 
 		needsKey := make(map[string]interface{})
@@ -155,120 +289,658 @@ func main() {
 			}
 		}
 
+		for _, source := range slices.Concat(sources.OptIn, sources.Active) {
+			hqgologger.Print("> " + source + " !")
+		}
+
 		hqgologger.Print("")
 
 		os.Exit(0)
 	}
 
-	if domainsFilePath != "" {
-		file, err := os.Open(domainsFilePath)
+	cfg.Imports.Hosts = append(cfg.Imports.Hosts, importHosts...)
+	cfg.Imports.JSONL = append(cfg.Imports.JSONL, importJSONL...)
+	cfg.Imports.Zones = append(cfg.Imports.Zones, importZones...)
+	cfg.Imports.FDNS = append(cfg.Imports.FDNS, importFDNS...)
+
+	types := []sources.ResultType{}
+
+	for _, name := range resultTypes {
+		t, err := sources.ParseResultType(name)
 		if err != nil {
-			hqgologger.Fatal("failed opening input file", hqgologger.WithError(err))
+			hqgologger.Fatal("invalid result type!", hqgologger.WithError(err))
 		}
 
-		scanner := bufio.NewScanner(file)
+		types = append(types, t)
+	}
 
-		for scanner.Scan() {
-			domain := scanner.Text()
+	if outputInJSONL {
+		outputFormat = "jsonl"
+	}
 
-			if domain != "" {
-				domains = append(domains, domain)
+	format, err := output.ParseFormat(outputFormat, outputCSVColumns)
+	if err != nil {
+		hqgologger.Fatal("invalid output format!", hqgologger.WithError(err))
+	}
+
+	writer := output.NewWriter()
+
+	writer.SetFormat(format)
+
+	finderCFG := newFinderConfiguration(cfg)
+
+	finderCFG.SourcesToUSe = sourcesToUse
+	finderCFG.SourcesToExclude = sourcesToExclude
+	finderCFG.ResultTypes = types
+	finderCFG.ExcludeExpired = excludeExpired
+
+	normalizer := &input.Normalizer{
+		Registrable: registrable,
+	}
+
+	finder, err := xsubfind3r.New(finderCFG)
+	if err != nil {
+		hqgologger.Fatal("failed creating finder!", hqgologger.WithError(err))
+
+		return
+	}
+
+	queue := make(chan string)
+
+	if stream {
+		reader := io.Reader(os.Stdin)
+
+		if domainsFilePath != "" {
+			file, err := os.Open(domainsFilePath)
+			if err != nil {
+				hqgologger.Fatal("failed opening input file", hqgologger.WithError(err))
 			}
+
+			defer file.Close()
+
+			reader = file
 		}
 
-		if err := scanner.Err(); err != nil {
-			hqgologger.Fatal("failed reading input file!", hqgologger.WithError(err))
+		go func() {
+			defer close(queue)
+
+			seen := map[string]struct{}{}
+
+			enqueue := func(line string) {
+				domain, ok := normalize(normalizer, line)
+				if !ok {
+					return
+				}
+
+				if _, ok := seen[domain]; ok {
+					return
+				}
+
+				seen[domain] = struct{}{}
+
+				hqgologger.Info(fmt.Sprintf("Finding subdomains for %v...", au.Underline(domain).Bold()))
+
+				queue <- domain
+			}
+
+			for _, domain := range domains {
+				enqueue(domain)
+			}
+
+			scanner := bufio.NewScanner(reader)
+
+			for scanner.Scan() {
+				enqueue(scanner.Text())
+			}
+
+			if err := scanner.Err(); err != nil {
+				hqgologger.Error("failed reading input stream!", hqgologger.WithError(err))
+			}
+		}()
+	} else {
+		lines := domains
+
+		if domainsFilePath != "" {
+			file, err := os.Open(domainsFilePath)
+			if err != nil {
+				hqgologger.Fatal("failed opening input file", hqgologger.WithError(err))
+			}
+
+			scanner := bufio.NewScanner(file)
+
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+			}
+
+			if err := scanner.Err(); err != nil {
+				hqgologger.Fatal("failed reading input file!", hqgologger.WithError(err))
+			}
+
+			file.Close()
 		}
 
-		file.Close()
-	}
+		if input.HasStdin() {
+			scanner := bufio.NewScanner(os.Stdin)
 
-	if input.HasStdin() {
-		scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+			}
+
+			if err := scanner.Err(); err != nil {
+				hqgologger.Fatal("failed reading stdin!", hqgologger.WithError(err))
+			}
+		}
 
-		for scanner.Scan() {
-			domain := scanner.Text()
+		domains = []string{}
 
-			if domain != "" {
+		for _, line := range lines {
+			if domain, ok := normalize(normalizer, line); ok {
 				domains = append(domains, domain)
 			}
 		}
 
-		if err := scanner.Err(); err != nil {
-			hqgologger.Fatal("failed reading stdin!", hqgologger.WithError(err))
-		}
+		domains = input.Collapse(domains)
+
+		go func() {
+			defer close(queue)
+
+			for _, domain := range domains {
+				hqgologger.Info(fmt.Sprintf("Finding subdomains for %v...", au.Underline(domain).Bold()))
+
+				queue <- domain
+			}
+		}()
 	}
 
-	writer := output.NewWriter()
+	files := map[string]*os.File{}
 
-	if outputInJSONL {
-		writer.SetFormatToJSONL()
+	errs := map[string]int{}
+
+	start := time.Now()
+
+	var (
+		notifiers []notify.Notifier
+		events    map[string]*notify.Event
+	)
+
+	if notifyOnFinish {
+		notifiers, err = newNotifiers(cfg.Notifiers)
+		if err != nil {
+			hqgologger.Fatal("invalid notifier!", hqgologger.WithError(err))
+		}
+
+		if len(notifiers) == 0 {
+			hqgologger.Warn("no notifiers configured, `--notify` has no effect!")
+		}
+
+		events = map[string]*notify.Event{}
 	}
 
-	finder, err := xsubfind3r.New(&xsubfind3r.Configuration{
-		Client: &xsubfind3r.ClientConfiguration{
-			UserAgent: fmt.Sprintf("%s %s (https://github.com/hueristiq/%s.git)", configuration.NAME, configuration.VERSION, configuration.NAME),
-		},
-		SourcesToUSe:     sourcesToUse,
-		SourcesToExclude: sourcesToExclude,
-		Keys:             cfg.Keys,
-	})
-	if err != nil {
-		hqgologger.Fatal("failed creating finder!", hqgologger.WithError(err))
+	event := func(domain string) (e *notify.Event) {
+		e, ok := events[domain]
+		if !ok {
+			e = &notify.Event{
+				Domain: domain,
+				Time:   start,
+				Added:  []notify.Subdomain{},
+			}
+
+			events[domain] = e
+		}
 
 		return
 	}
 
-	for index := range domains {
-		domain := domains[index]
+	if diffBaseline == "store" && storeFilePath == "" {
+		storeFilePath = configuration.DefaultStoreFilePath
+	}
 
-		hqgologger.Info(fmt.Sprintf("Finding subdomains for %v...", au.Underline(domain).Bold()))
-		hqgologger.Print("")
+	var (
+		db  *store.Store
+		run *store.Run
+	)
+
+	if storeFilePath != "" {
+		db, err = store.Open(storeFilePath)
+		if err != nil {
+			hqgologger.Fatal("failed opening results store!", hqgologger.WithError(err), hqgologger.WithString("file", storeFilePath))
+		}
+
+		defer db.Close()
+
+		run, err = db.StartRun(configuration.VERSION)
+		if err != nil {
+			hqgologger.Fatal("failed recording run!", hqgologger.WithError(err))
+		}
+	}
+
+	var tracker *diff.Tracker
+
+	if diffBaseline != "" {
+		baseline, err := newBaseline(diffBaseline, db)
+		if err != nil {
+			hqgologger.Fatal("failed loading diff baseline!", hqgologger.WithError(err), hqgologger.WithString("baseline", diffBaseline))
+		}
+
+		tracker = diff.NewTracker(baseline)
+	}
+
+	// pending holds the domains results were written for whose end has not been seen.
+	pending := map[string]struct{}{}
+
+	write := func(domain string, result sources.Result) {
+		pending[domain] = struct{}{}
 
 		outputs := []io.Writer{
 			os.Stdout,
 		}
 
-		var file *os.File
+		if file := outputFile(writer, files, domain); file != nil {
+			outputs = append(outputs, file)
+		}
 
-		switch {
-		case outputFilePath != "":
-			file, err = writer.CreateFile(outputFilePath)
-			if err != nil {
-				hqgologger.Fatal("failed craeting output file!", hqgologger.WithError(err), hqgologger.WithString("file", outputFilePath))
+		for _, output := range outputs {
+			if err := writer.Write(output, domain, result); err != nil {
+				hqgologger.Error("error writing result!", hqgologger.WithError(err), hqgologger.WithString("source", result.Source))
 			}
+		}
+	}
 
-			outputs = append(outputs, file)
-		case outputDirectoryPath != "":
-			outputFilePath = filepath.Join(outputDirectoryPath, domain)
+	// finish reports the subdomains of the diff baseline of domain that were not found
+	// again, then writes out the output buffered for domain and closes its own file, if
+	// any, once every result of domain has been written.
+	finish := func(domain string) {
+		if tracker != nil && diffRemoved {
+			removed := tracker.RemovedFrom(domain)
 
-			file, err = writer.CreateFile(outputFilePath)
-			if err != nil {
-				hqgologger.Fatal("failed craeting output file!", hqgologger.WithError(err), hqgologger.WithString("file", outputFilePath))
+			if events != nil && len(removed) > 0 {
+				event(domain).Removed = removed
 			}
 
-			outputs = append(outputs, file)
+			for _, subdomain := range removed {
+				write(domain, sources.Result{
+					Type:   sources.ResultSubdomain,
+					Source: "baseline",
+					Value:  subdomain,
+					Metadata: map[string]string{
+						output.MetadataChange: output.ChangeRemoved,
+					},
+				})
+			}
+		}
+
+		delete(pending, domain)
+
+		if err := writer.Flush(domain); err != nil {
+			hqgologger.Error("error writing results!", hqgologger.WithError(err), hqgologger.WithString("domain", domain))
+		}
+
+		if outputFilePath != "" || outputDirectoryPath == "" {
+			return
 		}
 
-		results := finder.Find(domain)
+		path := filepath.Join(outputDirectoryPath, domain)
 
-		for result := range results {
-			for _, output := range outputs {
-				switch result.Type {
-				case sources.ResultError:
-					if verbose {
-						hqgologger.Error("error finding subdomains!", hqgologger.WithError(err), hqgologger.WithString("source", result.Source))
-					}
-				case sources.ResultSubdomain:
-					if err := writer.Write(output, domain, result); err != nil {
-						hqgologger.Error("error writing subdomain!", hqgologger.WithError(err), hqgologger.WithString("source", result.Source))
-					}
+		file, ok := files[path]
+		if !ok {
+			return
+		}
+
+		if err := writer.Release(file); err != nil {
+			hqgologger.Error("error writing results!", hqgologger.WithError(err), hqgologger.WithString("domain", domain))
+		}
+
+		file.Close()
+
+		delete(files, path)
+	}
+
+	// Reports complete the sources recorded in the store; they are not written out.
+	options := []xsubfind3r.FindOption{
+		xsubfind3r.WithDomainEnds(),
+		xsubfind3r.WithReports(),
+	}
+
+	if maxAge != "" {
+		age, err := parseAge(maxAge)
+		if err != nil {
+			hqgologger.Fatal("invalid maximum age!", hqgologger.WithError(err))
+		}
+
+		options = append(options, xsubfind3r.WithMaxAge(age))
+	}
+
+	if minScore > 0 {
+		options = append(options, xsubfind3r.WithMinScore(minScore))
+	}
+
+	var results chan xsubfind3r.DomainResult
+
+	switch {
+	case coordinateAddress != "" && queuePath != "":
+		hqgologger.Fatal("`--coordinate` and `--queue` are mutually exclusive!")
+	case coordinateAddress != "":
+		settings := cluster.Settings{
+			Sources:        sourcesToUse,
+			Exclude:        sourcesToExclude,
+			Include:        resultTypes,
+			ExcludeExpired: excludeExpired,
+			MaxAge:         maxAge,
+			MinScore:       minScore,
+		}
+
+		coordinator := cluster.NewCoordinator(settings, cfg.Cluster.Token, cluster.DefaultLeaseTimeout)
+
+		if cfg.Cluster.Token == "" {
+			hqgologger.Warn("no cluster token configured, anyone reaching the coordinator may run its jobs!")
+		}
+
+		srv := &http.Server{
+			Addr:              coordinateAddress,
+			Handler:           coordinator.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		listener, err := net.Listen("tcp", coordinateAddress)
+		if err != nil {
+			hqgologger.Fatal("failed listening!", hqgologger.WithError(err), hqgologger.WithString("address", coordinateAddress))
+		}
+
+		go func() {
+			if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				hqgologger.Fatal("failed coordinating!", hqgologger.WithError(err))
+			}
+		}()
+
+		// Workers learn that every job has completed on their next lease, so the server
+		// lingers a little once the results are drained.
+		defer func() {
+			time.Sleep(2 * time.Second)
+
+			_ = srv.Close()
+		}()
+
+		hqgologger.Info(fmt.Sprintf("coordinating workers on %v...", au.Underline(listener.Addr().String()).Bold()))
+
+		results = coordinator.Run(queue)
+	case queuePath != "":
+		q, err := openQueue(queuePath, cfg.Queue)
+		if err != nil {
+			hqgologger.Fatal("failed opening job queue!", hqgologger.WithError(err), hqgologger.WithString("queue", queuePath))
+		}
+
+		defer q.Close()
+
+		input := domains
+
+		if stream {
+			// Streamed domains are not known in advance: the batch is identified by where
+			// they are read from.
+			input = append([]string{"stream", domainsFilePath}, domains...)
+		}
+
+		results = findQueued(finder, q, queue, concurrency, batchIdentity(batchOptions(), input), options...)
+	default:
+		results = finder.FindFrom(queue, concurrency, options...)
+	}
+
+	for result := range results {
+		if result.End {
+			finish(result.Domain)
+
+			continue
+		}
+
+		if run != nil {
+			if err := run.Add(result.Domain, result.Result); err != nil {
+				hqgologger.Error("error recording result!", hqgologger.WithError(err), hqgologger.WithString("source", result.Source))
+			}
+		}
+
+		switch result.Type {
+		case sources.ResultReport:
+			continue
+		case sources.ResultError:
+			errs[sources.KindOf(result.Error).Error()]++
+
+			if tracker != nil {
+				tracker.Failed(result.Domain, result.Error)
+			}
+
+			if verbose {
+				hqgologger.Error("error finding subdomains!", hqgologger.WithError(result.Error), hqgologger.WithString("source", result.Source))
+			}
+		case sources.ResultSubdomain:
+			if tracker != nil {
+				added, err := tracker.Added(result.Domain, result.Value)
+				if err != nil {
+					hqgologger.Error("error loading diff baseline!", hqgologger.WithError(err), hqgologger.WithString("domain", result.Domain))
+
+					continue
 				}
+
+				if !added {
+					continue
+				}
+
+				result.Metadata = maps.Clone(result.Metadata)
+				if result.Metadata == nil {
+					result.Metadata = map[string]string{}
+				}
+
+				result.Metadata[output.MetadataChange] = output.ChangeAdded
 			}
+
+			if events != nil {
+				e := event(result.Domain)
+
+				e.Added = append(e.Added, notify.Subdomain{
+					Name:    result.Value,
+					Sources: result.Sources,
+					Score:   result.Score,
+				})
+			}
+
+			write(result.Domain, result.Result)
+		default:
+			// Diff mode reports changes to subdomains only.
+			if tracker != nil {
+				continue
+			}
+
+			write(result.Domain, result.Result)
 		}
+	}
 
+	// Domains whose end was not seen, e.g. as the coordinator was interrupted.
+	for _, domain := range slices.Sorted(maps.Keys(pending)) {
+		finish(domain)
+	}
+
+	if err := writer.Close(); err != nil {
+		hqgologger.Error("error writing results!", hqgologger.WithError(err))
+	}
+
+	if run != nil {
+		if err := run.Finish(); err != nil {
+			hqgologger.Error("error recording run!", hqgologger.WithError(err))
+		}
+	}
+
+	if tracker != nil {
+		if err := tracker.Commit(); err != nil {
+			hqgologger.Error("error updating diff baseline!", hqgologger.WithError(err), hqgologger.WithString("baseline", diffBaseline))
+		}
+	}
+
+	for _, file := range files {
 		file.Close()
+	}
 
-		hqgologger.Print("")
+	for _, domain := range slices.Sorted(maps.Keys(events)) {
+		e := events[domain]
+
+		slices.SortFunc(e.Added, func(a, b notify.Subdomain) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		if err := notify.Dispatch(context.Background(), notifiers, *e); err != nil {
+			hqgologger.Error("failed notifying!", hqgologger.WithError(err), hqgologger.WithString("domain", domain))
+		}
+	}
+
+	summarize(errs)
+}
+
+// newNotifiers returns the notifiers configured by cfgs.
+func newNotifiers(cfgs []notify.Configuration) (notifiers []notify.Notifier, err error) {
+	for _, cfg := range cfgs {
+		var notifier notify.Notifier
+
+		notifier, err = notify.New(cfg)
+		if err != nil {
+			return
+		}
+
+		notifiers = append(notifiers, notifier)
+	}
+
+	return
+}
+
+// newBaseline returns the diff baseline named by value: "store" for the results store db,
+// a directory (an existing one, or a path ending in a separator) holding a file per
+// domain, or a single file.
+func newBaseline(value string, db *store.Store) (baseline diff.Baseline, err error) {
+	if value == "store" {
+		baseline = diff.NewStoreBaseline(db)
+
+		return
+	}
+
+	if info, statErr := os.Stat(value); (statErr == nil && info.IsDir()) || strings.HasSuffix(value, string(filepath.Separator)) {
+		baseline = diff.NewDirectoryBaseline(value)
+
+		return
+	}
+
+	baseline, err = diff.NewFileBaseline(value)
+
+	return
+}
+
+// loadConfiguration reads the configuration file at path, with environment variables
+// overriding its values, for the modes that read it on their own or read it again.
+func loadConfiguration(path string) (cfg *configuration.Configuration, err error) {
+	v := viper.New()
+
+	v.SetConfigFile(path)
+
+	v.AutomaticEnv()
+
+	v.SetEnvPrefix(strings.ToUpper(configuration.NAME))
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	if err = v.ReadInConfig(); err != nil {
+		return
+	}
+
+	err = v.Unmarshal(&cfg)
+
+	return
+}
+
+// newFinderConfiguration returns the finder configuration derived from the configuration
+// file cfg. Options set by flags are left for the caller to set.
+func newFinderConfiguration(cfg *configuration.Configuration) (finderCFG *xsubfind3r.Configuration) {
+	finderCFG = &xsubfind3r.Configuration{
+		Client: &xsubfind3r.ClientConfiguration{
+			UserAgent: fmt.Sprintf("%s %s (https://github.com/hueristiq/%s.git)", configuration.NAME, configuration.VERSION, configuration.NAME),
+		},
+		Keys:    cfg.Keys,
+		DNSSEC:  cfg.DNSSEC,
+		CTLogs:  cfg.CTLogs,
+		TLS:     cfg.TLS,
+		Imports: cfg.Imports,
+		Scoring: cfg.Scoring,
+	}
+
+	return
+}
+
+// parseAge parses a maximum age: a number of days such as "90d", or a Go duration such
+// as "12h".
+func parseAge(value string) (age time.Duration, err error) {
+	age, err = input.ParseDuration(value)
+
+	return
+}
+
+// summarize warns about the number of source errors per category, if there were any.
+func summarize(errs map[string]int) {
+	if len(errs) == 0 {
+		return
+	}
+
+	categories := []string{}
+
+	for category, count := range errs {
+		categories = append(categories, fmt.Sprintf("%s (%d)", category, count))
+	}
+
+	slices.Sort(categories)
+
+	message := "sources reported errors: " + strings.Join(categories, ", ")
+
+	if !verbose {
+		message += "; use --verbose for details"
+	}
+
+	hqgologger.Warn(message)
+}
+
+// normalize normalizes an input line into a target domain, warning about rejected lines.
+func normalize(normalizer *input.Normalizer, line string) (domain string, ok bool) {
+	domain, err := normalizer.Normalize(line)
+	if err != nil {
+		if !errors.Is(err, input.ErrSkip) {
+			hqgologger.Warn("skipping input!", hqgologger.WithError(err), hqgologger.WithString("input", line))
+		}
+
+		return
 	}
+
+	ok = true
+
+	return
+}
+
+// outputFile returns the file results for domain are written to, opening it on first use:
+// the `--output` file shared by every domain, or the domain's own file in the
+// `--output-directory`. It returns nil when no file output is requested.
+func outputFile(writer *output.Writer, files map[string]*os.File, domain string) (file *os.File) {
+	var path string
+
+	switch {
+	case outputFilePath != "":
+		path = outputFilePath
+	case outputDirectoryPath != "":
+		path = filepath.Join(outputDirectoryPath, domain)
+	default:
+		return
+	}
+
+	file, ok := files[path]
+	if ok {
+		return
+	}
+
+	file, err := writer.CreateFile(path)
+	if err != nil {
+		hqgologger.Fatal("failed craeting output file!", hqgologger.WithError(err), hqgologger.WithString("file", path))
+	}
+
+	files[path] = file
+
+	return
 }
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	hqgologgerformatter "github.com/hueristiq/hq-go-logger/formatter"
+	hqgologgerlevels "github.com/hueristiq/hq-go-logger/levels"
+	"github.com/hueristiq/xsubfind3r/internal/configuration"
+	"github.com/hueristiq/xsubfind3r/internal/input"
+	"github.com/hueristiq/xsubfind3r/internal/monitor"
+	"github.com/hueristiq/xsubfind3r/internal/notify"
+	"github.com/hueristiq/xsubfind3r/internal/store"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/logrusorgru/aurora/v4"
+	"github.com/spf13/pflag"
+)
+
+// isMonitor reports whether the program was invoked in monitor mode, as
+// `xsubfind3r monitor`.
+func isMonitor() bool {
+	return len(os.Args) > 1 && os.Args[1] == "monitor"
+}
+
+// monitorFlags are the flags of monitor mode.
+type monitorFlags struct {
+	configurationFilePath string
+	domainsFilePath       string
+	interval              string
+	state                 string
+	concurrency           int
+	maxAge                string
+	excludeExpired        bool
+	minScore              float64
+	sourcesToUse          []string
+	sourcesToExclude      []string
+	storeFilePath         string
+	inJSONL               bool
+	monochrome            bool
+	silent                bool
+	verbose               bool
+}
+
+// runMonitor enumerates domains on their schedules until interrupted, reporting new
+// subdomains, as selected by args. SIGHUP reloads the configuration and the domain list.
+func runMonitor(args []string) {
+	var f monitorFlags
+
+	flags := pflag.NewFlagSet("monitor", pflag.ExitOnError)
+
+	flags.StringVarP(&f.configurationFilePath, "configuration", "c", configuration.DefaultConfigurationFilePath, "")
+	flags.StringVarP(&f.domainsFilePath, "list", "l", "", "")
+	flags.StringVar(&f.interval, "interval", "", "")
+	flags.StringVar(&f.state, "state", "", "")
+	flags.IntVarP(&f.concurrency, "concurrency", "C", 5, "")
+	flags.StringVar(&f.maxAge, "max-age", "", "")
+	flags.BoolVar(&f.excludeExpired, "exclude-expired", false, "")
+	flags.Float64Var(&f.minScore, "min-score", 0, "")
+	flags.StringSliceVarP(&f.sourcesToUse, "sources-to-use", "u", []string{}, "")
+	flags.StringSliceVarP(&f.sourcesToExclude, "sources-to-exclude", "e", []string{}, "")
+	flags.StringVar(&f.storeFilePath, "store", "", "")
+	flags.Lookup("store").NoOptDefVal = configuration.DefaultStoreFilePath
+	flags.BoolVar(&f.inJSONL, "jsonl", false, "")
+	flags.BoolVarP(&f.monochrome, "monochrome", "m", false, "")
+	flags.BoolVarP(&f.silent, "silent", "s", false, "")
+	flags.BoolVarP(&f.verbose, "verbose", "v", false, "")
+
+	flags.Usage = func() {
+		hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+		h := "USAGE:\n"
+		h += fmt.Sprintf(" %s monitor [OPTIONS]\n", configuration.NAME)
+
+		h += "\nCONFIGURATION:\n"
+
+		defaultConfigurationFilePath := strings.ReplaceAll(configuration.DefaultConfigurationFilePath, configuration.UserDotConfigDirectoryPath, "$HOME/.config")
+
+		h += fmt.Sprintf(" -c, --configuration string           (default: %v)\n", au.Underline(defaultConfigurationFilePath).Bold())
+
+		h += "\nINPUT:\n"
+		h += " -l, --list string                    monitored domains file path, one `domain [interval]` per line\n"
+
+		h += "\n Domains are also read from the `monitor.domains` section of the configuration.\n"
+
+		h += "\nSCHEDULE:\n"
+		h += "     --interval string                interval of domains with none of their own, e.g. 6h or 1d (default: 24h)\n"
+		h += "     --state string                   state directory (default: $HOME/.config/xsubfind3r/monitor)\n"
+		h += " -C, --concurrency int                number of domains to enumerate concurrently (default: 5)\n"
+
+		h += "\nENUMERATION:\n"
+		h += "     --max-age string                 drop results last seen longer ago than this, e.g. 90d or 12h\n"
+		h += "     --exclude-expired bool           drop names found only in expired certificates\n"
+		h += "     --min-score float                drop subdomains with a confidence score below this (0-1)\n"
+		h += " -u, --sources-to-use string[]        comma(,) separated sources to use\n"
+		h += " -e, --sources-to-exclude string[]    comma(,) separated sources to exclude\n"
+
+		h += "\nOUTPUT:\n"
+		h += "     --jsonl bool                     output changes as JSONL(ines) events\n"
+		h += "     --store string                   record runs in a SQLite database (default: $HOME/.config/xsubfind3r/results.db)\n"
+		h += " -m, --monochrome bool                stdout in monochrome\n"
+		h += " -s, --silent bool                    stdout in silent mode\n"
+		h += " -v, --verbose bool                   stdout in verbose mode\n"
+
+		h += "\n Send SIGHUP to reload the configuration and the domain list.\n"
+
+		hqgologger.Info(h, hqgologger.WithLabel(""))
+		hqgologger.Print("")
+	}
+
+	_ = flags.Parse(args)
+
+	hqgologger.DefaultLogger.SetFormatter(
+		hqgologgerformatter.NewConsoleFormatter(&hqgologgerformatter.ConsoleFormatterConfiguration{
+			Colorize: !f.monochrome,
+		}),
+	)
+
+	if f.silent {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelSilent)
+	}
+
+	if f.verbose {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelDebug)
+	}
+
+	au = aurora.New(aurora.WithColors(!f.monochrome))
+
+	hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+	if err := configuration.CreateOrUpdate(f.configurationFilePath); err != nil {
+		hqgologger.Fatal("failed creating or updating Configuration!", hqgologger.WithError(err))
+	}
+
+	stdout := &stdoutNotifier{
+		jsonl: f.inJSONL,
+	}
+
+	cfg, settings, err := loadMonitorSettings(&f, stdout)
+	if err != nil {
+		hqgologger.Fatal("failed loading monitor settings!", hqgologger.WithError(err))
+	}
+
+	state := f.state
+
+	if state == "" {
+		state = cfg.Monitor.State
+	}
+
+	if state == "" {
+		state = configuration.DefaultMonitorStatePath
+	}
+
+	m, err := monitor.New(state, f.concurrency, settings)
+	if err != nil {
+		hqgologger.Fatal("failed loading monitor state!", hqgologger.WithError(err), hqgologger.WithString("state", state))
+	}
+
+	if f.storeFilePath != "" {
+		db, err := store.Open(f.storeFilePath)
+		if err != nil {
+			hqgologger.Fatal("failed opening results store!", hqgologger.WithError(err), hqgologger.WithString("file", f.storeFilePath))
+		}
+
+		defer db.Close()
+
+		m.SetStore(db, configuration.VERSION)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	defer stop()
+
+	hup := make(chan os.Signal, 1)
+
+	signal.Notify(hup, syscall.SIGHUP)
+
+	defer signal.Stop(hup)
+
+	go func() {
+		<-ctx.Done()
+
+		hqgologger.Info("stopping, waiting for runs in progress...")
+
+		// Restore the default behavior: a second interrupt exits at once.
+		stop()
+	}()
+
+	go m.ReloadOn(ctx, hup, func() (settings monitor.Settings, err error) {
+		_, settings, err = loadMonitorSettings(&f, stdout)
+
+		return
+	})
+
+	hqgologger.Info(fmt.Sprintf("monitoring %v domains...", au.Underline(len(settings.Schedules)).Bold()))
+
+	m.Run(ctx)
+
+	hqgologger.Info("stopped monitoring.")
+}
+
+// loadMonitorSettings reads the configuration file and the domain list, and returns the
+// configuration together with the monitor settings derived from it and the flags. Changes
+// are dispatched to stdout first, then to the configured notifiers.
+func loadMonitorSettings(f *monitorFlags, stdout notify.Notifier) (cfg *configuration.Configuration, settings monitor.Settings, err error) {
+	cfg, err = loadConfiguration(f.configurationFilePath)
+	if err != nil {
+		return
+	}
+
+	finderCFG := newFinderConfiguration(cfg)
+
+	finderCFG.SourcesToUSe = f.sourcesToUse
+	finderCFG.SourcesToExclude = f.sourcesToExclude
+	finderCFG.ExcludeExpired = f.excludeExpired
+
+	settings.Finder, err = xsubfind3r.New(finderCFG)
+	if err != nil {
+		return
+	}
+
+	if f.maxAge != "" {
+		age, perr := parseAge(f.maxAge)
+		if perr != nil {
+			err = fmt.Errorf("invalid maximum age: %w", perr)
+
+			return
+		}
+
+		settings.Options = append(settings.Options, xsubfind3r.WithMaxAge(age))
+	}
+
+	if f.minScore > 0 {
+		settings.Options = append(settings.Options, xsubfind3r.WithMinScore(f.minScore))
+	}
+
+	interval := f.interval
+
+	if interval == "" {
+		interval = cfg.Monitor.Interval
+	}
+
+	defaultInterval := monitor.DefaultInterval
+
+	if interval != "" {
+		defaultInterval, err = monitor.ParseInterval(interval)
+		if err != nil {
+			return
+		}
+	}
+
+	targets := cfg.Monitor.Domains
+
+	if f.domainsFilePath != "" {
+		var listed []monitor.Target
+
+		listed, err = readTargets(f.domainsFilePath)
+		if err != nil {
+			return
+		}
+
+		targets = append(targets, listed...)
+	}
+
+	settings.Schedules, err = newSchedules(targets, defaultInterval)
+	if err != nil {
+		return
+	}
+
+	settings.Notifiers = []notify.Notifier{
+		stdout,
+	}
+
+	var notifiers []notify.Notifier
+
+	notifiers, err = newNotifiers(cfg.Notifiers)
+	if err != nil {
+		return
+	}
+
+	settings.Notifiers = append(settings.Notifiers, notifiers...)
+
+	return
+}
+
+// readTargets reads a domain list: one domain per line, optionally followed by its
+// interval, e.g. "example.com 6h". Blank lines and lines starting with "#" are skipped.
+func readTargets(path string) (targets []monitor.Target, err error) {
+	var file *os.File
+
+	file, err = os.Open(path)
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		target := monitor.Target{
+			Domain: fields[0],
+		}
+
+		if len(fields) > 1 {
+			target.Interval = fields[1]
+		}
+
+		targets = append(targets, target)
+	}
+
+	err = scanner.Err()
+
+	return
+}
+
+// newSchedules normalizes the domains of targets and parses their intervals. A domain
+// listed more than once keeps its last interval.
+func newSchedules(targets []monitor.Target, defaultInterval time.Duration) (schedules []monitor.Schedule, err error) {
+	normalizer := &input.Normalizer{}
+
+	index := map[string]int{}
+
+	for _, target := range targets {
+		domain, ok := normalize(normalizer, target.Domain)
+		if !ok {
+			continue
+		}
+
+		schedule := monitor.Schedule{
+			Domain:   domain,
+			Interval: defaultInterval,
+		}
+
+		if target.Interval != "" {
+			schedule.Interval, err = monitor.ParseInterval(target.Interval)
+			if err != nil {
+				err = fmt.Errorf("%s: %w", domain, err)
+
+				return
+			}
+		}
+
+		if i, ok := index[domain]; ok {
+			schedules[i] = schedule
+
+			continue
+		}
+
+		index[domain] = len(schedules)
+
+		schedules = append(schedules, schedule)
+	}
+
+	return
+}
+
+// stdoutNotifier writes changes to stdout: the new subdomains one per line and the
+// removed ones prefixed with "-", or every event as a JSON line.
+//
+// Fields:
+//   - jsonl (bool): Write events as JSON lines.
+//   - mu (sync.Mutex): Serializes writes.
+type stdoutNotifier struct {
+	jsonl bool
+	mu    sync.Mutex
+}
+
+func (n *stdoutNotifier) Name() (name string) {
+	name = "stdout"
+
+	return
+}
+
+func (n *stdoutNotifier) Notify(_ context.Context, event notify.Event) (err error) {
+	n.mu.Lock()
+
+	defer n.mu.Unlock()
+
+	if n.jsonl {
+		err = json.NewEncoder(os.Stdout).Encode(event)
+
+		return
+	}
+
+	bw := bufio.NewWriter(os.Stdout)
+
+	for _, subdomain := range event.Added {
+		fmt.Fprintln(bw, subdomain.Name)
+	}
+
+	for _, subdomain := range event.Removed {
+		fmt.Fprintln(bw, "-"+subdomain)
+	}
+
+	err = bw.Flush()
+
+	return
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	hqgologgerformatter "github.com/hueristiq/hq-go-logger/formatter"
+	hqgologgerlevels "github.com/hueristiq/hq-go-logger/levels"
+	"github.com/hueristiq/xsubfind3r/internal/configuration"
+	"github.com/hueristiq/xsubfind3r/internal/store"
+	"github.com/logrusorgru/aurora/v4"
+	"github.com/spf13/pflag"
+)
+
+// isQuery reports whether the program was invoked in query mode, as `xsubfind3r query`.
+func isQuery() bool {
+	return len(os.Args) > 1 && os.Args[1] == "query"
+}
+
+// query lists the subdomains recorded in a results store, as selected by args.
+func query(args []string) {
+	var (
+		queryStoreFilePath string
+		queryDomain        string
+		querySources       []string
+		querySince         string
+		queryUntil         string
+		queryInJSONL       bool
+		queryMonochrome    bool
+		querySilent        bool
+	)
+
+	flags := pflag.NewFlagSet("query", pflag.ExitOnError)
+
+	flags.StringVar(&queryStoreFilePath, "store", configuration.DefaultStoreFilePath, "")
+	flags.StringVarP(&queryDomain, "domain", "d", "", "")
+	flags.StringSliceVarP(&querySources, "source", "u", []string{}, "")
+	flags.StringVar(&querySince, "since", "", "")
+	flags.StringVar(&queryUntil, "until", "", "")
+	flags.BoolVar(&queryInJSONL, "jsonl", false, "")
+	flags.BoolVarP(&queryMonochrome, "monochrome", "m", false, "")
+	flags.BoolVarP(&querySilent, "silent", "s", false, "")
+
+	flags.Usage = func() {
+		hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+		h := "USAGE:\n"
+		h += fmt.Sprintf(" %s query [OPTIONS]\n", configuration.NAME)
+
+		h += "\nSTORE:\n"
+
+		defaultStoreFilePath := strings.ReplaceAll(configuration.DefaultStoreFilePath, configuration.UserDotConfigDirectoryPath, "$HOME/.config")
+
+		h += fmt.Sprintf("     --store string                   (default: %v)\n", au.Underline(defaultStoreFilePath).Bold())
+
+		h += "\nFILTERS:\n"
+		h += " -d, --domain string                  target domain whose subdomains to list\n"
+		h += " -u, --source string[]                comma(,) separated sources, list subdomains reported by any of them\n"
+		h += "     --since string                   list subdomains found by runs since, e.g. 2026-01-01 or 30d\n"
+		h += "     --until string                   list subdomains found by runs before, e.g. 2026-02-01 or 7d\n"
+
+		h += "\nOUTPUT:\n"
+		h += "     --jsonl bool                     output in JSONL(ines)\n"
+		h += " -m, --monochrome bool                stdout in monochrome\n"
+		h += " -s, --silent bool                    stdout in silent mode\n"
+
+		hqgologger.Info(h, hqgologger.WithLabel(""))
+		hqgologger.Print("")
+	}
+
+	_ = flags.Parse(args)
+
+	hqgologger.DefaultLogger.SetFormatter(
+		hqgologgerformatter.NewConsoleFormatter(&hqgologgerformatter.ConsoleFormatterConfiguration{
+			Colorize: !queryMonochrome,
+		}),
+	)
+
+	if querySilent {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelSilent)
+	}
+
+	au = aurora.New(aurora.WithColors(!queryMonochrome))
+
+	hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+	if queryDomain == "" {
+		hqgologger.Fatal("no domain specified, use `--domain`!")
+	}
+
+	q := store.Query{
+		Domain:  strings.TrimSuffix(strings.ToLower(queryDomain), "."),
+		Sources: querySources,
+	}
+
+	var err error
+
+	if querySince != "" {
+		if q.Since, err = parseDate(querySince); err != nil {
+			hqgologger.Fatal("invalid `--since` date!", hqgologger.WithError(err))
+		}
+	}
+
+	if queryUntil != "" {
+		if q.Until, err = parseDate(queryUntil); err != nil {
+			hqgologger.Fatal("invalid `--until` date!", hqgologger.WithError(err))
+		}
+	}
+
+	if _, err = os.Stat(queryStoreFilePath); err != nil {
+		hqgologger.Fatal("failed opening results store!", hqgologger.WithError(err), hqgologger.WithString("file", queryStoreFilePath))
+	}
+
+	db, err := store.Open(queryStoreFilePath)
+	if err != nil {
+		hqgologger.Fatal("failed opening results store!", hqgologger.WithError(err), hqgologger.WithString("file", queryStoreFilePath))
+	}
+
+	defer db.Close()
+
+	hosts, err := db.Query(q)
+	if err != nil {
+		hqgologger.Error("failed querying results store!", hqgologger.WithError(err))
+
+		return
+	}
+
+	if queryInJSONL {
+		enc := json.NewEncoder(os.Stdout)
+
+		for _, host := range hosts {
+			if err := enc.Encode(newHostForJSONL(host)); err != nil {
+				hqgologger.Error("error writing result!", hqgologger.WithError(err))
+			}
+		}
+
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	for _, host := range hosts {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", host.Subdomain, host.FirstRun.Format(time.RFC3339), host.Runs, strings.Join(host.Sources, ","))
+	}
+
+	tw.Flush()
+}
+
+// parseDate parses a date given as "2006-01-02", in RFC 3339, or as an age relative to
+// now accepted by parseAge, e.g. "30d".
+func parseDate(value string) (t time.Time, err error) {
+	if t, err = time.Parse(time.DateOnly, value); err == nil {
+		return
+	}
+
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return
+	}
+
+	var age time.Duration
+
+	age, err = parseAge(value)
+	if err != nil {
+		err = fmt.Errorf("%q is neither a date nor an age", value)
+
+		return
+	}
+
+	t = time.Now().Add(-age)
+
+	return
+}
+
+// hostForJSONL is the JSONL representation of a recorded subdomain.
+type hostForJSONL struct {
+	Domain    string   `json:"domain"`
+	Subdomain string   `json:"subdomain"`
+	FirstRun  string   `json:"first_run"`
+	LastRun   string   `json:"last_run"`
+	Runs      int      `json:"runs"`
+	Sources   []string `json:"sources"`
+	FirstSeen string   `json:"first_seen,omitempty"`
+	LastSeen  string   `json:"last_seen,omitempty"`
+	Score     float64  `json:"score,omitempty"`
+}
+
+func newHostForJSONL(host store.Host) (data hostForJSONL) {
+	data = hostForJSONL{
+		Domain:    host.Domain,
+		Subdomain: host.Subdomain,
+		FirstRun:  host.FirstRun.Format(time.RFC3339),
+		LastRun:   host.LastRun.Format(time.RFC3339),
+		Runs:      host.Runs,
+		Sources:   host.Sources,
+		Score:     host.Score,
+	}
+
+	if !host.FirstSeen.IsZero() {
+		data.FirstSeen = host.FirstSeen.Format(time.RFC3339)
+	}
+
+	if !host.LastSeen.IsZero() {
+		data.LastSeen = host.LastSeen.Format(time.RFC3339)
+	}
+
+	return
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+)
+
+// batchPayload is what the queue keeps of a batch job.
+//
+// Fields:
+//   - Domain (string): The domain to enumerate.
+//   - Batch (string): The identity of the batch, as returned by batchIdentity, so that
+//     jobs are only resumed, and results replayed, for the same input and options.
+//   - Run (string): The identifier of the run of the batch that queued the job. The run
+//     of an interrupted batch is continued by the next run of the same batch.
+type batchPayload struct {
+	Domain string `json:"domain"`
+	Batch  string `json:"batch"`
+	Run    string `json:"run"`
+}
+
+// batchJob is a queued domain of a batch.
+type batchJob struct {
+	batchPayload
+
+	queue.Job
+}
+
+// openQueue opens the job queue in the directory at path, keeping finished jobs for the
+// retention period of cfg.
+func openQueue(path string, cfg queue.Configuration) (q *queue.Queue, err error) {
+	retention, err := queue.ParseRetention(cfg.Retention)
+	if err != nil {
+		return
+	}
+
+	q, err = queue.Open(path, retention)
+
+	return
+}
+
+// findQueued enumerates the domains received on domains as FindFrom does, keeping each
+// of them as a job in q, so that an interrupted batch can be resumed: if the last run of
+// the same batch was interrupted, its pending jobs are enumerated first, and the domains
+// whose job completed are not enumerated again, their results being replayed from q
+// instead. Once every domain is enumerated, the jobs of the batch are removed from q, so
+// that later runs of the batch enumerate every domain again.
+//
+// Parameters:
+//   - finder (*xsubfind3r.Finder): The finder.
+//   - q (*queue.Queue): The queue.
+//   - domains (<-chan string): The target domains.
+//   - concurrency (int): The maximum number of domains enumerated concurrently.
+//   - batch (string): The identity of the batch, as returned by batchIdentity.
+//   - options (...xsubfind3r.FindOption): Per-call overrides, applied to every domain.
+//
+// Returns:
+//   - results (chan xsubfind3r.DomainResult): A channel that streams the results of every
+//     domain, each followed by the end marker of the domain, as with
+//     xsubfind3r.WithDomainEnds.
+func findQueued(finder *xsubfind3r.Finder, q *queue.Queue, domains <-chan string, concurrency int, batch string, options ...xsubfind3r.FindOption) (results chan xsubfind3r.DomainResult) {
+	results = make(chan xsubfind3r.DomainResult)
+
+	jobs := make(chan batchJob)
+
+	// ids are the jobs of the batch, removed once it completes. They are written by the
+	// goroutine queuing the jobs, and read once it is done.
+	var ids []string
+
+	go func() {
+		defer close(jobs)
+
+		var stored []batchJob
+
+		run := ""
+
+		for _, job := range q.Jobs(queue.KindBatch) {
+			var payload batchPayload
+
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				hqgologger.Error("failed reading queued job!", hqgologger.WithError(err), hqgologger.WithString("job", job.ID))
+
+				continue
+			}
+
+			if payload.Batch != batch {
+				continue
+			}
+
+			ids = append(ids, job.ID)
+
+			stored = append(stored, batchJob{batchPayload: payload, Job: job})
+
+			// Jobs are in the order they were queued: the last run is the interrupted one.
+			run = payload.Run
+		}
+
+		if run == "" {
+			var err error
+
+			run, err = queue.NewID()
+			if err != nil {
+				hqgologger.Fatal("failed queuing batch!", hqgologger.WithError(err))
+			}
+		}
+
+		latest := map[string]queue.Job{}
+
+		for _, job := range stored {
+			if job.Run != run {
+				continue
+			}
+
+			latest[job.Domain] = job.Job
+
+			if job.State == queue.StatePending {
+				hqgologger.Info(fmt.Sprintf("Resuming subdomains finding for %v...", au.Underline(job.Domain).Bold()))
+
+				jobs <- job
+			}
+		}
+
+		for domain := range domains {
+			payload := batchPayload{
+				Domain: domain,
+				Batch:  batch,
+				Run:    run,
+			}
+
+			job, ok := latest[domain]
+
+			switch {
+			case ok && job.State == queue.StatePending:
+				// Already resumed.
+				continue
+			case ok && job.State == queue.StateCompleted:
+			default:
+				id, err := queue.NewID()
+				if err != nil {
+					hqgologger.Error("failed queuing domain!", hqgologger.WithError(err), hqgologger.WithString("domain", domain))
+
+					continue
+				}
+
+				job, err = q.Push(id, queue.KindBatch, payload)
+				if err != nil {
+					hqgologger.Error("failed queuing domain!", hqgologger.WithError(err), hqgologger.WithString("domain", domain))
+
+					continue
+				}
+
+				ids = append(ids, id)
+
+				latest[domain] = job
+			}
+
+			jobs <- batchJob{batchPayload: payload, Job: job}
+		}
+	}()
+
+	go func() {
+		defer close(results)
+
+		wg := &sync.WaitGroup{}
+
+		for range max(concurrency, 1) {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for job := range jobs {
+					if job.State != queue.StateCompleted || !replay(q, job, results) {
+						enumerate(finder, q, job, results, options...)
+					}
+
+					results <- xsubfind3r.DomainResult{
+						Domain: job.Domain,
+						End:    true,
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		for _, id := range ids {
+			if err := q.Remove(id); err != nil {
+				hqgologger.Error("failed removing job!", hqgologger.WithError(err), hqgologger.WithString("job", id))
+			}
+		}
+	}()
+
+	return
+}
+
+// replay sends the results of a completed job on results, reporting whether they could
+// be read.
+func replay(q *queue.Queue, job batchJob, results chan<- xsubfind3r.DomainResult) (ok bool) {
+	stored, err := q.Results(job.ID)
+	if err != nil {
+		hqgologger.Error("failed reading queued results, finding again!", hqgologger.WithError(err), hqgologger.WithString("domain", job.Domain))
+
+		return
+	}
+
+	hqgologger.Debug("replaying queued results...", hqgologger.WithString("domain", job.Domain))
+
+	for _, result := range stored {
+		results <- xsubfind3r.DomainResult{
+			Domain: job.Domain,
+			Result: result,
+		}
+	}
+
+	ok = true
+
+	return
+}
+
+// enumerate runs a job, recording its results in q as they are sent on results.
+func enumerate(finder *xsubfind3r.Finder, q *queue.Queue, job batchJob, results chan<- xsubfind3r.DomainResult, options ...xsubfind3r.FindOption) {
+	persist := true
+
+	if err := q.Start(job.ID); err != nil {
+		hqgologger.Error("failed persisting job!", hqgologger.WithError(err), hqgologger.WithString("domain", job.Domain))
+
+		persist = false
+	}
+
+	for result := range finder.Find(job.Domain, options...) {
+		if persist {
+			if err := q.Append(job.ID, result); err != nil {
+				hqgologger.Error("failed persisting result!", hqgologger.WithError(err), hqgologger.WithString("domain", job.Domain))
+
+				persist = false
+			}
+		}
+
+		results <- xsubfind3r.DomainResult{
+			Domain: job.Domain,
+			Result: result,
+		}
+	}
+
+	if !persist {
+		return
+	}
+
+	if err := q.Finish(job.ID, queue.StateCompleted); err != nil {
+		hqgologger.Error("failed persisting job!", hqgologger.WithError(err), hqgologger.WithString("domain", job.Domain))
+	}
+}
+
+// batchIdentity returns the identity of a batch: a digest of the options affecting its
+// results, as returned by batchOptions, and of its input.
+func batchIdentity(fingerprint string, input []string) (batch string) {
+	hash := sha256.New()
+
+	fmt.Fprintln(hash, fingerprint)
+
+	for _, line := range input {
+		fmt.Fprintln(hash, line)
+	}
+
+	batch = hex.EncodeToString(hash.Sum(nil))
+
+	return
+}
+
+// batchOptions returns a description of the options affecting the results of a batch.
+func batchOptions() (fingerprint string) {
+	fingerprint = fmt.Sprintf(
+		"sources=%v exclude=%v include=%v max-age=%s exclude-expired=%t min-score=%g",
+		sourcesToUse, sourcesToExclude, resultTypes, maxAge, excludeExpired, minScore,
+	)
+
+	return
+}
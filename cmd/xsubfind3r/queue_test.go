@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// newQueueFinder returns a finder reading a subdomain of every domain of the tests from a
+// hosts file, and counting the domains it enumerates with a Certificate Transparency log
+// queried once per domain.
+func newQueueFinder(t *testing.T) (finder *xsubfind3r.Finder, enumerated *atomic.Int64) {
+	t.Helper()
+
+	enumerated = &atomic.Int64{}
+
+	log := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		enumerated.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		fmt.Fprint(w, `{"tree_size":0}`)
+	}))
+
+	t.Cleanup(log.Close)
+
+	hosts := filepath.Join(t.TempDir(), "hosts")
+
+	if err := os.WriteFile(hosts, []byte("127.0.0.1 a.example.com\n127.0.0.1 a.example.org\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	finder, err := xsubfind3r.New(&xsubfind3r.Configuration{
+		SourcesToUSe: []string{sources.CTLOGS, sources.HOSTSFILE},
+		CTLogs: sources.CTLogsConfiguration{
+			Logs: []sources.CTLogConfiguration{{URL: log.URL + "/"}},
+		},
+		Imports: sources.ImportsConfiguration{
+			Hosts: []string{hosts},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// runBatch runs a queued batch of domains, and returns the subdomains it output.
+func runBatch(finder *xsubfind3r.Finder, q *queue.Queue, batch string, domains ...string) (found []string) {
+	input := make(chan string)
+
+	go func() {
+		defer close(input)
+
+		for _, domain := range domains {
+			input <- domain
+		}
+	}()
+
+	for result := range findQueued(finder, q, input, 1, batch, xsubfind3r.WithDomainEnds()) {
+		if !result.End && result.Type == sources.ResultSubdomain {
+			found = append(found, result.Value)
+		}
+	}
+
+	slices.Sort(found)
+
+	return
+}
+
+// queueJob queues a job of domain for the run of batch, in state.
+func queueJob(t *testing.T, q *queue.Queue, batch, run, domain string, state queue.State, results ...sources.Result) {
+	t.Helper()
+
+	id, err := queue.NewID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = q.Push(id, queue.KindBatch, batchPayload{Domain: domain, Batch: batch, Run: run}); err != nil {
+		t.Fatal(err)
+	}
+
+	if state == queue.StatePending {
+		return
+	}
+
+	if err = q.Start(id); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, result := range results {
+		if err = q.Append(id, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = q.Finish(id, state); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestFindQueuedBatch checks that only the interrupted run of the same batch is resumed,
+// and that completed batches are enumerated anew.
+func TestFindQueuedBatch(t *testing.T) {
+	finder, enumerated := newQueueFinder(t)
+
+	q, err := queue.Open(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer q.Close()
+
+	domains := []string{"example.com", "example.org"}
+
+	batch := batchIdentity(batchOptions(), domains)
+
+	if found := runBatch(finder, q, batch, domains...); !slices.Equal(found, []string{"a.example.com", "a.example.org"}) {
+		t.Fatalf("got %v", found)
+	}
+
+	if jobs := q.Jobs(queue.KindBatch); len(jobs) != 0 {
+		t.Errorf("got %d jobs once the batch completed, want them removed", len(jobs))
+	}
+
+	// A completed batch is enumerated again rather than replayed.
+	runBatch(finder, q, batch, domains...)
+
+	if enumerated.Load() != 4 {
+		t.Errorf("got %d domains enumerated running a batch twice, want 4", enumerated.Load())
+	}
+
+	// The run of the batch was interrupted once example.com completed, while an older run
+	// and another batch completed example.org.
+	replayed := sources.Result{Type: sources.ResultSubdomain, Source: sources.HOSTSFILE, Value: "replayed.example.com"}
+	stale := sources.Result{Type: sources.ResultSubdomain, Source: sources.HOSTSFILE, Value: "stale.example.org"}
+
+	queueJob(t, q, batch, "older", "example.org", queue.StateCompleted, stale)
+	queueJob(t, q, batchIdentity(batchOptions(), []string{"example.org"}), "other", "example.org", queue.StateCompleted, stale)
+	queueJob(t, q, batch, "interrupted", "example.com", queue.StateCompleted, replayed)
+	queueJob(t, q, batch, "interrupted", "example.org", queue.StatePending)
+
+	enumerated.Store(0)
+
+	if found := runBatch(finder, q, batch, domains...); !slices.Equal(found, []string{"a.example.org", "replayed.example.com"}) {
+		t.Errorf("got %v, want example.com replayed and example.org enumerated", found)
+	}
+
+	if enumerated.Load() != 1 {
+		t.Errorf("got %d domains enumerated resuming the batch, want 1", enumerated.Load())
+	}
+
+	if jobs := q.Jobs(queue.KindBatch); len(jobs) != 1 {
+		t.Errorf("got %d jobs, want those of the other batch only", len(jobs))
+	}
+}
+
+func TestBatchIdentity(t *testing.T) {
+	a := batchIdentity("sources=[crtsh]", []string{"example.com", "example.org"})
+
+	for _, b := range []string{
+		batchIdentity("sources=[otx]", []string{"example.com", "example.org"}),
+		batchIdentity("sources=[crtsh]", []string{"example.com"}),
+		batchIdentity("sources=[crtsh]", []string{"example.comexample.org"}),
+	} {
+		if a == b {
+			t.Errorf("batches of different inputs or options share identity %s", a)
+		}
+	}
+
+	if a != batchIdentity("sources=[crtsh]", []string{"example.com", "example.org"}) {
+		t.Error("identity of the same batch differs")
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	hqgologgerformatter "github.com/hueristiq/hq-go-logger/formatter"
+	hqgologgerlevels "github.com/hueristiq/hq-go-logger/levels"
+	"github.com/hueristiq/xsubfind3r/internal/configuration"
+	"github.com/hueristiq/xsubfind3r/internal/server"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/logrusorgru/aurora/v4"
+	"github.com/spf13/pflag"
+)
+
+// isServe reports whether the program was invoked in server mode, as `xsubfind3r serve`.
+func isServe() bool {
+	return len(os.Args) > 1 && os.Args[1] == "serve"
+}
+
+// serve runs the HTTP API until interrupted, as configured by args.
+func serve(args []string) {
+	var (
+		serveConfigurationFilePath string
+		serveListen                string
+		serveConcurrency           int
+		serveQueuePath             string
+		serveTLSCert               string
+		serveTLSKey                string
+		serveSourcesToUse          []string
+		serveSourcesToExclude      []string
+		serveExcludeExpired        bool
+		serveMonochrome            bool
+		serveSilent                bool
+		serveVerbose               bool
+	)
+
+	flags := pflag.NewFlagSet("serve", pflag.ExitOnError)
+
+	flags.StringVarP(&serveConfigurationFilePath, "configuration", "c", configuration.DefaultConfigurationFilePath, "")
+	flags.StringVar(&serveListen, "listen", "127.0.0.1:8080", "")
+	flags.IntVarP(&serveConcurrency, "concurrency", "C", 5, "")
+	flags.StringVar(&serveQueuePath, "queue", "", "")
+	flags.Lookup("queue").NoOptDefVal = configuration.DefaultQueuePath
+	flags.StringVar(&serveTLSCert, "tls-cert", "", "")
+	flags.StringVar(&serveTLSKey, "tls-key", "", "")
+	flags.StringSliceVarP(&serveSourcesToUse, "sources-to-use", "u", []string{}, "")
+	flags.StringSliceVarP(&serveSourcesToExclude, "sources-to-exclude", "e", []string{}, "")
+	flags.BoolVar(&serveExcludeExpired, "exclude-expired", false, "")
+	flags.BoolVarP(&serveMonochrome, "monochrome", "m", false, "")
+	flags.BoolVarP(&serveSilent, "silent", "s", false, "")
+	flags.BoolVarP(&serveVerbose, "verbose", "v", false, "")
+
+	flags.Usage = func() {
+		hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+		h := "USAGE:\n"
+		h += fmt.Sprintf(" %s serve [OPTIONS]\n", configuration.NAME)
+
+		h += "\nCONFIGURATION:\n"
+
+		defaultConfigurationFilePath := strings.ReplaceAll(configuration.DefaultConfigurationFilePath, configuration.UserDotConfigDirectoryPath, "$HOME/.config")
+
+		h += fmt.Sprintf(" -c, --configuration string           (default: %v)\n", au.Underline(defaultConfigurationFilePath).Bold())
+
+		h += "\nSERVER:\n"
+		h += "     --listen string                  address to listen on (default: 127.0.0.1:8080)\n"
+		h += " -C, --concurrency int                number of jobs to run concurrently, others are queued (default: 5)\n"
+
+		defaultQueuePath := strings.ReplaceAll(configuration.DefaultQueuePath, configuration.UserDotConfigDirectoryPath, "$HOME/.config")
+
+		h += fmt.Sprintf("     --queue string                   persist jobs and results in a directory, resuming them on restart (default: %v)\n", au.Underline(defaultQueuePath).Bold())
+		h += "     --tls-cert string                serve HTTPS with this PEM certificate (chain), requires --tls-key\n"
+		h += "     --tls-key string                 PEM private key of --tls-cert\n"
+
+		h += "\nSOURCES:\n"
+		h += " -u, --sources-to-use string[]        comma(,) separated sources jobs may use\n"
+		h += " -e, --sources-to-exclude string[]    comma(,) separated sources jobs may not use\n"
+		h += "     --exclude-expired bool           drop names found only in expired certificates\n"
+
+		h += "\nOUTPUT:\n"
+		h += " -m, --monochrome bool                stdout in monochrome\n"
+		h += " -s, --silent bool                    stdout in silent mode\n"
+		h += " -v, --verbose bool                   stdout in verbose mode\n"
+
+		hqgologger.Info(h, hqgologger.WithLabel(""))
+		hqgologger.Print("")
+	}
+
+	_ = flags.Parse(args)
+
+	hqgologger.DefaultLogger.SetFormatter(
+		hqgologgerformatter.NewConsoleFormatter(&hqgologgerformatter.ConsoleFormatterConfiguration{
+			Colorize: !serveMonochrome,
+		}),
+	)
+
+	if serveSilent {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelSilent)
+	}
+
+	if serveVerbose {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelDebug)
+	}
+
+	au = aurora.New(aurora.WithColors(!serveMonochrome))
+
+	hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+	if err := configuration.CreateOrUpdate(serveConfigurationFilePath); err != nil {
+		hqgologger.Fatal("failed creating or updating Configuration!", hqgologger.WithError(err))
+	}
+
+	cfg, err := loadConfiguration(serveConfigurationFilePath)
+	if err != nil {
+		hqgologger.Fatal("failed reading in Configuration!", hqgologger.WithError(err))
+	}
+
+	finderCFG := newFinderConfiguration(cfg)
+
+	finderCFG.SourcesToUSe = serveSourcesToUse
+	finderCFG.SourcesToExclude = serveSourcesToExclude
+	finderCFG.ExcludeExpired = serveExcludeExpired
+
+	finder, err := xsubfind3r.New(finderCFG)
+	if err != nil {
+		hqgologger.Fatal("failed creating finder!", hqgologger.WithError(err))
+	}
+
+	s, err := server.New(finder, serveConcurrency, cfg.Server.Tenants)
+	if err != nil {
+		hqgologger.Fatal("failed creating server!", hqgologger.WithError(err))
+	}
+
+	if len(cfg.Server.Tenants) == 0 {
+		hqgologger.Warn("no tenants configured, the API is open to anyone reaching it!")
+	}
+
+	secure := checkTLS(serveListen, serveTLSCert, serveTLSKey, len(cfg.Server.Tenants) > 0)
+
+	if serveQueuePath != "" {
+		q, err := openQueue(serveQueuePath, cfg.Queue)
+		if err != nil {
+			hqgologger.Fatal("failed opening job queue!", hqgologger.WithError(err), hqgologger.WithString("queue", serveQueuePath))
+		}
+
+		defer q.Close()
+
+		if err := s.Resume(q); err != nil {
+			hqgologger.Fatal("failed resuming jobs!", hqgologger.WithError(err), hqgologger.WithString("queue", serveQueuePath))
+		}
+	}
+
+	srv := &http.Server{
+		Addr:              serveListen,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		stop()
+
+		hqgologger.Info("stopping, cancelling jobs...")
+
+		// Cancelling the jobs ends the streams following them, so that the shutdown does not
+		// wait on them.
+		s.Close()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			hqgologger.Error("failed shutting down!", hqgologger.WithError(err))
+		}
+	}()
+
+	hqgologger.Info(fmt.Sprintf("listening on %v...", au.Underline(serveListen).Bold()))
+
+	if secure {
+		err = srv.ListenAndServeTLS(serveTLSCert, serveTLSKey)
+	} else {
+		err = srv.ListenAndServe()
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		hqgologger.Fatal("failed serving!", hqgologger.WithError(err))
+	}
+
+	<-ctx.Done()
+
+	s.Close()
+
+	hqgologger.Info("stopped serving.")
+}
+
+// checkTLS checks the TLS flags of a server listening on listen: the certificate and key
+// are given together or not at all. Without them, and unless listen is a loopback
+// address, it warns that the bearer tokens of tenants, if any, travel in plaintext.
+//
+// Parameters:
+//   - listen (string): The address the server listens on.
+//   - cert (string): The path of the certificate, from --tls-cert.
+//   - key (string): The path of the private key, from --tls-key.
+//   - tenants (bool): Whether tenants authenticate with bearer tokens.
+//
+// Returns:
+//   - secure (bool): Whether the server is to serve TLS.
+func checkTLS(listen, cert, key string, tenants bool) (secure bool) {
+	if (cert == "") != (key == "") {
+		hqgologger.Fatal("--tls-cert and --tls-key must be given together!")
+	}
+
+	secure = cert != ""
+
+	if secure || !tenants || isLoopback(listen) {
+		return
+	}
+
+	hqgologger.Warn("serving without TLS beyond loopback, tenant tokens travel in plaintext: use --tls-cert and --tls-key, or a TLS terminator!", hqgologger.WithString("address", listen))
+
+	return
+}
+
+// isLoopback reports whether the address listen binds to loopback only.
+func isLoopback(listen string) (ok bool) {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return
+	}
+
+	if host == "localhost" {
+		ok = true
+
+		return
+	}
+
+	ip := net.ParseIP(host)
+
+	ok = ip != nil && ip.IsLoopback()
+
+	return
+}
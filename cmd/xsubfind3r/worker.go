@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	hqgologgerformatter "github.com/hueristiq/hq-go-logger/formatter"
+	hqgologgerlevels "github.com/hueristiq/hq-go-logger/levels"
+	"github.com/hueristiq/xsubfind3r/internal/cluster"
+	"github.com/hueristiq/xsubfind3r/internal/configuration"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"github.com/logrusorgru/aurora/v4"
+	"github.com/spf13/pflag"
+)
+
+// isWorker reports whether the program was invoked in worker mode, as `xsubfind3r worker`.
+func isWorker() bool {
+	return len(os.Args) > 1 && os.Args[1] == "worker"
+}
+
+// work runs the jobs of a coordinator until it has none left, or until interrupted, as
+// configured by args.
+func work(args []string) {
+	var (
+		workerConfigurationFilePath string
+		workerCoordinator           string
+		workerName                  string
+		workerConcurrency           int
+		workerMonochrome            bool
+		workerSilent                bool
+		workerVerbose               bool
+	)
+
+	host, _ := os.Hostname()
+
+	flags := pflag.NewFlagSet("worker", pflag.ExitOnError)
+
+	flags.StringVarP(&workerConfigurationFilePath, "configuration", "c", configuration.DefaultConfigurationFilePath, "")
+	flags.StringVar(&workerCoordinator, "coordinator", "", "")
+	flags.StringVar(&workerName, "name", cluster.DefaultWorkerName(host, os.Getpid()), "")
+	flags.IntVarP(&workerConcurrency, "concurrency", "C", 5, "")
+	flags.BoolVarP(&workerMonochrome, "monochrome", "m", false, "")
+	flags.BoolVarP(&workerSilent, "silent", "s", false, "")
+	flags.BoolVarP(&workerVerbose, "verbose", "v", false, "")
+
+	flags.Usage = func() {
+		hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+		h := "USAGE:\n"
+		h += fmt.Sprintf(" %s worker [OPTIONS]\n", configuration.NAME)
+
+		h += "\nCONFIGURATION:\n"
+
+		defaultConfigurationFilePath := strings.ReplaceAll(configuration.DefaultConfigurationFilePath, configuration.UserDotConfigDirectoryPath, "$HOME/.config")
+
+		h += fmt.Sprintf(" -c, --configuration string           (default: %v)\n", au.Underline(defaultConfigurationFilePath).Bold())
+
+		h += "\nWORKER:\n"
+		h += "     --coordinator string             URL of the coordinator, e.g. http://10.0.0.1:9090\n"
+		h += "     --name string                    name identifying the worker to the coordinator (default: <hostname>-<pid>)\n"
+		h += " -C, --concurrency int                number of jobs to run concurrently (default: 5)\n"
+
+		h += "\n Sources, result types and filters are set by the coordinator; API keys are\n"
+		h += " read from the worker's own configuration.\n"
+
+		h += "\nOUTPUT:\n"
+		h += " -m, --monochrome bool                stdout in monochrome\n"
+		h += " -s, --silent bool                    stdout in silent mode\n"
+		h += " -v, --verbose bool                   stdout in verbose mode\n"
+
+		hqgologger.Info(h, hqgologger.WithLabel(""))
+		hqgologger.Print("")
+	}
+
+	_ = flags.Parse(args)
+
+	hqgologger.DefaultLogger.SetFormatter(
+		hqgologgerformatter.NewConsoleFormatter(&hqgologgerformatter.ConsoleFormatterConfiguration{
+			Colorize: !workerMonochrome,
+		}),
+	)
+
+	if workerSilent {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelSilent)
+	}
+
+	if workerVerbose {
+		hqgologger.DefaultLogger.SetLevel(hqgologgerlevels.LevelDebug)
+	}
+
+	au = aurora.New(aurora.WithColors(!workerMonochrome))
+
+	hqgologger.Info(configuration.BANNER(au), hqgologger.WithLabel(""))
+
+	if workerCoordinator == "" {
+		hqgologger.Fatal("no coordinator, use `--coordinator`!")
+	}
+
+	if err := configuration.CreateOrUpdate(workerConfigurationFilePath); err != nil {
+		hqgologger.Fatal("failed creating or updating Configuration!", hqgologger.WithError(err))
+	}
+
+	cfg, err := loadConfiguration(workerConfigurationFilePath)
+	if err != nil {
+		hqgologger.Fatal("failed reading in Configuration!", hqgologger.WithError(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	defer stop()
+
+	worker := cluster.NewWorker(workerCoordinator, cfg.Cluster.Token, workerName)
+
+	settings, err := worker.Settings(ctx)
+	if err != nil {
+		hqgologger.Fatal("failed fetching settings from coordinator!", hqgologger.WithError(err), hqgologger.WithString("coordinator", workerCoordinator))
+	}
+
+	types := []sources.ResultType{}
+
+	for _, name := range settings.Include {
+		t, err := sources.ParseResultType(name)
+		if err != nil {
+			hqgologger.Fatal("invalid result type!", hqgologger.WithError(err))
+		}
+
+		types = append(types, t)
+	}
+
+	finderCFG := newFinderConfiguration(cfg)
+
+	finderCFG.SourcesToUSe = settings.Sources
+	finderCFG.SourcesToExclude = settings.Exclude
+	finderCFG.ResultTypes = types
+	finderCFG.ExcludeExpired = settings.ExcludeExpired
+
+	finder, err := xsubfind3r.New(finderCFG)
+	if err != nil {
+		hqgologger.Fatal("failed creating finder!", hqgologger.WithError(err))
+	}
+
+	// Reports let the coordinator record every source that reported a subdomain.
+	options := []xsubfind3r.FindOption{
+		xsubfind3r.WithReports(),
+	}
+
+	if settings.MaxAge != "" {
+		age, err := parseAge(settings.MaxAge)
+		if err != nil {
+			hqgologger.Fatal("invalid maximum age!", hqgologger.WithError(err))
+		}
+
+		options = append(options, xsubfind3r.WithMaxAge(age))
+	}
+
+	if settings.MinScore > 0 {
+		options = append(options, xsubfind3r.WithMinScore(settings.MinScore))
+	}
+
+	hqgologger.Info(fmt.Sprintf("working for %v as %v...", au.Underline(workerCoordinator).Bold(), au.Underline(workerName).Bold()))
+
+	if err := worker.Run(ctx, finder, workerConcurrency, options...); err != nil {
+		hqgologger.Fatal("failed working!", hqgologger.WithError(err), hqgologger.WithString("coordinator", workerCoordinator))
+	}
+
+	if ctx.Err() != nil {
+		hqgologger.Info("stopped working, unfinished jobs will be reassigned.")
+
+		return
+	}
+
+	hqgologger.Info("coordinator has no jobs left, stopped working.")
+}
@@ -0,0 +1,226 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// coordinate starts c with domains to hand out, and serves its API.
+func coordinate(t *testing.T, c *Coordinator, domains ...string) (server *httptest.Server, results chan xsubfind3r.DomainResult) {
+	t.Helper()
+
+	server = httptest.NewServer(c.Handler())
+
+	t.Cleanup(server.Close)
+
+	queued := make(chan string, len(domains))
+
+	for _, domain := range domains {
+		queued <- domain
+	}
+
+	close(queued)
+
+	results = c.Run(queued)
+
+	return
+}
+
+// post posts request, in JSON, to path of server, decoding a 200 response into response
+// if it is not nil, and returns the response status.
+func post(t *testing.T, server *httptest.Server, path string, request, response any) (status int) {
+	t.Helper()
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.Post(server.URL+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	if response != nil && res.StatusCode == http.StatusOK {
+		if err = json.NewDecoder(res.Body).Decode(response); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	status = res.StatusCode
+
+	return
+}
+
+// TestWorkers runs a coordinator with several workers, each running several jobs at
+// once, and checks the results of every domain are merged once.
+func TestWorkers(t *testing.T) {
+	domains := []string{}
+	hosts := &strings.Builder{}
+
+	for i := range 12 {
+		domain := fmt.Sprintf("example%d.com", i)
+
+		domains = append(domains, domain)
+
+		fmt.Fprintf(hosts, "127.0.0.1 a.%s\n127.0.0.2 b.%s\n", domain, domain)
+	}
+
+	path := filepath.Join(t.TempDir(), "hosts")
+
+	if err := os.WriteFile(path, []byte(hosts.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server, results := coordinate(t, NewCoordinator(Settings{}, "secret", time.Second), domains...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+
+	for i := range 3 {
+		finder, err := xsubfind3r.New(&xsubfind3r.Configuration{
+			SourcesToUSe: []string{sources.HOSTSFILE},
+			Imports: sources.ImportsConfiguration{
+				Hosts: []string{path},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			worker := NewWorker(server.URL, "secret", fmt.Sprintf("worker-%d", i))
+
+			if err := worker.Run(ctx, finder, 2); err != nil {
+				t.Errorf("worker-%d: %v", i, err)
+			}
+		}()
+	}
+
+	merged := map[string][]string{}
+	ends := map[string]int{}
+
+	for result := range results {
+		switch {
+		case result.End:
+			ends[result.Domain]++
+		case result.Type == sources.ResultSubdomain:
+			merged[result.Domain] = append(merged[result.Domain], result.Value)
+		case result.Type == sources.ResultError:
+			t.Errorf("%s: %v", result.Domain, result.Error)
+		}
+	}
+
+	// Workers stop once they learn every job has completed.
+	wg.Wait()
+
+	for _, domain := range domains {
+		slices.Sort(merged[domain])
+
+		if want := []string{"a." + domain, "b." + domain}; !slices.Equal(merged[domain], want) {
+			t.Errorf("%s: got %v, want %v", domain, merged[domain], want)
+		}
+
+		if ends[domain] != 1 {
+			t.Errorf("%s: merged %d times, want once", domain, ends[domain])
+		}
+	}
+}
+
+// TestReap checks that an expired lease is returned to the pending jobs although no
+// worker asks for a job.
+func TestReap(t *testing.T) {
+	c := NewCoordinator(Settings{}, "", 300*time.Millisecond)
+
+	server, _ := coordinate(t, c, "example.com")
+
+	var assignment Assignment
+
+	if status := post(t, server, "/v1/cluster/lease", leaseRequest{Worker: "lost"}, &assignment); status != http.StatusOK {
+		t.Fatalf("got status %d leasing a job", status)
+	}
+
+	time.Sleep(time.Second)
+
+	c.mu.Lock()
+
+	pending := len(c.pending)
+
+	c.mu.Unlock()
+
+	if pending != 1 {
+		t.Errorf("got %d pending jobs once the lease expired, want 1", pending)
+	}
+}
+
+// TestResultsDone checks that completing a job is acknowledged before its results are
+// merged, and again should the worker push its last results again.
+func TestResultsDone(t *testing.T) {
+	c := NewCoordinator(Settings{}, "", time.Minute)
+
+	server, results := coordinate(t, c, "example.com")
+
+	var assignment Assignment
+
+	if status := post(t, server, "/v1/cluster/lease", leaseRequest{Worker: "worker"}, &assignment); status != http.StatusOK {
+		t.Fatalf("got status %d leasing a job", status)
+	}
+
+	path := "/v1/cluster/jobs/" + assignment.ID + "/results"
+
+	request := resultsRequest{
+		Worker:  "worker",
+		Attempt: assignment.Attempt,
+		Results: []queue.Record{
+			queue.NewRecord(sources.Result{Type: sources.ResultSubdomain, Source: sources.CRTSH, Value: "a.example.com"}),
+		},
+		Done: true,
+	}
+
+	// Nothing takes the results yet.
+	for range 2 {
+		if status := post(t, server, path, request, nil); status != http.StatusNoContent {
+			t.Fatalf("got status %d completing the job", status)
+		}
+	}
+
+	request.Worker = "other"
+
+	if status := post(t, server, path, request, nil); status != http.StatusConflict {
+		t.Errorf("got status %d completing the job of another worker", status)
+	}
+
+	merged := []xsubfind3r.DomainResult{}
+
+	for result := range results {
+		merged = append(merged, result)
+	}
+
+	if len(merged) != 2 || merged[0].Value != "a.example.com" || !merged[1].End {
+		t.Errorf("got %+v, want the result merged once", merged)
+	}
+}
@@ -0,0 +1,457 @@
+package cluster
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Coordinator hands out the domains it receives as jobs to workers, and merges their
+// results. It is safe for concurrent use.
+//
+// Fields:
+//   - settings (Settings): The settings jobs are run with.
+//   - digest ([sha256.Size]byte): The digest of the token of the cluster.
+//   - secured (bool): Whether requests must carry the token.
+//   - timeout (time.Duration): How long leases last without being renewed.
+//   - results (chan xsubfind3r.DomainResult): The merged results.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - jobs (map[string]*job): The jobs, keyed by identifier.
+//   - pending ([]*job): The jobs waiting for a worker, oldest first.
+//   - remaining (int): The number of jobs not yet merged.
+//   - closed (bool): Whether every domain has been received.
+//   - finished (bool): Whether every job has been merged.
+type Coordinator struct {
+	settings Settings
+	digest   [sha256.Size]byte
+	secured  bool
+	timeout  time.Duration
+	results  chan xsubfind3r.DomainResult
+
+	mu        sync.Mutex
+	jobs      map[string]*job
+	pending   []*job
+	remaining int
+	closed    bool
+	finished  bool
+}
+
+// job is a domain to enumerate.
+//
+// Fields:
+//   - id (string): The identifier of the job.
+//   - domain (string): The domain.
+//   - worker (string): The worker holding the current lease, or "" if none. Once the job
+//     has completed, the worker that completed it.
+//   - attempt (int): The number of the current, or last, lease.
+//   - deadline (time.Time): When the current lease expires.
+//   - results ([]sources.Result): The results pushed under the current lease.
+//   - done (bool): Whether the job has completed.
+type job struct {
+	id       string
+	domain   string
+	worker   string
+	attempt  int
+	deadline time.Time
+	results  []sources.Result
+	done     bool
+}
+
+// Run hands out the domains received on domains as jobs, until domains is closed and
+// every job has completed.
+//
+// Parameters:
+//   - domains (<-chan string): The domains to enumerate.
+//
+// Returns:
+//   - results (chan xsubfind3r.DomainResult): The results of every job, as it completes,
+//     each followed by the end marker of its domain, as with xsubfind3r.WithDomainEnds.
+//     The channel is closed once every job has completed.
+func (c *Coordinator) Run(domains <-chan string) (results chan xsubfind3r.DomainResult) {
+	results = c.results
+
+	// Leases expire whether or not workers are still asking for jobs, e.g. when the last
+	// worker of the cluster crashes.
+	go func() {
+		ticker := time.NewTicker(c.timeout / 3)
+
+		defer ticker.Stop()
+
+		for range ticker.C {
+			c.mu.Lock()
+
+			finished := c.finished
+
+			if !finished {
+				c.reap()
+			}
+
+			c.mu.Unlock()
+
+			if finished {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for domain := range domains {
+			id, err := queue.NewID()
+			if err != nil {
+				hqgologger.Error("failed queuing domain!", hqgologger.WithError(err), hqgologger.WithString("domain", domain))
+
+				continue
+			}
+
+			c.mu.Lock()
+
+			j := &job{
+				id:     id,
+				domain: domain,
+			}
+
+			c.jobs[id] = j
+			c.pending = append(c.pending, j)
+			c.remaining++
+
+			c.mu.Unlock()
+		}
+
+		c.mu.Lock()
+
+		c.closed = true
+
+		c.finish()
+
+		c.mu.Unlock()
+	}()
+
+	return
+}
+
+// Handler returns the HTTP API workers use, as described in the package documentation.
+//
+// Returns:
+//   - handler (http.Handler): The handler.
+func (c *Coordinator) Handler() (handler http.Handler) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/cluster/settings", c.authenticated(c.handleSettings))
+	mux.HandleFunc("POST /v1/cluster/lease", c.authenticated(c.handleLease))
+	mux.HandleFunc("POST /v1/cluster/jobs/{id}/heartbeat", c.authenticated(c.handleHeartbeat))
+	mux.HandleFunc("POST /v1/cluster/jobs/{id}/results", c.authenticated(c.handleResults))
+
+	handler = mux
+
+	return
+}
+
+// authenticated wraps handler, responding with 401 Unauthorized to requests without the
+// token of the cluster.
+func (c *Coordinator) authenticated(handler http.HandlerFunc) (wrapped http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if c.secured {
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			digest := sha256.Sum256([]byte(token))
+
+			if subtle.ConstantTimeCompare(c.digest[:], digest[:]) != 1 {
+				writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+
+				return
+			}
+		}
+
+		handler(w, r)
+	}
+}
+
+func (c *Coordinator) handleSettings(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, c.settings)
+}
+
+func (c *Coordinator) handleLease(w http.ResponseWriter, r *http.Request) {
+	var request leaseRequest
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	c.mu.Lock()
+
+	defer c.mu.Unlock()
+
+	c.reap()
+
+	if c.finished {
+		writeError(w, http.StatusGone, ErrFinished)
+
+		return
+	}
+
+	if len(c.pending) == 0 {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	j := c.pending[0]
+
+	c.pending = c.pending[1:]
+
+	j.worker = request.Worker
+	j.attempt++
+	j.deadline = time.Now().Add(c.timeout)
+	j.results = nil
+
+	hqgologger.Debug("job leased", hqgologger.WithString("domain", j.domain), hqgologger.WithString("worker", j.worker), hqgologger.WithString("attempt", strconv.Itoa(j.attempt)))
+
+	writeJSON(w, http.StatusOK, Assignment{
+		ID:        j.id,
+		Domain:    j.domain,
+		Attempt:   j.attempt,
+		Heartbeat: (c.timeout / 3).String(),
+	})
+}
+
+func (c *Coordinator) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var request heartbeatRequest
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	c.mu.Lock()
+
+	defer c.mu.Unlock()
+
+	if _, err := c.lease(r.PathValue("id"), request.Worker, request.Attempt); err != nil {
+		writeError(w, http.StatusConflict, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Coordinator) handleResults(w http.ResponseWriter, r *http.Request) {
+	var request resultsRequest
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	results := make([]sources.Result, 0, len(request.Results))
+
+	for _, record := range request.Results {
+		result, err := record.Result()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		results = append(results, result)
+	}
+
+	c.mu.Lock()
+
+	// The worker may push the last results again, e.g. should the response be lost.
+	if j, ok := c.jobs[r.PathValue("id")]; ok && j.done && request.Done && j.worker == request.Worker && j.attempt == request.Attempt {
+		c.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	j, err := c.lease(r.PathValue("id"), request.Worker, request.Attempt)
+	if err != nil {
+		c.mu.Unlock()
+
+		writeError(w, http.StatusConflict, err)
+
+		return
+	}
+
+	j.results = append(j.results, results...)
+
+	if !request.Done {
+		c.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	j.done = true
+
+	merged := j.results
+
+	j.results = nil
+
+	c.mu.Unlock()
+
+	hqgologger.Debug("job completed", hqgologger.WithString("domain", j.domain), hqgologger.WithString("worker", request.Worker))
+
+	// The job is acknowledged before its results are merged: a worker kept waiting on
+	// the output could time out and take the job for lost.
+	go c.merge(j, merged)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lease returns the job with the given identifier, renewing its lease, if the lease is
+// held by worker under attempt. The caller must hold mu.
+func (c *Coordinator) lease(id, worker string, attempt int) (j *job, err error) {
+	j, ok := c.jobs[id]
+	if !ok || j.done || j.worker != worker || j.attempt != attempt || time.Now().After(j.deadline) {
+		err = ErrLeaseLost
+
+		return
+	}
+
+	j.deadline = time.Now().Add(c.timeout)
+
+	return
+}
+
+// reap returns the jobs whose lease expired to the pending jobs, or abandons them after
+// MaxAttempts expired leases. The caller must hold mu.
+func (c *Coordinator) reap() {
+	now := time.Now()
+
+	for _, j := range c.jobs {
+		if j.done || j.worker == "" || now.Before(j.deadline) {
+			continue
+		}
+
+		hqgologger.Warn(
+			"worker lost, reassigning job...",
+			hqgologger.WithString("domain", j.domain),
+			hqgologger.WithString("worker", j.worker),
+		)
+
+		j.worker = ""
+		j.results = nil
+
+		if j.attempt < MaxAttempts {
+			c.pending = append(c.pending, j)
+
+			continue
+		}
+
+		j.done = true
+
+		abandoned := sources.Result{
+			Type:   sources.ResultError,
+			Source: Source,
+			Error:  sources.NewError(sources.ErrUnclassified, Source, fmt.Errorf("%w after %d expired leases", ErrAbandoned, j.attempt)),
+		}
+
+		// Merging must not hold mu, as the results may not be taken right away.
+		go c.merge(j, []sources.Result{abandoned})
+	}
+}
+
+// merge sends the results of a completed job and marks their end, then counts it as
+// merged.
+func (c *Coordinator) merge(j *job, results []sources.Result) {
+	for _, result := range results {
+		c.results <- xsubfind3r.DomainResult{
+			Domain: j.domain,
+			Result: result,
+		}
+	}
+
+	c.results <- xsubfind3r.DomainResult{
+		Domain: j.domain,
+		End:    true,
+	}
+
+	c.mu.Lock()
+
+	defer c.mu.Unlock()
+
+	c.remaining--
+
+	c.finish()
+}
+
+// finish closes the results channel once every domain has been received and every job
+// merged. The caller must hold mu.
+func (c *Coordinator) finish() {
+	if !c.closed || c.remaining > 0 || c.finished {
+		return
+	}
+
+	c.finished = true
+
+	close(c.results)
+}
+
+// decode decodes the JSON body of r into v, responding with 400 Bad Request if it is
+// invalid.
+func decode(w http.ResponseWriter, r *http.Request, v any) (ok bool) {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ok = true
+
+	return
+}
+
+// writeJSON responds with v in JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError responds with err as {"error": "..."}.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// NewCoordinator returns a coordinator running jobs with settings.
+//
+// Parameters:
+//   - settings (Settings): The settings jobs are run with.
+//   - token (string): The token workers must present, or "" for none.
+//   - timeout (time.Duration): How long leases last without being renewed, or 0 for
+//     DefaultLeaseTimeout.
+//
+// Returns:
+//   - c (*Coordinator): The coordinator.
+func NewCoordinator(settings Settings, token string, timeout time.Duration) (c *Coordinator) {
+	if timeout <= 0 {
+		timeout = DefaultLeaseTimeout
+	}
+
+	c = &Coordinator{
+		settings: settings,
+		digest:   sha256.Sum256([]byte(token)),
+		secured:  token != "",
+		timeout:  timeout,
+		results:  make(chan xsubfind3r.DomainResult),
+		jobs:     map[string]*job{},
+	}
+
+	return
+}
@@ -0,0 +1,118 @@
+// Package cluster spreads the enumeration of many domains across machines. A coordinator
+// splits the domains into jobs, one per domain; workers, each with its own egress and API
+// keys, lease jobs from it, run them, and push their results back. Results are merged at
+// the coordinator, once per job, when the job completes.
+//
+// The protocol is JSON over HTTP:
+//
+//	GET  /v1/cluster/settings             the settings jobs are run with
+//	POST /v1/cluster/lease                lease a job: 200 with an Assignment, 204 if none is
+//	                                      pending yet, 410 once every job has completed
+//	POST /v1/cluster/jobs/{id}/heartbeat  renew the lease of a job
+//	POST /v1/cluster/jobs/{id}/results    push results of a job, completing it with "done"
+//
+// Leases expire unless renewed, by heartbeats or results, within the lease timeout; the
+// job is then reassigned, and whatever the worker that lost it pushes afterwards is
+// refused with 409 Conflict. When a token is configured, every request must carry it, as
+// "Authorization: Bearer <token>".
+package cluster
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+)
+
+// Configuration configures the cluster, as the `cluster` section of the configuration
+// file.
+//
+// Fields:
+//   - Token (string): The token shared by the coordinator and its workers, or "" for
+//     none.
+type Configuration struct {
+	Token string `yaml:"token"`
+}
+
+// Settings are the settings every job of a coordinator is run with, so that results do
+// not depend on the worker running it, besides its API keys.
+//
+// Fields:
+//   - Sources ([]string): The sources to use, or none for every source.
+//   - Exclude ([]string): The sources not to use.
+//   - Include ([]string): The result types to report besides subdomains, e.g. "ip".
+//   - ExcludeExpired (bool): Whether to drop names found only in expired certificates.
+//   - MaxAge (string): Drop results last seen longer ago than this, e.g. "90d".
+//   - MinScore (float64): Drop subdomains scoring below this.
+type Settings struct {
+	Sources        []string `json:"sources,omitempty"`
+	Exclude        []string `json:"exclude,omitempty"`
+	Include        []string `json:"include,omitempty"`
+	ExcludeExpired bool     `json:"exclude_expired,omitempty"`
+	MaxAge         string   `json:"max_age,omitempty"`
+	MinScore       float64  `json:"min_score,omitempty"`
+}
+
+// Assignment is a leased job.
+//
+// Fields:
+//   - ID (string): The identifier of the job.
+//   - Domain (string): The domain to enumerate.
+//   - Attempt (int): The number of the lease, identifying it among those of the job.
+//   - Heartbeat (string): How often to renew the lease, e.g. "10s".
+type Assignment struct {
+	ID        string `json:"id"`
+	Domain    string `json:"domain"`
+	Attempt   int    `json:"attempt"`
+	Heartbeat string `json:"heartbeat"`
+}
+
+// leaseRequest is the body of lease requests.
+type leaseRequest struct {
+	Worker string `json:"worker"`
+}
+
+// heartbeatRequest is the body of heartbeats.
+type heartbeatRequest struct {
+	Worker  string `json:"worker"`
+	Attempt int    `json:"attempt"`
+}
+
+// resultsRequest is the body of result pushes.
+//
+// Fields:
+//   - Worker (string): The name of the worker.
+//   - Attempt (int): The lease the results were found under.
+//   - Results ([]queue.Record): The results.
+//   - Done (bool): Whether the job has completed, i.e. these are its last results.
+type resultsRequest struct {
+	Worker  string         `json:"worker"`
+	Attempt int            `json:"attempt"`
+	Results []queue.Record `json:"results"`
+	Done    bool           `json:"done"`
+}
+
+const (
+	// DefaultLeaseTimeout is how long a lease lasts without being renewed.
+	DefaultLeaseTimeout = 30 * time.Second
+	// MaxAttempts is the number of leases of a job that may expire before it is
+	// abandoned, should it keep losing its workers, e.g. by crashing them.
+	MaxAttempts = 3
+
+	// Source is the source of the errors reported for abandoned jobs.
+	Source = "cluster"
+
+	maxRequestSize = 32 << 20
+)
+
+var (
+	// ErrLeaseLost is returned for heartbeats and results of leases that expired, or of
+	// jobs that completed.
+	ErrLeaseLost = errors.New("lease lost")
+	// ErrUnauthorized is returned for requests without the token of the cluster.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrFinished is reported by coordinators that have no jobs left.
+	ErrFinished = errors.New("coordinator finished")
+	// ErrAbandoned is reported for jobs abandoned after MaxAttempts expired leases.
+	ErrAbandoned = errors.New("job abandoned")
+)
@@ -0,0 +1,408 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Worker runs the jobs of a coordinator.
+//
+// Fields:
+//   - name (string): The name of the worker, identifying it to the coordinator.
+//   - endpoint (string): The base URL of the coordinator.
+//   - token (string): The token of the cluster, or "" for none.
+//   - client (*http.Client): The client talking to the coordinator.
+type Worker struct {
+	name     string
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// Settings fetches the settings jobs are run with, retrying while the coordinator is
+// unreachable, for up to MaxUnreachable.
+//
+// Parameters:
+//   - ctx (context.Context): Stops the worker once done.
+//
+// Returns:
+//   - settings (Settings): The settings.
+//   - err (error): An error if they could not be fetched.
+func (w *Worker) Settings(ctx context.Context) (settings Settings, err error) {
+	err = w.retry(ctx, func() (err error) {
+		var status int
+
+		status, err = w.call(ctx, http.MethodGet, "/v1/cluster/settings", nil, &settings)
+		if err == nil && status != http.StatusOK {
+			err = fmt.Errorf("%w: status %d", errUnexpected, status)
+		}
+
+		return
+	})
+
+	return
+}
+
+// Run leases and runs jobs with finder, at most concurrency at a time, until the
+// coordinator has no jobs left or ctx is done. Results are pushed in batches of
+// BatchSize, or at least every FlushInterval.
+//
+// Parameters:
+//   - ctx (context.Context): Stops the worker once done; jobs in progress are abandoned,
+//     to be reassigned once their lease expires.
+//   - finder (*xsubfind3r.Finder): The finder running the jobs.
+//   - concurrency (int): The maximum number of jobs run at once.
+//   - options (...xsubfind3r.FindOption): Per-call overrides, applied to every job.
+//
+// Returns:
+//   - err (error): An error if the coordinator stayed unreachable for MaxUnreachable.
+func (w *Worker) Run(ctx context.Context, finder *xsubfind3r.Finder, concurrency int, options ...xsubfind3r.FindOption) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+	errs := make([]error, max(concurrency, 1))
+
+	for i := range max(concurrency, 1) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				assignment, ok, lerr := w.lease(ctx)
+				if lerr != nil {
+					if !errors.Is(lerr, ErrFinished) && ctx.Err() == nil {
+						errs[i] = lerr
+					}
+
+					// The other loops have nothing left to do either.
+					cancel()
+
+					return
+				}
+
+				if !ok {
+					select {
+					case <-ctx.Done():
+					case <-time.After(pollInterval):
+					}
+
+					continue
+				}
+
+				w.run(ctx, finder, assignment, options)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	err = errors.Join(errs...)
+
+	return
+}
+
+// lease leases a job, reporting ok false if none is pending yet, and ErrFinished once the
+// coordinator has no jobs left.
+func (w *Worker) lease(ctx context.Context) (assignment Assignment, ok bool, err error) {
+	err = w.retry(ctx, func() (err error) {
+		var status int
+
+		status, err = w.call(ctx, http.MethodPost, "/v1/cluster/lease", leaseRequest{Worker: w.name}, &assignment)
+		if err != nil {
+			return
+		}
+
+		switch status {
+		case http.StatusOK:
+			ok = true
+		case http.StatusNoContent:
+		case http.StatusGone:
+			err = ErrFinished
+		default:
+			err = fmt.Errorf("%w: status %d", errUnexpected, status)
+		}
+
+		return
+	})
+
+	return
+}
+
+// run runs a job, renewing its lease and pushing its results, until it completes or its
+// lease is lost.
+func (w *Worker) run(ctx context.Context, finder *xsubfind3r.Finder, assignment Assignment, options []xsubfind3r.FindOption) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	hqgologger.Info(fmt.Sprintf("Finding subdomains for %s...", assignment.Domain))
+
+	heartbeat, err := time.ParseDuration(assignment.Heartbeat)
+	if err != nil || heartbeat <= 0 {
+		heartbeat = DefaultLeaseTimeout / 3
+	}
+
+	path := "/v1/cluster/jobs/" + url.PathEscape(assignment.ID)
+
+	lost := func(err error) {
+		if ctx.Err() == nil {
+			hqgologger.Warn("lost job!", hqgologger.WithError(err), hqgologger.WithString("domain", assignment.Domain))
+		}
+
+		cancel()
+	}
+
+	go func() {
+		ticker := time.NewTicker(heartbeat)
+
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			request := heartbeatRequest{Worker: w.name, Attempt: assignment.Attempt}
+
+			if err := w.push(ctx, path+"/heartbeat", request); err != nil {
+				lost(err)
+
+				return
+			}
+		}
+	}()
+
+	batch := []queue.Record{}
+	flushed := time.Now()
+	found := 0
+
+	flush := func(done bool) (ok bool) {
+		request := resultsRequest{
+			Worker:  w.name,
+			Attempt: assignment.Attempt,
+			Results: batch,
+			Done:    done,
+		}
+
+		if err := w.push(ctx, path+"/results", request); err != nil {
+			lost(err)
+
+			return
+		}
+
+		batch = []queue.Record{}
+		flushed = time.Now()
+
+		ok = true
+
+		return
+	}
+
+	options = append(options, xsubfind3r.WithContext(ctx))
+
+	for result := range finder.Find(assignment.Domain, options...) {
+		if result.Type == sources.ResultSubdomain {
+			found++
+		}
+
+		batch = append(batch, queue.NewRecord(result))
+
+		if len(batch) >= BatchSize || time.Since(flushed) >= FlushInterval {
+			if !flush(false) {
+				return
+			}
+		}
+	}
+
+	if ctx.Err() != nil || !flush(true) {
+		return
+	}
+
+	hqgologger.Info(fmt.Sprintf("Found %d subdomains for %s.", found, assignment.Domain))
+}
+
+// push posts request to path, reporting ErrLeaseLost if the coordinator refuses it.
+func (w *Worker) push(ctx context.Context, path string, request any) (err error) {
+	err = w.retry(ctx, func() (err error) {
+		var status int
+
+		status, err = w.call(ctx, http.MethodPost, path, request, nil)
+		if err != nil {
+			return
+		}
+
+		switch status {
+		case http.StatusOK, http.StatusNoContent:
+		case http.StatusConflict:
+			err = ErrLeaseLost
+		default:
+			err = fmt.Errorf("%w: status %d", errUnexpected, status)
+		}
+
+		return
+	})
+
+	return
+}
+
+// retry calls f until it succeeds, fails with an error other than a transport error, or
+// transport errors persist for MaxUnreachable.
+func (w *Worker) retry(ctx context.Context, f func() (err error)) (err error) {
+	start := time.Now()
+	delay := time.Second
+
+	for {
+		err = f()
+
+		var transport *url.Error
+
+		if err == nil || !errors.As(err, &transport) || ctx.Err() != nil || time.Since(start) >= MaxUnreachable {
+			return
+		}
+
+		hqgologger.Debug("coordinator unreachable, retrying...", hqgologger.WithError(err), hqgologger.WithString("delay", delay.String()))
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+
+			return
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// call sends request, in JSON, to path, decoding the response into response if it is not
+// nil and the request succeeded.
+func (w *Worker) call(ctx context.Context, method, path string, request, response any) (status int, err error) {
+	var body io.Reader
+
+	if request != nil {
+		var data []byte
+
+		data, err = json.Marshal(request)
+		if err != nil {
+			return
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	var req *http.Request
+
+	req, err = http.NewRequestWithContext(ctx, method, w.endpoint+path, body)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.token)
+	}
+
+	var res *http.Response
+
+	res, err = w.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	defer res.Body.Close()
+
+	status = res.StatusCode
+
+	if status == http.StatusUnauthorized {
+		err = ErrUnauthorized
+
+		return
+	}
+
+	if status == http.StatusOK && response != nil {
+		err = json.NewDecoder(res.Body).Decode(response)
+
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	return
+}
+
+// NewWorker returns a worker running the jobs of the coordinator at endpoint.
+//
+// Parameters:
+//   - endpoint (string): The base URL of the coordinator, e.g. "http://10.0.0.1:9090".
+//   - token (string): The token of the cluster, or "" for none.
+//   - name (string): The name of the worker, identifying it to the coordinator.
+//
+// Returns:
+//   - w (*Worker): The worker.
+func NewWorker(endpoint, token, name string) (w *Worker) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+
+	w = &Worker{
+		name:     name,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    token,
+		client: &http.Client{
+			Timeout: time.Minute,
+		},
+	}
+
+	return
+}
+
+// DefaultWorkerName returns the name workers go by unless given one: the host name and
+// the process identifier.
+//
+// Parameters:
+//   - host (string): The host name.
+//   - pid (int): The process identifier.
+//
+// Returns:
+//   - name (string): The name.
+func DefaultWorkerName(host string, pid int) (name string) {
+	name = host + "-" + strconv.Itoa(pid)
+
+	return
+}
+
+const (
+	// BatchSize is the number of results a worker pushes at once.
+	BatchSize = 500
+	// FlushInterval is how often a worker pushes results at least, while a job runs.
+	FlushInterval = 5 * time.Second
+	// MaxUnreachable is how long a worker keeps retrying an unreachable coordinator.
+	MaxUnreachable = 2 * time.Minute
+
+	pollInterval  = time.Second
+	maxRetryDelay = 15 * time.Second
+)
+
+// errUnexpected is returned for unexpected responses of the coordinator.
+var errUnexpected = errors.New("unexpected response")
@@ -1,20 +1,37 @@
 package configuration
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
 
 	"dario.cat/mergo"
 	hqgologger "github.com/hueristiq/hq-go-logger"
+	"github.com/hueristiq/xsubfind3r/internal/cluster"
+	"github.com/hueristiq/xsubfind3r/internal/monitor"
+	"github.com/hueristiq/xsubfind3r/internal/notify"
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/internal/server"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
 	"github.com/logrusorgru/aurora/v4"
 	"gopkg.in/yaml.v3"
 )
 
 type Configuration struct {
-	Version string       `yaml:"version"`
-	Sources []string     `yaml:"sources"`
-	Keys    sources.Keys `yaml:"keys"`
+	Version   string                          `yaml:"version"`
+	Sources   []string                        `yaml:"sources"`
+	Keys      sources.Keys                    `yaml:"keys"`
+	DNSSEC    sources.DNSSECConfiguration     `yaml:"dnssec"`
+	CTLogs    sources.CTLogsConfiguration     `yaml:"ctlogs"`
+	TLS       sources.TLSConfiguration        `yaml:"tls"`
+	Imports   sources.ImportsConfiguration    `yaml:"imports"`
+	Scoring   xsubfind3r.ScoringConfiguration `yaml:"scoring"`
+	Monitor   monitor.Configuration           `yaml:"monitor"`
+	Notifiers []notify.Configuration          `yaml:"notifiers"`
+	Server    server.Configuration            `yaml:"server"`
+	Queue     queue.Configuration             `yaml:"queue"`
+	Cluster   cluster.Configuration           `yaml:"cluster"`
 }
 
 func (cfg *Configuration) Write(path string) (err error) {
@@ -80,6 +97,9 @@ __  _____ _   _| |__  / _(_)_ __   __| |___ / _ __
 	}()
 
 	DefaultConfigurationFilePath = filepath.Join(UserDotConfigDirectoryPath, NAME, "config.yaml")
+	DefaultStoreFilePath         = filepath.Join(UserDotConfigDirectoryPath, NAME, "results.db")
+	DefaultMonitorStatePath      = filepath.Join(UserDotConfigDirectoryPath, NAME, "monitor")
+	DefaultQueuePath             = filepath.Join(UserDotConfigDirectoryPath, NAME, "queue")
 	DefaultConfiguration         = Configuration{
 		Version: VERSION,
 		Sources: sources.List,
@@ -96,6 +116,46 @@ __  _____ _   _| |__  / _(_)_ __   __| |___ / _ __
 			URLScan:        []string{},
 			VirusTotal:     []string{},
 		},
+		DNSSEC: sources.DNSSECConfiguration{
+			Resolvers:  []string{},
+			Wordlist:   "",
+			MaxQueries: 5000,
+		},
+		CTLogs: sources.CTLogsConfiguration{
+			Logs:       []sources.CTLogConfiguration{},
+			State:      filepath.Join(UserDotConfigDirectoryPath, NAME, "state", "ctlogs.json"),
+			BatchSize:  256,
+			MaxEntries: 10000,
+		},
+		TLS: sources.TLSConfiguration{
+			Ports:       []int{443},
+			Timeout:     5,
+			Concurrency: 20,
+		},
+		Imports: sources.ImportsConfiguration{
+			Hosts: []string{},
+			JSONL: []string{},
+			Zones: []string{},
+			FDNS:  []string{},
+		},
+		Scoring: xsubfind3r.ScoringConfiguration{
+			Weights: maps.Clone(xsubfind3r.DefaultWeights),
+		},
+		Monitor: monitor.Configuration{
+			Interval: "24h",
+			State:    DefaultMonitorStatePath,
+			Domains:  []monitor.Target{},
+		},
+		Notifiers: []notify.Configuration{},
+		Server: server.Configuration{
+			Tenants: []server.Tenant{},
+		},
+		Queue: queue.Configuration{
+			Retention: "7d",
+		},
+		Cluster: cluster.Configuration{
+			Token: "",
+		},
 	}
 )
 
@@ -0,0 +1,320 @@
+// Package diff compares the subdomains found by a run against a baseline, the subdomains
+// found by the previous run, so that only changes are reported. The baseline is kept in
+// a file, in a directory with one file per domain, or in the results store, and is only
+// replaced once the run has completed.
+package diff
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hueristiq/xsubfind3r/internal/store"
+)
+
+// Baseline holds the subdomains found for each domain by the previous run.
+type Baseline interface {
+	// Load returns the subdomains of domain in the baseline. Domains absent from the
+	// baseline have none.
+	Load(domain string) (subdomains []string, err error)
+
+	// Update replaces the subdomains of domain. The change takes effect on Commit.
+	Update(domain string, subdomains []string)
+
+	// Commit writes every update.
+	Commit() (err error)
+}
+
+// FileBaseline keeps the baseline of every domain in a single file, one subdomain per
+// line. Files in the JSONL output format are read too, so the output of an earlier run
+// can serve as the first baseline; the file is always written back as plain lines.
+//
+// Fields:
+//   - path (string): The path of the file.
+//   - lines ([]string): The subdomains in the file.
+//   - updates (map[string][]string): The pending updates, keyed by domain.
+type FileBaseline struct {
+	path    string
+	lines   []string
+	updates map[string][]string
+}
+
+func (b *FileBaseline) Load(domain string) (subdomains []string, err error) {
+	for _, line := range b.lines {
+		if belongs(line, domain) {
+			subdomains = append(subdomains, line)
+		}
+	}
+
+	return
+}
+
+func (b *FileBaseline) Update(domain string, subdomains []string) {
+	b.updates[domain] = subdomains
+}
+
+func (b *FileBaseline) Commit() (err error) {
+	if len(b.updates) == 0 {
+		return
+	}
+
+	lines := []string{}
+
+	for _, line := range b.lines {
+		updated := false
+
+		for domain := range b.updates {
+			if belongs(line, domain) {
+				updated = true
+
+				break
+			}
+		}
+
+		if !updated {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, subdomains := range b.updates {
+		lines = append(lines, subdomains...)
+	}
+
+	slices.Sort(lines)
+
+	lines = slices.Compact(lines)
+
+	if err = writeLines(b.path, lines); err != nil {
+		return
+	}
+
+	b.lines = lines
+	b.updates = map[string][]string{}
+
+	return
+}
+
+// DirectoryBaseline keeps the baseline of each domain in its own file, "<domain>.txt",
+// one subdomain per line, as written by `--output-directory`.
+//
+// Fields:
+//   - path (string): The path of the directory.
+//   - updates (map[string][]string): The pending updates, keyed by domain.
+type DirectoryBaseline struct {
+	path    string
+	updates map[string][]string
+}
+
+func (b *DirectoryBaseline) Load(domain string) (subdomains []string, err error) {
+	var lines []string
+
+	lines, err = readLines(filepath.Join(b.path, domain+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+
+		return
+	}
+
+	for _, line := range lines {
+		if belongs(line, domain) {
+			subdomains = append(subdomains, line)
+		}
+	}
+
+	return
+}
+
+func (b *DirectoryBaseline) Update(domain string, subdomains []string) {
+	b.updates[domain] = subdomains
+}
+
+func (b *DirectoryBaseline) Commit() (err error) {
+	for domain, subdomains := range b.updates {
+		lines := slices.Sorted(slices.Values(subdomains))
+
+		if err = writeLines(filepath.Join(b.path, domain+".txt"), lines); err != nil {
+			return
+		}
+
+		delete(b.updates, domain)
+	}
+
+	return
+}
+
+// StoreBaseline uses the results store as the baseline: the baseline of a domain is what
+// the latest completed run recorded for it. The store is updated by recording the
+// current run, so Update and Commit do nothing.
+//
+// Fields:
+//   - store (*store.Store): The results store.
+type StoreBaseline struct {
+	store *store.Store
+}
+
+func (b *StoreBaseline) Load(domain string) (subdomains []string, err error) {
+	subdomains, err = b.store.Latest(domain)
+
+	return
+}
+
+func (b *StoreBaseline) Update(_ string, _ []string) {}
+
+func (b *StoreBaseline) Commit() (err error) {
+	return
+}
+
+// NewFileBaseline returns a baseline kept in the file at path. The file need not exist.
+//
+// Parameters:
+//   - path (string): The path of the file.
+//
+// Returns:
+//   - baseline (*FileBaseline): The baseline.
+//   - err (error): An error if the file exists but could not be read.
+func NewFileBaseline(path string) (baseline *FileBaseline, err error) {
+	baseline = &FileBaseline{
+		path:    path,
+		updates: map[string][]string{},
+	}
+
+	baseline.lines, err = readLines(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+
+	return
+}
+
+// NewDirectoryBaseline returns a baseline kept in the directory at path. The directory
+// need not exist.
+//
+// Parameters:
+//   - path (string): The path of the directory.
+//
+// Returns:
+//   - baseline (*DirectoryBaseline): The baseline.
+func NewDirectoryBaseline(path string) (baseline *DirectoryBaseline) {
+	baseline = &DirectoryBaseline{
+		path:    path,
+		updates: map[string][]string{},
+	}
+
+	return
+}
+
+// NewStoreBaseline returns a baseline read from the results store s.
+//
+// Parameters:
+//   - s (*store.Store): The results store.
+//
+// Returns:
+//   - baseline (*StoreBaseline): The baseline.
+func NewStoreBaseline(s *store.Store) (baseline *StoreBaseline) {
+	baseline = &StoreBaseline{
+		store: s,
+	}
+
+	return
+}
+
+// belongs reports whether subdomain is domain or one of its subdomains.
+func belongs(subdomain, domain string) bool {
+	return subdomain == domain || strings.HasSuffix(subdomain, "."+domain)
+}
+
+// readLines reads the subdomains in the file at path: one per line, or the "subdomain"
+// field of JSONL lines. Empty lines and lines of other JSONL results are skipped.
+func readLines(path string) (lines []string, err error) {
+	var file *os.File
+
+	file, err = os.Open(path)
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "{") {
+			var record struct {
+				Subdomain string `json:"subdomain"`
+			}
+
+			if json.Unmarshal([]byte(line), &record) != nil {
+				continue
+			}
+
+			line = record.Subdomain
+		}
+
+		line = strings.ToLower(line)
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	err = scanner.Err()
+
+	return
+}
+
+// writeLines atomically replaces the file at path with lines: they are written to a
+// temporary file in the same directory, which is then renamed over path.
+func writeLines(path string, lines []string) (err error) {
+	directory := filepath.Dir(path)
+
+	if err = os.MkdirAll(directory, 0o750); err != nil {
+		return
+	}
+
+	var file *os.File
+
+	file, err = os.CreateTemp(directory, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			file.Close()
+
+			os.Remove(file.Name())
+		}
+	}()
+
+	bw := bufio.NewWriter(file)
+
+	for _, line := range lines {
+		if _, err = bw.WriteString(line + "\n"); err != nil {
+			return
+		}
+	}
+
+	if err = bw.Flush(); err != nil {
+		return
+	}
+
+	if err = file.Sync(); err != nil {
+		return
+	}
+
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(file.Name(), path)
+
+	return
+}
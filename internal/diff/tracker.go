@@ -0,0 +1,206 @@
+package diff
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Tracker compares the subdomains found by a run against a Baseline. It is safe for
+// concurrent use.
+//
+// Fields:
+//   - baseline (Baseline): The baseline.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - previous (map[string]map[string]struct{}): The baseline of every domain loaded so
+//     far, keyed by domain.
+//   - current (map[string]map[string]struct{}): The subdomains found by the run, keyed by
+//     domain.
+//   - failed (map[string]struct{}): The domains a source failed for.
+type Tracker struct {
+	baseline Baseline
+	mu       sync.Mutex
+	previous map[string]map[string]struct{}
+	current  map[string]map[string]struct{}
+	failed   map[string]struct{}
+}
+
+// Added records that the run found subdomain for domain, and reports whether it is new:
+// absent from the baseline and not found before by this run.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//   - subdomain (string): The subdomain found.
+//
+// Returns:
+//   - added (bool): Whether the subdomain is new.
+//   - err (error): An error if the baseline of domain could not be loaded.
+func (t *Tracker) Added(domain, subdomain string) (added bool, err error) {
+	t.mu.Lock()
+
+	defer t.mu.Unlock()
+
+	previous, ok := t.previous[domain]
+	if !ok {
+		var subdomains []string
+
+		subdomains, err = t.baseline.Load(domain)
+		if err != nil {
+			return
+		}
+
+		previous = map[string]struct{}{}
+
+		for _, s := range subdomains {
+			previous[s] = struct{}{}
+		}
+
+		t.previous[domain] = previous
+		t.current[domain] = map[string]struct{}{}
+	}
+
+	if _, ok := t.current[domain][subdomain]; ok {
+		return
+	}
+
+	t.current[domain][subdomain] = struct{}{}
+
+	_, ok = previous[subdomain]
+
+	added = !ok
+
+	return
+}
+
+// Failed records that a source failed for domain, so that what the run found for domain
+// may be incomplete: its removed subdomains are not reported and the subdomains of its
+// baseline are kept.
+// Errors of sources missing their API keys are ignored, as those sources never
+// contribute to a baseline.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//   - err (error): The error of the source.
+func (t *Tracker) Failed(domain string, err error) {
+	if errors.Is(err, sources.ErrMissingKey) {
+		return
+	}
+
+	t.mu.Lock()
+
+	defer t.mu.Unlock()
+
+	t.failed[domain] = struct{}{}
+}
+
+// Removed returns, for every domain the run found subdomains for, the subdomains of the
+// baseline the run did not find, sorted. Domains the run found nothing for are left out,
+// as their sources most likely failed, and so are domains a source failed for.
+//
+// Returns:
+//   - removed (map[string][]string): The removed subdomains, keyed by domain.
+func (t *Tracker) Removed() (removed map[string][]string) {
+	t.mu.Lock()
+
+	defer t.mu.Unlock()
+
+	removed = map[string][]string{}
+
+	for domain := range t.current {
+		if subdomains := t.removed(domain); len(subdomains) > 0 {
+			removed[domain] = subdomains
+		}
+	}
+
+	return
+}
+
+// RemovedFrom returns the subdomains of the baseline of domain the run did not find,
+// sorted, or nil if the run found nothing for domain; see Removed. Call it once the run
+// has finished with domain.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//
+// Returns:
+//   - removed ([]string): The removed subdomains.
+func (t *Tracker) RemovedFrom(domain string) (removed []string) {
+	t.mu.Lock()
+
+	defer t.mu.Unlock()
+
+	removed = t.removed(domain)
+
+	return
+}
+
+// removed returns the removed subdomains of domain. The caller must hold mu.
+func (t *Tracker) removed(domain string) (removed []string) {
+	current := t.current[domain]
+
+	if _, failed := t.failed[domain]; failed || len(current) == 0 {
+		return
+	}
+
+	for subdomain := range t.previous[domain] {
+		if _, ok := current[subdomain]; !ok {
+			removed = append(removed, subdomain)
+		}
+	}
+
+	slices.Sort(removed)
+
+	return
+}
+
+// Commit replaces the baseline of every domain the run found subdomains for with what
+// the run found. The baseline of a domain a source failed for keeps its subdomains, the
+// run's being added to them, so that those found again are not reported as new again.
+// Call it only once the run has completed.
+//
+// Returns:
+//   - err (error): An error if the baseline could not be written.
+func (t *Tracker) Commit() (err error) {
+	t.mu.Lock()
+
+	defer t.mu.Unlock()
+
+	for domain, current := range t.current {
+		if len(current) == 0 {
+			continue
+		}
+
+		if _, failed := t.failed[domain]; failed {
+			current = maps.Clone(current)
+
+			maps.Copy(current, t.previous[domain])
+		}
+
+		t.baseline.Update(domain, slices.Sorted(maps.Keys(current)))
+	}
+
+	err = t.baseline.Commit()
+
+	return
+}
+
+// NewTracker returns a tracker comparing against baseline.
+//
+// Parameters:
+//   - baseline (Baseline): The baseline.
+//
+// Returns:
+//   - tracker (*Tracker): The tracker.
+func NewTracker(baseline Baseline) (tracker *Tracker) {
+	tracker = &Tracker{
+		baseline: baseline,
+		previous: map[string]map[string]struct{}{},
+		current:  map[string]map[string]struct{}{},
+		failed:   map[string]struct{}{},
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// memoryBaseline is a baseline held in memory.
+type memoryBaseline struct {
+	domains map[string][]string
+	updates map[string][]string
+}
+
+func (b *memoryBaseline) Load(domain string) (subdomains []string, err error) {
+	return b.domains[domain], nil
+}
+
+func (b *memoryBaseline) Update(domain string, subdomains []string) {
+	b.updates[domain] = subdomains
+}
+
+func (b *memoryBaseline) Commit() (err error) {
+	for domain, subdomains := range b.updates {
+		b.domains[domain] = subdomains
+	}
+
+	return
+}
+
+// TestTrackerFailed checks that the baseline of a domain a source failed for keeps its
+// subdomains, while that of the other domains is replaced.
+func TestTrackerFailed(t *testing.T) {
+	baseline := &memoryBaseline{
+		domains: map[string][]string{
+			"example.com": {"a.example.com", "b.example.com"},
+			"example.org": {"a.example.org", "b.example.org"},
+		},
+		updates: map[string][]string{},
+	}
+
+	tracker := NewTracker(baseline)
+
+	for _, found := range [][2]string{
+		{"example.com", "a.example.com"},
+		{"example.com", "c.example.com"},
+		{"example.org", "a.example.org"},
+	} {
+		if _, err := tracker.Added(found[0], found[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tracker.Failed("example.com", sources.NewError(sources.ErrRateLimited, sources.CRTSH, nil))
+	tracker.Failed("example.org", sources.NewError(sources.ErrMissingKey, sources.SHODAN, nil))
+
+	if removed := tracker.RemovedFrom("example.com"); len(removed) != 0 {
+		t.Errorf("got removed %v for a domain a source failed for", removed)
+	}
+
+	if removed := tracker.RemovedFrom("example.org"); !slices.Equal(removed, []string{"b.example.org"}) {
+		t.Errorf("got removed %v, want b.example.org", removed)
+	}
+
+	if err := tracker.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := baseline.domains["example.com"]; !slices.Equal(got, []string{"a.example.com", "b.example.com", "c.example.com"}) {
+		t.Errorf("got baseline %v, want the previous subdomains and the new one", got)
+	}
+
+	if got := baseline.domains["example.org"]; !slices.Equal(got, []string{"a.example.org"}) {
+		t.Errorf("got baseline %v, want it replaced", got)
+	}
+}
@@ -0,0 +1,41 @@
+package input
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a duration given as a number of days, such as "90d", or as a Go
+// duration, such as "12h".
+//
+// Parameters:
+//   - value (string): The duration.
+//
+// Returns:
+//   - duration (time.Duration): The parsed duration.
+//   - err (error): An error if value is neither.
+func ParseDuration(value string) (duration time.Duration, err error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+
+		n, err = strconv.Atoi(days)
+		if err != nil {
+			err = fmt.Errorf("invalid duration %q", value)
+
+			return
+		}
+
+		duration = time.Duration(n) * 24 * time.Hour
+
+		return
+	}
+
+	duration, err = time.ParseDuration(value)
+	if err != nil {
+		err = fmt.Errorf("invalid duration %q", value)
+	}
+
+	return
+}
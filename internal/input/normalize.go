@@ -0,0 +1,165 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Normalizer turns free-form input lines into target domains.
+//
+// Fields:
+//   - Registrable (bool): Reduce every hostname to its registrable domain (eTLD+1),
+//     e.g. "www.api.example.co.uk" to "example.co.uk".
+type Normalizer struct {
+	Registrable bool
+}
+
+// Normalize extracts the target domain from a single input line.
+//
+// URLs ("https://www.example.com/path"), e-mail addresses ("user@example.com"),
+// wildcards ("*.example.com"), host:port pairs and fully qualified names with a trailing
+// dot ("EXAMPLE.com.") are reduced to their hostname, internationalized names are
+// converted to their ASCII (punycode) form, and the result is lowercased.
+//
+// Parameters:
+//   - line (string): The raw input line.
+//
+// Returns:
+//   - domain (string): The normalized domain.
+//   - err (error): ErrSkip for blank lines and comments, or an error wrapping ErrInvalid
+//     describing why the line was rejected.
+func (n *Normalizer) Normalize(line string) (domain string, err error) {
+	domain = strings.TrimSpace(line)
+
+	if domain == "" || strings.HasPrefix(domain, "#") {
+		err = ErrSkip
+
+		return
+	}
+
+	if strings.Contains(domain, "://") {
+		var parsed *url.URL
+
+		parsed, err = url.Parse(domain)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalid, err)
+
+			return
+		}
+
+		domain = parsed.Hostname()
+	}
+
+	if index := strings.LastIndex(domain, "@"); index >= 0 {
+		domain = domain[index+1:]
+	}
+
+	if index := strings.IndexAny(domain, "/?#"); index >= 0 {
+		domain = domain[:index]
+	}
+
+	if host, _, splitErr := net.SplitHostPort(domain); splitErr == nil {
+		domain = host
+	}
+
+	domain = strings.TrimPrefix(domain, "*.")
+	domain = strings.TrimSuffix(domain, ".")
+
+	if domain == "" {
+		err = fmt.Errorf("%w: no hostname", ErrInvalid)
+
+		return
+	}
+
+	if net.ParseIP(domain) != nil {
+		err = fmt.Errorf("%w: %s is an IP address", ErrInvalid, domain)
+
+		return
+	}
+
+	domain, err = idna.Lookup.ToASCII(domain)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalid, err)
+
+		return
+	}
+
+	domain = strings.ToLower(domain)
+
+	if !strings.Contains(domain, ".") {
+		err = fmt.Errorf("%w: %s is not a domain", ErrInvalid, domain)
+
+		return
+	}
+
+	if n.Registrable {
+		domain, err = publicsuffix.EffectiveTLDPlusOne(domain)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalid, err)
+
+			return
+		}
+	}
+
+	return
+}
+
+// Collapse removes duplicate domains and domains that are subdomains of other domains
+// in the list, since enumerating the parent already covers them. The order of the
+// remaining domains is preserved.
+//
+// Parameters:
+//   - domains ([]string): The normalized domains.
+//
+// Returns:
+//   - collapsed ([]string): The remaining domains.
+func Collapse(domains []string) (collapsed []string) {
+	all := map[string]struct{}{}
+
+	for _, domain := range domains {
+		all[domain] = struct{}{}
+	}
+
+	kept := map[string]struct{}{}
+
+	for _, domain := range domains {
+		if _, ok := kept[domain]; ok {
+			continue
+		}
+
+		covered := false
+
+		for parent := domain; strings.Contains(parent, "."); {
+			parent = parent[strings.Index(parent, ".")+1:]
+
+			if _, ok := all[parent]; ok {
+				covered = true
+
+				break
+			}
+		}
+
+		if covered {
+			continue
+		}
+
+		kept[domain] = struct{}{}
+
+		collapsed = append(collapsed, domain)
+	}
+
+	return
+}
+
+var (
+	// ErrSkip is returned by Normalize for blank lines and comments, which are ignored silently.
+	ErrSkip = errors.New("blank line or comment")
+	// ErrInvalid is wrapped by the errors Normalize returns for lines that are rejected.
+	ErrInvalid = errors.New("invalid input")
+)
@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/input"
+)
+
+// Configuration configures monitor mode, as the `monitor` section of the configuration
+// file.
+//
+// Fields:
+//   - Interval (string): The schedule of domains with none of their own, e.g. "24h" or "1d".
+//   - State (string): The directory the monitor persists its state in: the time of the last
+//     run and the subdomains known for every domain.
+//   - Domains ([]Target): The monitored domains.
+type Configuration struct {
+	Interval string   `yaml:"interval"`
+	State    string   `yaml:"state"`
+	Domains  []Target `yaml:"domains"`
+}
+
+// Target is a monitored domain.
+//
+// Fields:
+//   - Domain (string): The domain.
+//   - Interval (string): How often to enumerate it, or "" for the default interval.
+type Target struct {
+	Domain   string `yaml:"domain"`
+	Interval string `yaml:"interval"`
+}
+
+// Schedule is a monitored domain with its parsed interval.
+//
+// Fields:
+//   - Domain (string): The domain.
+//   - Interval (time.Duration): How often to enumerate it.
+type Schedule struct {
+	Domain   string
+	Interval time.Duration
+}
+
+// ParseInterval parses an interval: a number of days such as "1d", or a Go duration such
+// as "6h". Intervals shorter than MinInterval are rejected.
+//
+// Parameters:
+//   - value (string): The interval.
+//
+// Returns:
+//   - interval (time.Duration): The parsed interval.
+//   - err (error): An error if value is not a valid interval.
+func ParseInterval(value string) (interval time.Duration, err error) {
+	interval, err = input.ParseDuration(value)
+	if err != nil {
+		return
+	}
+
+	if interval < MinInterval {
+		err = fmt.Errorf("%w: %q is shorter than %s", ErrInterval, value, MinInterval)
+	}
+
+	return
+}
+
+const (
+	// DefaultInterval is the schedule of domains when none is configured.
+	DefaultInterval = 24 * time.Hour
+	// MinInterval is the shortest accepted interval.
+	MinInterval = time.Minute
+)
+
+// ErrInterval is returned for intervals shorter than MinInterval.
+var ErrInterval = errors.New("interval too short")
@@ -0,0 +1,399 @@
+// Package monitor re-enumerates domains on schedules and dispatches the changes each run
+// finds.
+package monitor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	"github.com/hueristiq/xsubfind3r/internal/diff"
+	"github.com/hueristiq/xsubfind3r/internal/notify"
+	"github.com/hueristiq/xsubfind3r/internal/store"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Settings are what a Monitor runs with. They can be replaced while it runs, see Reload.
+//
+// Fields:
+//   - Finder (*xsubfind3r.Finder): The finder enumerating domains.
+//   - Schedules ([]Schedule): The monitored domains.
+//   - Notifiers ([]notify.Notifier): Where changes are dispatched.
+//   - Options ([]xsubfind3r.FindOption): Per-call options of every enumeration.
+type Settings struct {
+	Finder    *xsubfind3r.Finder
+	Schedules []Schedule
+	Notifiers []notify.Notifier
+	Options   []xsubfind3r.FindOption
+}
+
+// Monitor enumerates domains on their schedules, and dispatches the subdomains each run
+// finds that the previous run of the domain did not to its notifiers. The first run to
+// find subdomains for a domain establishes its baseline silently.
+//
+// Its state lives in a directory: "state.json" holds when every domain was last
+// enumerated, so that schedules survive restarts, and "baseline/<domain>.txt" the
+// subdomains known for every domain.
+//
+// Fields:
+//   - state (*State): When every domain was last enumerated.
+//   - baseline (string): The directory of the per-domain baselines.
+//   - concurrency (int): The maximum number of domains enumerated at once.
+//   - store (*store.Store): The results store runs are recorded in, or nil.
+//   - version (string): The version runs are recorded with.
+//   - mu (sync.Mutex): Guards settings.
+//   - settings (Settings): The current settings.
+//   - reload (chan struct{}): Signals that the settings changed.
+type Monitor struct {
+	state       *State
+	baseline    string
+	concurrency int
+	store       *store.Store
+	version     string
+	mu          sync.Mutex
+	settings    Settings
+	reload      chan struct{}
+}
+
+// SetStore records every run in db.
+//
+// Parameters:
+//   - db (*store.Store): The results store.
+//   - version (string): The version of the program, recorded with every run.
+func (m *Monitor) SetStore(db *store.Store, version string) {
+	m.store = db
+	m.version = version
+}
+
+// Reload replaces the settings. Runs in progress complete with the settings they started
+// with; domains no longer listed are no longer scheduled, and new ones are scheduled at
+// once unless their state says otherwise.
+//
+// Parameters:
+//   - settings (Settings): The new settings.
+func (m *Monitor) Reload(settings Settings) {
+	m.mu.Lock()
+
+	m.settings = settings
+
+	m.mu.Unlock()
+
+	select {
+	case m.reload <- struct{}{}:
+	default:
+	}
+}
+
+// ReloadOn reloads the settings returned by load whenever a signal arrives on signals,
+// e.g. SIGHUP, until ctx is done. Settings that fail to load are logged, and the current
+// ones are kept.
+//
+// Parameters:
+//   - ctx (context.Context): Stops reloading once done.
+//   - signals (<-chan os.Signal): The signals triggering a reload.
+//   - load (func() (Settings, error)): Loads the new settings.
+func (m *Monitor) ReloadOn(ctx context.Context, signals <-chan os.Signal, load func() (settings Settings, err error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			settings, err := load()
+			if err != nil {
+				hqgologger.Error("failed reloading monitor settings, keeping the current ones!", hqgologger.WithError(err))
+
+				continue
+			}
+
+			m.Reload(settings)
+
+			hqgologger.Info("reloaded monitor settings.", hqgologger.WithString("domains", strconv.Itoa(len(settings.Schedules))))
+		}
+	}
+}
+
+// Run enumerates the domains on their schedules until ctx is done, then waits for the
+// runs in progress to stop. A domain is due when its interval has elapsed since the start
+// of its last completed run; interrupted runs are not recorded, so they are repeated.
+//
+// Parameters:
+//   - ctx (context.Context): Stops the monitor once done.
+func (m *Monitor) Run(ctx context.Context) {
+	var (
+		wg      sync.WaitGroup
+		running = map[string]bool{}
+		retries = map[string]time.Time{}
+		done    = make(chan outcome)
+		slots   = make(chan struct{}, m.concurrency)
+	)
+
+	defer wg.Wait()
+
+	for {
+		settings := m.current()
+
+		now := time.Now()
+
+		var next time.Time
+
+		for _, schedule := range settings.Schedules {
+			if running[schedule.Domain] {
+				continue
+			}
+
+			due := m.state.Last(schedule.Domain).Add(schedule.Interval)
+
+			if retry, ok := retries[schedule.Domain]; ok && retry.After(due) {
+				due = retry
+			}
+
+			if due.After(now) {
+				if next.IsZero() || due.Before(next) {
+					next = due
+				}
+
+				continue
+			}
+
+			running[schedule.Domain] = true
+
+			wg.Add(1)
+
+			go func(schedule Schedule) {
+				defer wg.Done()
+
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+
+				err := m.enumerate(ctx, settings, schedule.Domain)
+
+				<-slots
+
+				select {
+				case done <- outcome{schedule: schedule, err: err}:
+				case <-ctx.Done():
+				}
+			}(schedule)
+		}
+
+		var (
+			timer *time.Timer
+			wake  <-chan time.Time
+		)
+
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+
+			wake = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case o := <-done:
+			delete(running, o.schedule.Domain)
+			delete(retries, o.schedule.Domain)
+
+			if o.err != nil {
+				hqgologger.Error("failed monitoring domain!", hqgologger.WithError(o.err), hqgologger.WithString("domain", o.schedule.Domain))
+
+				retries[o.schedule.Domain] = time.Now().Add(min(retryDelay, o.schedule.Interval))
+			}
+		case <-m.reload:
+		case <-wake:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// current returns the current settings.
+func (m *Monitor) current() (settings Settings) {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	settings = m.settings
+
+	return
+}
+
+// enumerate runs a single enumeration of domain, updates its baseline and state, and
+// dispatches the changes found to the notifiers.
+func (m *Monitor) enumerate(ctx context.Context, settings Settings, domain string) (err error) {
+	start := time.Now()
+
+	baseline := diff.NewDirectoryBaseline(m.baseline)
+
+	var known []string
+
+	known, err = baseline.Load(domain)
+	if err != nil {
+		return
+	}
+
+	// Until a run finds subdomains, every subdomain would be reported as new.
+	first := len(known) == 0
+
+	tracker := diff.NewTracker(baseline)
+
+	var run *store.Run
+
+	if m.store != nil {
+		run, err = m.store.StartRun(m.version)
+		if err != nil {
+			return
+		}
+
+		defer func() {
+			if ferr := run.Finish(); ferr != nil && err == nil {
+				err = ferr
+			}
+		}()
+	}
+
+	hqgologger.Debug("enumerating domain...", hqgologger.WithString("domain", domain))
+
+	added := []notify.Subdomain{}
+	errs := 0
+
+	options := append(slices.Clone(settings.Options), xsubfind3r.WithContext(ctx))
+
+	if run != nil {
+		options = append(options, xsubfind3r.WithReports())
+	}
+
+	for result, rerr := range settings.Finder.All(domain, options...) {
+		if run != nil {
+			if err = run.Add(domain, result); err != nil {
+				return
+			}
+		}
+
+		if rerr != nil {
+			errs++
+
+			tracker.Failed(domain, rerr)
+
+			hqgologger.Debug("error finding subdomains!", hqgologger.WithError(rerr), hqgologger.WithString("source", result.Source))
+
+			continue
+		}
+
+		if result.Type != sources.ResultSubdomain {
+			continue
+		}
+
+		var isNew bool
+
+		isNew, err = tracker.Added(domain, result.Value)
+		if err != nil {
+			return
+		}
+
+		if isNew {
+			added = append(added, notify.Subdomain{
+				Name:    result.Value,
+				Sources: result.Sources,
+				Score:   result.Score,
+			})
+		}
+	}
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	removed := tracker.RemovedFrom(domain)
+
+	if err = tracker.Commit(); err != nil {
+		return
+	}
+
+	if err = m.state.Record(domain, start); err != nil {
+		return
+	}
+
+	hqgologger.Info(
+		"enumerated domain.",
+		hqgologger.WithString("domain", domain),
+		hqgologger.WithString("added", strconv.Itoa(len(added))),
+		hqgologger.WithString("removed", strconv.Itoa(len(removed))),
+		hqgologger.WithString("errors", strconv.Itoa(errs)),
+	)
+
+	if first || (len(added) == 0 && len(removed) == 0) {
+		return
+	}
+
+	slices.SortFunc(added, func(a, b notify.Subdomain) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	event := notify.Event{
+		Domain:  domain,
+		Time:    start,
+		Added:   added,
+		Removed: removed,
+	}
+
+	// A completed run's changes are delivered even while shutting down.
+	nctx := context.WithoutCancel(ctx)
+
+	if nerr := notify.Dispatch(nctx, settings.Notifiers, event); nerr != nil {
+		hqgologger.Error("failed notifying!", hqgologger.WithError(nerr), hqgologger.WithString("domain", domain))
+	}
+
+	return
+}
+
+// outcome is the result of a run, reported to the scheduler.
+type outcome struct {
+	schedule Schedule
+	err      error
+}
+
+// New returns a monitor persisting its state in directory.
+//
+// Parameters:
+//   - directory (string): The state directory.
+//   - concurrency (int): The maximum number of domains enumerated at once.
+//   - settings (Settings): The initial settings.
+//
+// Returns:
+//   - m (*Monitor): The monitor.
+//   - err (error): An error if the state could not be loaded.
+func New(directory string, concurrency int, settings Settings) (m *Monitor, err error) {
+	var state *State
+
+	state, err = LoadState(filepath.Join(directory, "state.json"))
+	if err != nil {
+		return
+	}
+
+	m = &Monitor{
+		state:       state,
+		baseline:    filepath.Join(directory, "baseline"),
+		concurrency: max(concurrency, 1),
+		settings:    settings,
+		reload:      make(chan struct{}, 1),
+	}
+
+	return
+}
+
+// retryDelay is how long a domain whose run failed waits before it is retried, unless its
+// interval is shorter.
+var retryDelay = 5 * time.Minute
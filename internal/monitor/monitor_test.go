@@ -0,0 +1,440 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/notify"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// fake is a finder reading its subdomains from a hosts file, and counting its runs with
+// a Certificate Transparency log that every run queries once.
+type fake struct {
+	finder *xsubfind3r.Finder
+	hosts  string
+	runs   atomic.Int64
+}
+
+// newFake returns a fake finder, finding subdomains.
+func newFake(t *testing.T, subdomains ...string) (f *fake) {
+	t.Helper()
+
+	f = &fake{
+		hosts: filepath.Join(t.TempDir(), "hosts"),
+	}
+
+	f.find(t, subdomains...)
+
+	log := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		f.runs.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		fmt.Fprint(w, `{"tree_size":0}`)
+	}))
+
+	t.Cleanup(log.Close)
+
+	var err error
+
+	f.finder, err = xsubfind3r.New(&xsubfind3r.Configuration{
+		SourcesToUSe: []string{sources.CTLOGS, sources.HOSTSFILE},
+		CTLogs: sources.CTLogsConfiguration{
+			Logs: []sources.CTLogConfiguration{{URL: log.URL + "/"}},
+		},
+		Imports: sources.ImportsConfiguration{
+			Hosts: []string{f.hosts},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// find makes the following runs find subdomains.
+func (f *fake) find(t *testing.T, subdomains ...string) {
+	t.Helper()
+
+	data := &strings.Builder{}
+
+	for _, subdomain := range subdomains {
+		fmt.Fprintf(data, "127.0.0.1 %s\n", subdomain)
+	}
+
+	if err := writeFile(f.hosts, []byte(data.String())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recorder is a notifier recording the events it is sent.
+type recorder struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (r *recorder) Name() (name string) {
+	return "recorder"
+}
+
+func (r *recorder) Notify(_ context.Context, event notify.Event) (err error) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+
+	return
+}
+
+func (r *recorder) sent() (events []notify.Event) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	events = slices.Clone(r.events)
+
+	return
+}
+
+// start runs m until the test ends.
+func start(t *testing.T, m *Monitor) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		m.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		<-done
+	})
+}
+
+// eventually fails the test unless condition holds within a few seconds.
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); !condition(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestSchedule checks that domains are enumerated once their interval has elapsed since
+// their last run, as persisted in the state.
+func TestSchedule(t *testing.T) {
+	directory := t.TempDir()
+
+	now := time.Now()
+
+	last := map[string]time.Time{
+		// Due in a moment.
+		"example.com": now.Add(-time.Hour + 500*time.Millisecond),
+		// Not due for an hour.
+		"example.org": now,
+	}
+
+	data, err := json.Marshal(last)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = writeFile(filepath.Join(directory, "state.json"), data); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFake(t, "a.example.com", "a.example.org", "a.example.net")
+
+	m, err := New(directory, 3, Settings{
+		Finder: f.finder,
+		Schedules: []Schedule{
+			{Domain: "example.com", Interval: time.Hour},
+			{Domain: "example.org", Interval: time.Hour},
+			// Never enumerated, so due at once.
+			{Domain: "example.net", Interval: time.Hour},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start(t, m)
+
+	eventually(t, "the domain never enumerated", func() bool {
+		return !m.state.Last("example.net").IsZero()
+	})
+
+	if m.state.Last("example.com").After(now) {
+		t.Error("domain enumerated before its interval elapsed")
+	}
+
+	eventually(t, "the domain due", func() bool {
+		return m.state.Last("example.com").After(now)
+	})
+
+	if ran := m.state.Last("example.com").Sub(now); ran < 400*time.Millisecond {
+		t.Errorf("domain enumerated %s after start, want once due", ran)
+	}
+
+	if !m.state.Last("example.org").Equal(last["example.org"]) {
+		t.Errorf("domain enumerated at %s, want it not due", m.state.Last("example.org"))
+	}
+}
+
+// TestRetry checks that domains whose run failed are retried after retryDelay rather than
+// their interval, and then follow their interval again.
+func TestRetry(t *testing.T) {
+	defer func(delay time.Duration) {
+		retryDelay = delay
+	}(retryDelay)
+
+	retryDelay = 50 * time.Millisecond
+
+	directory := t.TempDir()
+
+	f := newFake(t, "a.example.com")
+
+	m, err := New(directory, 1, Settings{
+		Finder:    f.finder,
+		Schedules: []Schedule{{Domain: "example.com", Interval: time.Hour}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Runs fail recording their completion while the state file cannot be replaced.
+	blocker := filepath.Join(directory, "state.json", "blocker")
+
+	if err = os.MkdirAll(blocker, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	start(t, m)
+
+	eventually(t, "the failed run to be retried", func() bool {
+		return f.runs.Load() >= 2
+	})
+
+	if !m.state.Last("example.com").IsZero() {
+		t.Fatal("failed run recorded as completed")
+	}
+
+	if err = os.RemoveAll(filepath.Dir(blocker)); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "a run to complete", func() bool {
+		return !m.state.Last("example.com").IsZero()
+	})
+
+	runs := f.runs.Load()
+
+	time.Sleep(10 * retryDelay)
+
+	if f.runs.Load() != runs {
+		t.Errorf("got %d runs after a run completed, want none until the interval elapses", f.runs.Load()-runs)
+	}
+}
+
+// TestState checks that the state is persisted, and that a monitor reloading it does not
+// enumerate domains again before their interval elapses.
+func TestState(t *testing.T) {
+	directory := t.TempDir()
+
+	path := filepath.Join(directory, "state.json")
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !state.Last("example.com").IsZero() {
+		t.Fatal("missing state file not empty")
+	}
+
+	ran := time.Now()
+
+	if err = state.Record("example.com", ran); err != nil {
+		t.Fatal(err)
+	}
+
+	if state, err = LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if !state.Last("example.com").Equal(ran) {
+		t.Errorf("got %s, want %s", state.Last("example.com"), ran)
+	}
+
+	f := newFake(t, "a.example.com")
+
+	m, err := New(directory, 1, Settings{
+		Finder:    f.finder,
+		Schedules: []Schedule{{Domain: "example.com", Interval: time.Hour}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start(t, m)
+
+	time.Sleep(300 * time.Millisecond)
+
+	if f.runs.Load() != 0 {
+		t.Error("domain enumerated again after a restart, before its interval elapsed")
+	}
+
+	if err = os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = New(directory, 1, Settings{}); err == nil {
+		t.Error("got no error loading a corrupt state")
+	}
+}
+
+// TestBaseline checks that the first run of a domain establishes its baseline silently,
+// and that the following runs report what changed.
+func TestBaseline(t *testing.T) {
+	f := newFake(t, "a.example.com", "b.example.com")
+
+	notifier := &recorder{}
+
+	m, err := New(t.TempDir(), 1, Settings{
+		Finder:    f.finder,
+		Schedules: []Schedule{{Domain: "example.com", Interval: 100 * time.Millisecond}},
+		Notifiers: []notify.Notifier{notifier},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start(t, m)
+
+	eventually(t, "the baseline run", func() bool {
+		return f.runs.Load() >= 2
+	})
+
+	if events := notifier.sent(); len(events) != 0 {
+		t.Fatalf("got %v, want the baseline established silently", events)
+	}
+
+	f.find(t, "a.example.com", "c.example.com")
+
+	eventually(t, "the changes to be notified", func() bool {
+		return len(notifier.sent()) > 0
+	})
+
+	event := notifier.sent()[0]
+
+	if event.Domain != "example.com" || len(event.Added) != 1 || event.Added[0].Name != "c.example.com" {
+		t.Errorf("got added %v, want c.example.com", event.Added)
+	}
+
+	if !slices.Equal(event.Removed, []string{"b.example.com"}) {
+		t.Errorf("got removed %v, want b.example.com", event.Removed)
+	}
+}
+
+// TestReload checks that domains added by reloading the settings, directly or on SIGHUP,
+// are scheduled at once, and that failing reloads keep the current settings.
+func TestReload(t *testing.T) {
+	f := newFake(t, "a.example.com", "a.example.org")
+
+	m, err := New(t.TempDir(), 1, Settings{Finder: f.finder})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start(t, m)
+
+	m.Reload(Settings{
+		Finder:    f.finder,
+		Schedules: []Schedule{{Domain: "example.com", Interval: time.Hour}},
+	})
+
+	eventually(t, "the reloaded domain", func() bool {
+		return !m.state.Last("example.com").IsZero()
+	})
+
+	hup := make(chan os.Signal, 1)
+
+	signal.Notify(hup, syscall.SIGHUP)
+
+	defer signal.Stop(hup)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	loads := make(chan error, 2)
+
+	go m.ReloadOn(ctx, hup, func() (settings Settings, err error) {
+		err = <-loads
+
+		settings = Settings{
+			Finder:    f.finder,
+			Schedules: []Schedule{{Domain: "example.org", Interval: time.Hour}},
+		}
+
+		return
+	})
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loads <- os.ErrInvalid
+
+	if err = process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "the failing reload", func() bool {
+		return len(loads) == 0
+	})
+
+	time.Sleep(100 * time.Millisecond)
+
+	if !m.state.Last("example.org").IsZero() || len(m.current().Schedules) != 1 || m.current().Schedules[0].Domain != "example.com" {
+		t.Fatal("failing reload replaced the settings")
+	}
+
+	loads <- nil
+
+	if err = process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "the domain added on SIGHUP", func() bool {
+		return !m.state.Last("example.org").IsZero()
+	})
+}
@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// State records when every domain was last enumerated, so that schedules survive
+// restarts. It is persisted as JSON and safe for concurrent use.
+//
+// Fields:
+//   - path (string): The path of the state file.
+//   - mu (sync.Mutex): Guards runs.
+//   - runs (map[string]time.Time): The start of the last completed run, keyed by domain.
+type State struct {
+	path string
+	mu   sync.Mutex
+	runs map[string]time.Time
+}
+
+// Last returns when domain was last enumerated, or the zero time if it never was.
+func (s *State) Last(domain string) (last time.Time) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	last = s.runs[domain]
+
+	return
+}
+
+// Record records that a run of domain started at t completed, and persists the state.
+//
+// Parameters:
+//   - domain (string): The domain.
+//   - t (time.Time): When the run started.
+//
+// Returns:
+//   - err (error): An error if the state could not be written.
+func (s *State) Record(domain string, t time.Time) (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	// The run is recorded once persisted, so that failing to persist it fails the run.
+	runs := maps.Clone(s.runs)
+
+	runs[domain] = t
+
+	var data []byte
+
+	data, err = json.MarshalIndent(runs, "", "    ")
+	if err != nil {
+		return
+	}
+
+	if err = writeFile(s.path, data); err != nil {
+		return
+	}
+
+	s.runs = runs
+
+	return
+}
+
+// LoadState reads the state persisted at path. A missing file is an empty state.
+//
+// Parameters:
+//   - path (string): The path of the state file.
+//
+// Returns:
+//   - state (*State): The state.
+//   - err (error): An error if the file could not be read.
+func LoadState(path string) (state *State, err error) {
+	state = &State{
+		path: path,
+		runs: map[string]time.Time{},
+	}
+
+	var data []byte
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+
+		return
+	}
+
+	err = json.Unmarshal(data, &state.runs)
+
+	return
+}
+
+// writeFile atomically replaces the file at path with data: it is written to a temporary
+// file in the same directory, which is then renamed over path.
+func writeFile(path string, data []byte) (err error) {
+	directory := filepath.Dir(path)
+
+	if err = os.MkdirAll(directory, 0o750); err != nil {
+		return
+	}
+
+	var file *os.File
+
+	file, err = os.CreateTemp(directory, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			file.Close()
+
+			os.Remove(file.Name())
+		}
+	}()
+
+	if _, err = file.Write(data); err != nil {
+		return
+	}
+
+	if err = file.Sync(); err != nil {
+		return
+	}
+
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(file.Name(), path)
+
+	return
+}
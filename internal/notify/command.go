@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// CommandNotifier runs a command for every event, with the event in JSON on its
+// standard input.
+//
+// Fields:
+//   - command ([]string): The program and its arguments.
+type CommandNotifier struct {
+	command []string
+}
+
+func (n *CommandNotifier) Name() (name string) {
+	name = "command " + n.command[0]
+
+	return
+}
+
+func (n *CommandNotifier) Notify(ctx context.Context, event Event) (err error) {
+	var payload []byte
+
+	payload, err = json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, n.command[0], n.command[1:]...) //nolint:gosec // The command comes from the configuration.
+
+	cmd.Stdin = bytes.NewReader(payload)
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	if err = cmd.Run(); err != nil {
+		if stderr.Len() > 0 {
+			err = fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+
+		return
+	}
+
+	return
+}
+
+// NewCommandNotifier returns a notifier running command.
+//
+// Parameters:
+//   - command ([]string): The program and its arguments.
+//
+// Returns:
+//   - notifier (*CommandNotifier): The notifier.
+//   - err (error): An error if command is empty.
+func NewCommandNotifier(command []string) (notifier *CommandNotifier, err error) {
+	if len(command) == 0 {
+		err = fmt.Errorf("%w: command notifier without a command", ErrNotifier)
+
+		return
+	}
+
+	notifier = &CommandNotifier{
+		command: command,
+	}
+
+	return
+}
@@ -0,0 +1,129 @@
+// Package notify dispatches the subdomains found by a run, or the changes found by diff
+// and monitor mode, to external services: generic JSON webhooks, Slack and Discord
+// incoming webhooks, and local commands.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Notifier dispatches events.
+type Notifier interface {
+	// Name returns the name identifying the notifier in logs.
+	Name() (name string)
+	// Notify dispatches event. It should return once the event is delivered or ctx is done.
+	Notify(ctx context.Context, event Event) (err error)
+}
+
+// Event describes what a run found for a domain.
+//
+// Fields:
+//   - Domain (string): The target domain.
+//   - Time (time.Time): When the run started.
+//   - Added ([]Subdomain): The subdomains found, or in diff and monitor mode the subdomains
+//     absent from the previous run, sorted.
+//   - Removed ([]string): In diff and monitor mode, the subdomains of the previous run this
+//     run did not find, sorted.
+type Event struct {
+	Domain  string      `json:"domain"`
+	Time    time.Time   `json:"time"`
+	Added   []Subdomain `json:"added"`
+	Removed []string    `json:"removed,omitempty"`
+}
+
+// Subdomain is a subdomain reported in an Event.
+//
+// Fields:
+//   - Name (string): The subdomain.
+//   - Sources ([]string): The sources that reported it.
+//   - Score (float64): Its confidence score.
+type Subdomain struct {
+	Name    string   `json:"subdomain"`
+	Sources []string `json:"sources"`
+	Score   float64  `json:"score,omitempty"`
+}
+
+// Configuration configures a notifier, as an entry of the `notifiers` list.
+//
+// Fields:
+//   - Type (string): The kind of notifier: "webhook", "slack", "discord" or "command".
+//   - URL (string): For webhooks, the URL events are posted to.
+//   - Headers (map[string]string): For webhooks, extra request headers, e.g. Authorization.
+//   - Template (string): For webhooks, a text/template rendering each message from a
+//     Batch; for "webhook" it renders the whole request body.
+//   - BatchSize (int): For webhooks, the maximum number of subdomains per message, or 0 for
+//     the default of the type.
+//   - Retries (int): For webhooks, how many times a failed delivery is retried, or 0 for
+//     DefaultRetries. Negative values disable retries.
+//   - Command ([]string): For "command", the program and its arguments.
+type Configuration struct {
+	Type      string            `yaml:"type"`
+	URL       string            `yaml:"url"`
+	Headers   map[string]string `yaml:"headers"`
+	Template  string            `yaml:"template"`
+	BatchSize int               `yaml:"batchsize"`
+	Retries   int               `yaml:"retries"`
+	Command   []string          `yaml:"command"`
+}
+
+// New returns the notifier configured by cfg.
+//
+// Parameters:
+//   - cfg (Configuration): The configuration of the notifier.
+//
+// Returns:
+//   - notifier (Notifier): The notifier.
+//   - err (error): An error wrapping ErrNotifier if cfg is invalid.
+func New(cfg Configuration) (notifier Notifier, err error) {
+	switch cfg.Type {
+	case TypeWebhook, TypeSlack, TypeDiscord:
+		notifier, err = NewWebhookNotifier(cfg)
+	case TypeCommand:
+		notifier, err = NewCommandNotifier(cfg.Command)
+	default:
+		err = fmt.Errorf("%w: unknown type %q", ErrNotifier, cfg.Type)
+	}
+
+	return
+}
+
+// Dispatch sends event to every notifier, and returns the errors of those that failed,
+// joined.
+//
+// Parameters:
+//   - ctx (context.Context): Bounds the deliveries.
+//   - notifiers ([]Notifier): The notifiers.
+//   - event (Event): The event.
+//
+// Returns:
+//   - err (error): The delivery errors, each prefixed with the notifier's name, or nil.
+func Dispatch(ctx context.Context, notifiers []Notifier, event Event) (err error) {
+	errs := []error{}
+
+	for _, notifier := range notifiers {
+		if nerr := notifier.Notify(ctx, event); nerr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), nerr))
+		}
+	}
+
+	err = errors.Join(errs...)
+
+	return
+}
+
+// Notifier types.
+const (
+	TypeWebhook = "webhook"
+	TypeSlack   = "slack"
+	TypeDiscord = "discord"
+	TypeCommand = "command"
+)
+
+// timeout bounds a single delivery attempt.
+const timeout = 30 * time.Second
+
+// ErrNotifier is returned for invalid notifier configurations.
+var ErrNotifier = errors.New("invalid notifier")
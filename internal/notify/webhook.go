@@ -0,0 +1,339 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// Batch is a part of an Event small enough to be sent as a single message. It is what
+// message templates are rendered from, and the body of "webhook" notifications without a
+// template.
+//
+// Fields:
+//   - Event: The part of the event; Added and Removed hold this batch's subdomains only.
+//   - Index (int): The position of the batch, from 1.
+//   - Count (int): The number of batches the event was split into.
+type Batch struct {
+	Event
+
+	Index int `json:"batch"`
+	Count int `json:"batches"`
+}
+
+// WebhookNotifier posts events to an HTTP endpoint, split into batches of at most
+// batchSize subdomains, one request per batch. Depending on its type, each request
+// carries:
+//
+//   - "webhook": the Batch in JSON, or the rendered template.
+//   - "slack": a Slack incoming webhook message, {"text": ...}, rendered from the template.
+//   - "discord": a Discord webhook message, {"content": ...}, rendered from the template and
+//     cut to Discord's 2000 characters.
+//
+// Failed deliveries, i.e. transport errors, 429 and 5xx responses, are retried with
+// exponential backoff, honoring Retry-After.
+//
+// Fields:
+//   - kind (string): The type of the notifier.
+//   - endpoint (string): The URL events are posted to.
+//   - headers (map[string]string): Extra request headers.
+//   - template (*template.Template): The message template, or nil.
+//   - batchSize (int): The maximum number of subdomains per request.
+//   - retries (int): How many times a failed request is retried.
+//   - client (*http.Client): The HTTP client.
+type WebhookNotifier struct {
+	kind      string
+	endpoint  string
+	headers   map[string]string
+	template  *template.Template
+	batchSize int
+	retries   int
+	client    *http.Client
+}
+
+func (n *WebhookNotifier) Name() (name string) {
+	name = n.kind
+
+	if parsed, err := url.Parse(n.endpoint); err == nil {
+		name += " " + parsed.Host
+	}
+
+	return
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, event Event) (err error) {
+	for _, batch := range split(event, n.batchSize) {
+		var body []byte
+
+		body, err = n.render(batch)
+		if err != nil {
+			return
+		}
+
+		if err = n.post(ctx, body); err != nil {
+			err = fmt.Errorf("batch %d/%d: %w", batch.Index, batch.Count, err)
+
+			return
+		}
+	}
+
+	return
+}
+
+// render returns the request body of batch.
+func (n *WebhookNotifier) render(batch Batch) (body []byte, err error) {
+	if n.template == nil {
+		body, err = json.Marshal(batch)
+
+		return
+	}
+
+	var message bytes.Buffer
+
+	if err = n.template.Execute(&message, batch); err != nil {
+		return
+	}
+
+	switch n.kind {
+	case TypeSlack:
+		body, err = json.Marshal(map[string]string{"text": message.String()})
+	case TypeDiscord:
+		body, err = json.Marshal(map[string]string{"content": truncate(message.String(), discordMaxLength)})
+	default:
+		body = message.Bytes()
+	}
+
+	return
+}
+
+// post sends body, retrying failed attempts.
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) (err error) {
+	wait := retryWaitMin
+
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+
+		retryAfter, err = n.attempt(ctx, body)
+		if err == nil || retryAfter < 0 || attempt >= n.retries {
+			return
+		}
+
+		if retryAfter == 0 {
+			retryAfter = wait
+
+			wait = min(2*wait, retryWaitMax)
+		}
+
+		timer := time.NewTimer(retryAfter)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			err = ctx.Err()
+
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends body once. On failure, retryAfter is negative if the request must not be
+// retried, the delay the server asked for, or 0 to back off as usual.
+func (n *WebhookNotifier) attempt(ctx context.Context, body []byte) (retryAfter time.Duration, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	defer cancel()
+
+	var req *http.Request
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint, bytes.NewReader(body))
+	if err != nil {
+		retryAfter = -1
+
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range n.headers {
+		req.Header.Set(key, value)
+	}
+
+	var res *http.Response
+
+	res, err = n.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	defer res.Body.Close()
+
+	message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		return
+	}
+
+	err = fmt.Errorf("%w: status %d: %s", ErrDelivery, res.StatusCode, bytes.TrimSpace(message))
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests, res.StatusCode >= http.StatusInternalServerError:
+		if seconds, perr := strconv.Atoi(res.Header.Get("Retry-After")); perr == nil && seconds > 0 {
+			retryAfter = min(time.Duration(seconds)*time.Second, retryWaitMax)
+		}
+	default:
+		retryAfter = -1
+	}
+
+	return
+}
+
+// split splits event into batches of at most size subdomains, added ones first.
+func split(event Event, size int) (batches []Batch) {
+	added, removed := event.Added, event.Removed
+
+	for len(batches) == 0 || len(added)+len(removed) > 0 {
+		batch := Batch{
+			Event: Event{
+				Domain: event.Domain,
+				Time:   event.Time,
+				Added:  []Subdomain{},
+			},
+		}
+
+		n := min(size, len(added))
+
+		batch.Added, added = added[:n], added[n:]
+
+		if n < size {
+			m := min(size-n, len(removed))
+
+			batch.Removed, removed = removed[:m], removed[m:]
+		}
+
+		batches = append(batches, batch)
+	}
+
+	for i := range batches {
+		batches[i].Index = i + 1
+		batches[i].Count = len(batches)
+	}
+
+	return
+}
+
+// truncate cuts s to at most length characters, marking the cut with an ellipsis.
+func truncate(s string, length int) string {
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+
+	runes := []rune(s)
+
+	return string(runes[:length-1]) + "…"
+}
+
+// NewWebhookNotifier returns the webhook notifier configured by cfg, whose Type is one of
+// TypeWebhook, TypeSlack and TypeDiscord.
+//
+// Parameters:
+//   - cfg (Configuration): The configuration of the notifier.
+//
+// Returns:
+//   - notifier (*WebhookNotifier): The notifier.
+//   - err (error): An error wrapping ErrNotifier if cfg is invalid.
+func NewWebhookNotifier(cfg Configuration) (notifier *WebhookNotifier, err error) {
+	parsed, err := url.Parse(cfg.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		err = fmt.Errorf("%w: %s notifier needs an http(s) URL", ErrNotifier, cfg.Type)
+
+		return
+	}
+
+	notifier = &WebhookNotifier{
+		kind:      cfg.Type,
+		endpoint:  cfg.URL,
+		headers:   cfg.Headers,
+		batchSize: cfg.BatchSize,
+		retries:   cfg.Retries,
+		client:    &http.Client{},
+	}
+
+	if notifier.batchSize <= 0 {
+		notifier.batchSize = DefaultBatchSizes[cfg.Type]
+	}
+
+	if notifier.retries == 0 {
+		notifier.retries = DefaultRetries
+	}
+
+	text := cfg.Template
+
+	if text == "" {
+		text = DefaultTemplates[cfg.Type]
+	}
+
+	if text == "" {
+		return
+	}
+
+	notifier.template, err = template.New(cfg.Type).Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		notifier = nil
+
+		err = fmt.Errorf("%w: %w", ErrNotifier, err)
+
+		return
+	}
+
+	return
+}
+
+const (
+	// DefaultRetries is how many times a failed delivery is retried unless configured.
+	DefaultRetries = 3
+
+	discordMaxLength = 2000
+	retryWaitMin     = time.Second
+	retryWaitMax     = 30 * time.Second
+)
+
+var (
+	// DefaultBatchSizes is the maximum number of subdomains per message of each webhook
+	// type, unless configured. Chat messages are kept short enough to read, and within
+	// Discord's length limit.
+	DefaultBatchSizes = map[string]int{
+		TypeWebhook: 1000,
+		TypeSlack:   50,
+		TypeDiscord: 25,
+	}
+
+	// DefaultTemplates are the message templates of the chat webhook types, unless
+	// configured. "webhook" notifications have none: they carry the Batch in JSON.
+	DefaultTemplates = map[string]string{
+		TypeSlack: `*{{.Domain}}*{{if gt .Count 1}} ({{.Index}}/{{.Count}}){{end}}
+{{range .Added}}:new: ` + "`{{.Name}}`" + `
+{{end}}{{range .Removed}}:x: ` + "`{{.}}`" + `
+{{end}}`,
+		TypeDiscord: `**{{.Domain}}**{{if gt .Count 1}} ({{.Index}}/{{.Count}}){{end}}
+{{range .Added}}+ ` + "`{{.Name}}`" + `
+{{end}}{{range .Removed}}- ` + "`{{.}}`" + `
+{{end}}`,
+	}
+
+	// ErrDelivery is returned when a webhook endpoint rejects a request.
+	ErrDelivery = errors.New("delivery failed")
+)
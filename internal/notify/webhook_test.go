@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// endpoint is a webhook endpoint recording the requests it receives, and answering each
+// with the next of its responses, then with 204.
+type endpoint struct {
+	mu        sync.Mutex
+	bodies    [][]byte
+	times     []time.Time
+	responses []response
+}
+
+// response is an answer of an endpoint.
+type response struct {
+	status     int
+	retryAfter string
+}
+
+func (e *endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	e.mu.Lock()
+
+	defer e.mu.Unlock()
+
+	e.bodies = append(e.bodies, body)
+	e.times = append(e.times, time.Now())
+
+	if len(e.responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	res := e.responses[0]
+
+	e.responses = e.responses[1:]
+
+	if res.retryAfter != "" {
+		w.Header().Set("Retry-After", res.retryAfter)
+	}
+
+	w.WriteHeader(res.status)
+}
+
+// notify sends event to a notifier of cfg posting to e, and returns the error.
+func (e *endpoint) notify(t *testing.T, cfg Configuration, event Event) (err error) {
+	t.Helper()
+
+	server := httptest.NewServer(e)
+
+	defer server.Close()
+
+	cfg.URL = server.URL
+
+	notifier, err := NewWebhookNotifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = notifier.Notify(context.Background(), event)
+
+	return
+}
+
+// event returns an event of example.com with the given numbers of added and removed
+// subdomains.
+func event(added, removed int) (e Event) {
+	e = Event{
+		Domain: "example.com",
+		Time:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Added:  []Subdomain{},
+	}
+
+	for i := range added {
+		e.Added = append(e.Added, Subdomain{Name: fmt.Sprintf("a%d.example.com", i), Sources: []string{"crtsh"}})
+	}
+
+	for i := range removed {
+		e.Removed = append(e.Removed, fmt.Sprintf("r%d.example.com", i))
+	}
+
+	return
+}
+
+func TestWebhookBatches(t *testing.T) {
+	e := &endpoint{}
+
+	if err := e.notify(t, Configuration{Type: TypeWebhook, BatchSize: 3}, event(5, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.bodies) != 3 {
+		t.Fatalf("got %d requests, want 3", len(e.bodies))
+	}
+
+	want := []struct{ added, removed int }{{3, 0}, {2, 1}, {0, 1}}
+
+	for i, body := range e.bodies {
+		var batch Batch
+
+		if err := json.Unmarshal(body, &batch); err != nil {
+			t.Fatalf("batch %d: %v: %s", i+1, err, body)
+		}
+
+		if batch.Domain != "example.com" || batch.Index != i+1 || batch.Count != 3 {
+			t.Errorf("batch %d: got %+v", i+1, batch)
+		}
+
+		if len(batch.Added) != want[i].added || len(batch.Removed) != want[i].removed {
+			t.Errorf("batch %d: got %d added and %d removed, want %d and %d", i+1, len(batch.Added), len(batch.Removed), want[i].added, want[i].removed)
+		}
+	}
+}
+
+func TestWebhookSlack(t *testing.T) {
+	e := &endpoint{}
+
+	if err := e.notify(t, Configuration{Type: TypeSlack, BatchSize: 2}, event(2, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.bodies) != 2 {
+		t.Fatalf("got %d requests, want 2", len(e.bodies))
+	}
+
+	messages := []string{}
+
+	for _, body := range e.bodies {
+		var message map[string]string
+
+		if err := json.Unmarshal(body, &message); err != nil || len(message) != 1 {
+			t.Fatalf("got %s, want a Slack message", body)
+		}
+
+		messages = append(messages, message["text"])
+	}
+
+	if !strings.HasPrefix(messages[0], "*example.com* (1/2)\n") || !strings.Contains(messages[0], ":new: `a1.example.com`") {
+		t.Errorf("got first message %q", messages[0])
+	}
+
+	if !strings.HasPrefix(messages[1], "*example.com* (2/2)\n") || !strings.Contains(messages[1], ":x: `r0.example.com`") {
+		t.Errorf("got second message %q", messages[1])
+	}
+}
+
+func TestWebhookDiscord(t *testing.T) {
+	e := &endpoint{}
+
+	if err := e.notify(t, Configuration{Type: TypeDiscord, BatchSize: 200}, event(200, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.bodies) != 1 {
+		t.Fatalf("got %d requests, want 1", len(e.bodies))
+	}
+
+	var message map[string]string
+
+	if err := json.Unmarshal(e.bodies[0], &message); err != nil || len(message) != 1 {
+		t.Fatalf("got %s, want a Discord message", e.bodies[0])
+	}
+
+	content := message["content"]
+
+	if !strings.HasPrefix(content, "**example.com**\n+ `a0.example.com`") {
+		t.Errorf("got %q", content)
+	}
+
+	if utf8.RuneCountInString(content) != discordMaxLength || !strings.HasSuffix(content, "…") {
+		t.Errorf("got %d characters, want the message cut to %d", utf8.RuneCountInString(content), discordMaxLength)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	e := &endpoint{
+		responses: []response{
+			{status: http.StatusTooManyRequests, retryAfter: "1"},
+			{status: http.StatusServiceUnavailable, retryAfter: "1"},
+		},
+	}
+
+	if err := e.notify(t, Configuration{Type: TypeWebhook}, event(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.bodies) != 3 {
+		t.Fatalf("got %d requests, want 3", len(e.bodies))
+	}
+
+	for i := 1; i < len(e.times); i++ {
+		if wait := e.times[i].Sub(e.times[i-1]); wait < time.Second {
+			t.Errorf("retry %d sent after %v, before the %v asked for", i, wait, time.Second)
+		}
+	}
+
+	if string(e.bodies[0]) != string(e.bodies[2]) {
+		t.Errorf("retried with %s, want %s", e.bodies[2], e.bodies[0])
+	}
+}
+
+func TestWebhookRetryExhausted(t *testing.T) {
+	e := &endpoint{
+		responses: []response{
+			{status: http.StatusBadGateway, retryAfter: "1"},
+			{status: http.StatusBadGateway, retryAfter: "1"},
+		},
+	}
+
+	err := e.notify(t, Configuration{Type: TypeWebhook, Retries: 1}, event(1, 0))
+	if !errors.Is(err, ErrDelivery) {
+		t.Fatalf("got %v, want %v", err, ErrDelivery)
+	}
+
+	if len(e.bodies) != 2 {
+		t.Errorf("got %d requests, want 2", len(e.bodies))
+	}
+}
+
+func TestWebhookNoRetry(t *testing.T) {
+	e := &endpoint{
+		responses: []response{
+			{status: http.StatusBadRequest},
+		},
+	}
+
+	err := e.notify(t, Configuration{Type: TypeWebhook}, event(1, 0))
+	if !errors.Is(err, ErrDelivery) || !strings.Contains(err.Error(), "batch 1/1") {
+		t.Fatalf("got %v, want %v", err, ErrDelivery)
+	}
+
+	if len(e.bodies) != 1 {
+		t.Errorf("got %d requests, want a rejected request not to be retried", len(e.bodies))
+	}
+}
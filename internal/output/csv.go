@@ -0,0 +1,327 @@
+package output
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// CSVFormat writes a header and one row per result, with the configured columns. Cells
+// holding several values, such as the sources column, separate them with ";".
+//
+// The "ips" column lists the addresses of each host, taken from the IP address and A and
+// AAAA record results found for it; request those with `--include ip,record`. As
+// addresses may arrive after their host, the rows of a domain are buffered until the
+// domain is flushed, or until Close, when the column is selected.
+//
+// Fields:
+//   - Columns ([]string): The columns, in order; see CSVColumns.
+type CSVFormat struct {
+	Columns []string
+}
+
+// NewCSVFormat returns a CSV format with the given columns, or DefaultCSVColumns if none
+// are given.
+//
+// Parameters:
+//   - columns ([]string): The columns, in order; see CSVColumns.
+//
+// Returns:
+//   - format (*CSVFormat): The format.
+//   - err (error): ErrUnknownColumn if a column is not one of CSVColumns.
+func NewCSVFormat(columns []string) (format *CSVFormat, err error) {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	columns = slices.Clone(columns)
+
+	for i, column := range columns {
+		columns[i] = strings.ToLower(strings.TrimSpace(column))
+
+		if !slices.Contains(CSVColumns, columns[i]) {
+			err = fmt.Errorf("%w: %q", ErrUnknownColumn, column)
+
+			return
+		}
+	}
+
+	format = &CSVFormat{
+		Columns: columns,
+	}
+
+	return
+}
+
+func (format *CSVFormat) Name() (name string) {
+	return "csv"
+}
+
+func (format *CSVFormat) Extension() (extension string) {
+	return ".csv"
+}
+
+func (format *CSVFormat) Appendable() (ok bool) {
+	return false
+}
+
+func (format *CSVFormat) NewEncoder(writer io.Writer) (encoder Encoder) {
+	return &csvEncoder{
+		columns:  format.Columns,
+		writer:   csv.NewWriter(writer),
+		buffered: slices.Contains(format.Columns, "ips"),
+		rows:     map[string][]sources.Result{},
+		ips:      map[string]map[string][]string{},
+	}
+}
+
+// csvEncoder writes CSV rows. When buffered, the rows and the addresses of the hosts of
+// every domain are kept, keyed by domain, until the domain is flushed; domains are
+// written in the order they were first seen.
+type csvEncoder struct {
+	columns  []string
+	writer   *csv.Writer
+	header   bool
+	buffered bool
+	domains  []string
+	rows     map[string][]sources.Result
+	ips      map[string]map[string][]string
+}
+
+func (e *csvEncoder) Encode(domain string, result sources.Result) (err error) {
+	if e.buffered {
+		if _, ok := e.rows[domain]; !ok {
+			e.domains = append(e.domains, domain)
+			e.ips[domain] = map[string][]string{}
+		}
+
+		e.collect(domain, result)
+
+		e.rows[domain] = append(e.rows[domain], result)
+
+		return
+	}
+
+	err = e.write(domain, result)
+
+	return
+}
+
+func (e *csvEncoder) Flush(domain string) (err error) {
+	if !e.buffered {
+		return
+	}
+
+	err = e.flush(domain)
+
+	e.domains = slices.DeleteFunc(e.domains, func(d string) bool {
+		return d == domain
+	})
+
+	if err != nil {
+		return
+	}
+
+	e.writer.Flush()
+
+	err = e.writer.Error()
+
+	return
+}
+
+// flush writes the buffered rows of domain and forgets them.
+func (e *csvEncoder) flush(domain string) (err error) {
+	rows := e.rows[domain]
+
+	defer func() {
+		delete(e.rows, domain)
+		delete(e.ips, domain)
+	}()
+
+	for _, result := range rows {
+		if err = e.write(domain, result); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (e *csvEncoder) Close() (err error) {
+	for _, domain := range e.domains {
+		if err = e.flush(domain); err != nil {
+			return
+		}
+	}
+
+	e.domains = nil
+
+	if !e.header {
+		if err = e.writeHeader(); err != nil {
+			return
+		}
+	}
+
+	e.writer.Flush()
+
+	err = e.writer.Error()
+
+	return
+}
+
+// collect records the address carried by result, found for domain, if any, for the ips
+// column.
+func (e *csvEncoder) collect(domain string, result sources.Result) {
+	host := result.Metadata[sources.MetadataHost]
+
+	if host == "" {
+		return
+	}
+
+	switch {
+	case result.Type == sources.ResultIP:
+	case result.Type == sources.ResultDNSRecord && (result.Metadata[sources.MetadataRecordType] == "A" || result.Metadata[sources.MetadataRecordType] == "AAAA"):
+	default:
+		return
+	}
+
+	if !slices.Contains(e.ips[domain][host], result.Value) {
+		e.ips[domain][host] = append(e.ips[domain][host], result.Value)
+	}
+}
+
+func (e *csvEncoder) writeHeader() (err error) {
+	e.header = true
+
+	err = e.writer.Write(e.columns)
+
+	return
+}
+
+func (e *csvEncoder) write(domain string, result sources.Result) (err error) {
+	if !e.header {
+		if err = e.writeHeader(); err != nil {
+			return
+		}
+	}
+
+	cells := make([]string, len(e.columns))
+
+	for i, column := range e.columns {
+		cells[i] = sanitize(e.cell(column, domain, result))
+	}
+
+	if err = e.writer.Write(cells); err != nil {
+		return
+	}
+
+	if !e.buffered {
+		e.writer.Flush()
+
+		err = e.writer.Error()
+	}
+
+	return
+}
+
+func (e *csvEncoder) cell(column, domain string, result sources.Result) (value string) {
+	switch column {
+	case "domain":
+		value = domain
+	case "type":
+		value = result.Type.String()
+	case "value":
+		value = result.Value
+	case "host":
+		value = result.Metadata[sources.MetadataHost]
+
+		if result.Type == sources.ResultSubdomain {
+			value = result.Value
+		}
+	case "source":
+		value = result.Source
+	case "sources":
+		value = strings.Join(result.Sources, ";")
+
+		if value == "" {
+			value = result.Source
+		}
+	case "origin":
+		value = result.Origin
+	case "first_seen":
+		value = formatTime(result.FirstSeen)
+	case "last_seen":
+		value = formatTime(result.LastSeen)
+	case "score":
+		if result.Type == sources.ResultSubdomain {
+			value = strconv.FormatFloat(result.Score, 'f', -1, 64)
+		}
+	case "expired":
+		value = result.Metadata[sources.MetadataExpired]
+	case "change":
+		value = result.Metadata[MetadataChange]
+	case "ips":
+		host := result.Metadata[sources.MetadataHost]
+
+		if result.Type == sources.ResultSubdomain {
+			host = result.Value
+		}
+
+		value = strings.Join(e.ips[domain][host], ";")
+	}
+
+	return
+}
+
+// formatTime formats t as RFC 3339, or returns "" for the zero time.
+func formatTime(t time.Time) (formatted string) {
+	if !t.IsZero() {
+		formatted = t.Format(time.RFC3339)
+	}
+
+	return
+}
+
+// sanitize prevents spreadsheet applications from evaluating a cell as a formula, by
+// prefixing cells starting with a formula character with a single quote. Quoting of
+// separators, quotes and line breaks is left to encoding/csv.
+func sanitize(cell string) (sanitized string) {
+	sanitized = cell
+
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		sanitized = "'" + cell
+	}
+
+	return
+}
+
+var (
+	// CSVColumns are the columns supported by CSVFormat:
+	//
+	//   - domain: The target domain.
+	//   - type: The result type, e.g. "subdomain" or "ip".
+	//   - value: The subdomain, address, URL, record data or AS number.
+	//   - host: The host the result belongs to; for subdomains, the subdomain itself.
+	//   - source: The source credited with the result.
+	//   - sources: Every source that had reported the subdomain when it was output.
+	//   - origin: The host an enrichment result was derived from.
+	//   - first_seen, last_seen: When the result was first and last seen, in RFC 3339.
+	//   - score: The confidence score of the subdomain.
+	//   - expired: "true" if the name was taken from an expired certificate.
+	//   - change: In diff mode, "added" or "removed".
+	//   - ips: The addresses of the host.
+	CSVColumns = []string{"domain", "type", "value", "host", "source", "sources", "origin", "first_seen", "last_seen", "score", "expired", "change", "ips"}
+
+	// DefaultCSVColumns are the columns used when none are configured.
+	DefaultCSVColumns = []string{"domain", "type", "value", "sources", "first_seen", "last_seen", "score"}
+
+	// ErrUnknownColumn is returned by NewCSVFormat for columns that are not CSVColumns.
+	ErrUnknownColumn = errors.New("unknown CSV column")
+)
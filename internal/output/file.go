@@ -1,23 +1,28 @@
 package output
 
 import (
-	"bufio"
-	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
 )
 
+// Writer writes results in its Format to any number of destinations, keeping one Encoder
+// per destination. Close must be called once writing is done, before the destinations
+// are closed, so that buffered formats are written out.
 type Writer struct {
-	format format
+	format   Format
+	mu       sync.Mutex
+	encoders map[io.Writer]Encoder
+	order    []io.Writer
 }
 
-func (w *Writer) SetFormatToJSONL() {
-	w.format = formatJSONL
+func (w *Writer) SetFormat(format Format) {
+	w.format = format
 }
 
 func (w *Writer) CreateFile(path string) (file *os.File, err error) {
@@ -27,17 +32,14 @@ func (w *Writer) CreateFile(path string) (file *os.File, err error) {
 		return
 	}
 
-	extension := filepath.Ext(path)
+	extensions := []string{w.format.Extension()}
 
-	switch w.format {
-	case formatTXT:
-		if extension != ".txt" {
-			path += ".txt"
-		}
-	case formatJSONL:
-		if extension != ".json" {
-			path += ".json"
-		}
+	if aliased, ok := w.format.(interface{ Extensions() []string }); ok {
+		extensions = aliased.Extensions()
+	}
+
+	if !slices.Contains(extensions, filepath.Ext(path)) {
+		path += w.format.Extension()
 	}
 
 	directory := filepath.Dir(path)
@@ -51,7 +53,13 @@ func (w *Writer) CreateFile(path string) (file *os.File, err error) {
 		}
 	}
 
-	file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+
+	if w.format.Appendable() {
+		flag = os.O_APPEND | os.O_CREATE | os.O_WRONLY
+	}
+
+	file, err = os.OpenFile(path, flag, 0o600)
 	if err != nil {
 		return
 	}
@@ -60,73 +68,96 @@ func (w *Writer) CreateFile(path string) (file *os.File, err error) {
 }
 
 func (w *Writer) Write(writer io.Writer, domain string, result sources.Result) (err error) {
-	switch w.format {
-	case formatTXT:
-		err = w.writeTXT(writer, result)
-	case formatJSONL:
-		err = w.writeJSON(writer, domain, result)
+	w.mu.Lock()
+
+	defer w.mu.Unlock()
+
+	encoder, ok := w.encoders[writer]
+	if !ok {
+		encoder = w.format.NewEncoder(writer)
+
+		w.encoders[writer] = encoder
+		w.order = append(w.order, writer)
 	}
 
+	err = encoder.Encode(domain, result)
+
 	return
 }
 
-func (w *Writer) writeTXT(writer io.Writer, result sources.Result) (err error) {
-	bw := bufio.NewWriter(writer)
+// Flush writes out the output buffered for domain to every destination, once every
+// result of domain has been written.
+func (w *Writer) Flush(domain string) (err error) {
+	w.mu.Lock()
 
-	fmt.Fprintln(bw, result.Value)
+	defer w.mu.Unlock()
 
-	if err = bw.Flush(); err != nil {
-		return
+	errs := []error{}
+
+	for _, writer := range w.order {
+		if err := w.encoders[writer].Flush(domain); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
+	err = errors.Join(errs...)
+
 	return
 }
 
-func (w *Writer) writeJSON(writer io.Writer, domain string, result sources.Result) (err error) {
-	data := resultForJSONL{
-		Domain:    domain,
-		Subdomain: result.Value,
-		Source:    result.Source,
-	}
+// Release writes out the output buffered for writer and forgets it, so that it can be
+// closed, e.g. once the single domain written to it has finished. The destination itself
+// is not closed.
+func (w *Writer) Release(writer io.Writer) (err error) {
+	w.mu.Lock()
 
-	var dataJSONBytes []byte
+	defer w.mu.Unlock()
 
-	dataJSONBytes, err = json.Marshal(data)
-	if err != nil {
+	encoder, ok := w.encoders[writer]
+	if !ok {
 		return
 	}
 
-	dataJSONString := string(dataJSONBytes)
+	delete(w.encoders, writer)
 
-	bw := bufio.NewWriter(writer)
+	w.order = slices.DeleteFunc(w.order, func(o io.Writer) bool {
+		return o == writer
+	})
 
-	fmt.Fprintln(bw, dataJSONString)
-
-	if err = bw.Flush(); err != nil {
-		return
-	}
+	err = encoder.Close()
 
 	return
 }
 
-type format string
+// Close writes out the output buffered for every destination, in the order the
+// destinations were first written to. The destinations themselves are not closed.
+func (w *Writer) Close() (err error) {
+	w.mu.Lock()
 
-type resultForJSONL struct {
-	Domain    string `json:"domain"`
-	Subdomain string `json:"subdomain"`
-	Source    string `json:"source"`
-}
+	defer w.mu.Unlock()
 
-const (
-	formatJSONL format = "JSON"
-	formatTXT   format = "TXT"
-)
+	errs := []error{}
+
+	for _, writer := range w.order {
+		if err := w.encoders[writer].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	w.encoders = map[io.Writer]Encoder{}
+	w.order = nil
+
+	err = errors.Join(errs...)
+
+	return
+}
 
 var ErrNoFilePathSpecified = errors.New("no file path specified")
 
 func NewWriter() (writter *Writer) {
 	writter = &Writer{
-		format: formatTXT,
+		format:   TXTFormat{},
+		encoders: map[io.Writer]Encoder{},
 	}
 
 	return
@@ -0,0 +1,175 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+func subdomain(value string) sources.Result {
+	return sources.Result{Type: sources.ResultSubdomain, Source: sources.CRTSH, Value: value}
+}
+
+func TestWriterFlushJSON(t *testing.T) {
+	writer := NewWriter()
+
+	writer.SetFormat(JSONFormat{})
+
+	buffer := &bytes.Buffer{}
+
+	for _, write := range []struct {
+		domain string
+		result sources.Result
+	}{
+		{"example.com", subdomain("a.example.com")},
+		{"example.org", subdomain("a.example.org")},
+		{"example.com", subdomain("b.example.com")},
+	} {
+		if err := writer.Write(buffer, write.domain, write.result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if buffer.Len() != 0 {
+		t.Fatalf("documents written before their domain finished: %s", buffer)
+	}
+
+	if err := writer.Flush("example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc document
+
+	if err := json.Unmarshal(buffer.Bytes(), &doc); err != nil {
+		t.Fatalf("flushing a domain did not write its document: %v: %s", err, buffer)
+	}
+
+	if doc.Domain != "example.com" || len(doc.Results) != 2 {
+		t.Errorf("got %+v, want the 2 results of example.com", doc)
+	}
+
+	buffer.Reset()
+
+	// The documents of domains that were not flushed are written on Close, once.
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(buffer.String(), `"domain"`) != 1 || !strings.Contains(buffer.String(), "a.example.org") {
+		t.Errorf("got %s, want the document of example.org only", buffer)
+	}
+}
+
+func TestWriterFlushCSV(t *testing.T) {
+	format, err := NewCSVFormat([]string{"domain", "value", "ips"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := NewWriter()
+
+	writer.SetFormat(format)
+
+	buffer := &bytes.Buffer{}
+
+	ip := sources.Result{
+		Type:     sources.ResultIP,
+		Source:   sources.SHODAN,
+		Value:    "192.0.2.1",
+		Metadata: map[string]string{sources.MetadataHost: "a.example.com"},
+	}
+
+	_ = writer.Write(buffer, "example.com", subdomain("a.example.com"))
+	_ = writer.Write(buffer, "example.org", subdomain("a.example.org"))
+	_ = writer.Write(buffer, "example.com", ip)
+
+	if buffer.Len() != 0 {
+		t.Fatalf("rows written before their domain finished: %s", buffer)
+	}
+
+	if err = writer.Flush("example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+	if len(lines) != 3 || lines[0] != "domain,value,ips" || lines[1] != "example.com,a.example.com,192.0.2.1" {
+		t.Fatalf("got %q, want the header and the rows of example.com", lines)
+	}
+
+	buffer.Reset()
+
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.TrimSpace(buffer.String()); got != "example.org,a.example.org," {
+		t.Errorf("got %q, want the row of example.org only", got)
+	}
+}
+
+func TestWriterRelease(t *testing.T) {
+	writer := NewWriter()
+
+	writer.SetFormat(JSONFormat{})
+
+	file := &bytes.Buffer{}
+	stdout := &bytes.Buffer{}
+
+	_ = writer.Write(file, "example.com", subdomain("a.example.com"))
+	_ = writer.Write(stdout, "example.com", subdomain("a.example.com"))
+
+	if err := writer.Release(file); err != nil {
+		t.Fatal(err)
+	}
+
+	if file.Len() == 0 || stdout.Len() != 0 {
+		t.Fatalf("release wrote %q to the file and %q to stdout", file, stdout)
+	}
+
+	file.Reset()
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if file.Len() != 0 || stdout.Len() == 0 {
+		t.Errorf("close wrote %q to the released file and %q to stdout", file, stdout)
+	}
+}
+
+func TestWriterCreateFile(t *testing.T) {
+	directory := t.TempDir()
+
+	for _, test := range []struct {
+		format Format
+		name   string
+		want   string
+	}{
+		{TXTFormat{}, "results", "results.txt"},
+		{TXTFormat{}, "results.txt", "results.txt"},
+		{JSONLFormat{}, "results", "results.json"},
+		{JSONLFormat{}, "results.json", "results.json"},
+		{JSONLFormat{}, "results.jsonl", "results.jsonl"},
+		{JSONFormat{}, "results.jsonl", "results.jsonl.json"},
+	} {
+		writer := NewWriter()
+
+		writer.SetFormat(test.format)
+
+		file, err := writer.CreateFile(filepath.Join(directory, test.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file.Close()
+
+		if got := filepath.Base(file.Name()); got != test.want {
+			t.Errorf("%s as %s: got %s, want %s", test.name, test.format.Name(), got, test.want)
+		}
+	}
+}
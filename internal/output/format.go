@@ -0,0 +1,81 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Format is an output format. A Writer creates one Encoder per destination, so formats
+// that need a header or a closing bracket can keep per-destination state.
+type Format interface {
+	// Name returns the name of the format, as accepted by ParseFormat.
+	Name() (name string)
+
+	// Extension returns the file extension of the format, e.g. ".csv".
+	Extension() (extension string)
+
+	// Appendable reports whether output in the format may be appended to an existing
+	// file. Files of formats that are not appendable are truncated when created.
+	Appendable() (ok bool)
+
+	// NewEncoder returns an encoder writing to writer.
+	NewEncoder(writer io.Writer) (encoder Encoder)
+}
+
+// Encoder encodes the results written to a single destination.
+type Encoder interface {
+	// Encode encodes result, found for domain. Encoders may buffer it until Flush or
+	// Close.
+	Encode(domain string, result sources.Result) (err error)
+
+	// Flush writes the output buffered for domain, once every result of domain has been
+	// encoded. No result of domain may be encoded afterwards.
+	Flush(domain string) (err error)
+
+	// Close writes any buffered output. The encoder must not be used afterwards.
+	Close() (err error)
+}
+
+// ParseFormat returns the format with the given name: "txt", "jsonl", "json" or "csv".
+//
+// Parameters:
+//   - name (string): The name of the format.
+//   - columns ([]string): The columns of the "csv" format; see NewCSVFormat. Ignored by
+//     the other formats.
+//
+// Returns:
+//   - format (Format): The format.
+//   - err (error): ErrUnknownFormat if no format has that name, or the error returned by
+//     NewCSVFormat.
+func ParseFormat(name string, columns []string) (format Format, err error) {
+	switch strings.ToLower(name) {
+	case "txt":
+		format = TXTFormat{}
+	case "jsonl":
+		format = JSONLFormat{}
+	case "json":
+		format = JSONFormat{}
+	case "csv":
+		format, err = NewCSVFormat(columns)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownFormat, name)
+	}
+
+	return
+}
+
+// Metadata set on the results of diff mode: MetadataChange is ChangeAdded on subdomains
+// absent from the baseline and ChangeRemoved on subdomains of the baseline that were not
+// found again.
+const (
+	MetadataChange = "change"
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+)
+
+// ErrUnknownFormat is returned by ParseFormat for names that match no format.
+var ErrUnknownFormat = errors.New("unknown output format")
@@ -0,0 +1,107 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// JSONFormat writes a single JSON document per domain, holding every result found for it:
+//
+//	{"domain": "example.com", "results": [{"subdomain": "www.example.com", ...}]}
+//
+// Results are buffered, and the document of a domain written once the domain is flushed,
+// or on Close, in the order the domains were first seen, so a file written for a single
+// domain holds exactly one document.
+type JSONFormat struct{}
+
+func (JSONFormat) Name() (name string) {
+	return "json"
+}
+
+func (JSONFormat) Extension() (extension string) {
+	return ".json"
+}
+
+func (JSONFormat) Appendable() (ok bool) {
+	return false
+}
+
+func (JSONFormat) NewEncoder(writer io.Writer) (encoder Encoder) {
+	return &jsonEncoder{
+		writer:    writer,
+		documents: map[string]*document{},
+	}
+}
+
+type jsonEncoder struct {
+	writer    io.Writer
+	domains   []string
+	documents map[string]*document
+}
+
+func (e *jsonEncoder) Encode(domain string, result sources.Result) (err error) {
+	doc, ok := e.documents[domain]
+	if !ok {
+		doc = &document{
+			Domain:  domain,
+			Results: []Record{},
+		}
+
+		e.domains = append(e.domains, domain)
+		e.documents[domain] = doc
+	}
+
+	doc.Results = append(doc.Results, NewRecord("", result))
+
+	return
+}
+
+func (e *jsonEncoder) Flush(domain string) (err error) {
+	doc, ok := e.documents[domain]
+	if !ok {
+		return
+	}
+
+	delete(e.documents, domain)
+
+	e.domains = slices.DeleteFunc(e.domains, func(d string) bool {
+		return d == domain
+	})
+
+	err = e.write(doc)
+
+	return
+}
+
+func (e *jsonEncoder) Close() (err error) {
+	for _, domain := range e.domains {
+		if err = e.write(e.documents[domain]); err != nil {
+			return
+		}
+	}
+
+	e.domains = nil
+	e.documents = map[string]*document{}
+
+	return
+}
+
+// write writes doc, indented.
+func (e *jsonEncoder) write(doc *document) (err error) {
+	enc := json.NewEncoder(e.writer)
+
+	enc.SetIndent("", "    ")
+
+	err = enc.Encode(doc)
+
+	return
+}
+
+// document is the JSON document written for a domain.
+type document struct {
+	Domain  string   `json:"domain"`
+	Results []Record `json:"results"`
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// JSONLFormat writes one JSON object per line.
+type JSONLFormat struct{}
+
+func (JSONLFormat) Name() (name string) {
+	return "jsonl"
+}
+
+func (JSONLFormat) Extension() (extension string) {
+	return ".json"
+}
+
+// Extensions returns the extensions of JSONL files: files named ".jsonl" are kept as
+// they are, and others get the ".json" extension that JSONL output has always had.
+func (JSONLFormat) Extensions() (extensions []string) {
+	return []string{".json", ".jsonl"}
+}
+
+func (JSONLFormat) Appendable() (ok bool) {
+	return true
+}
+
+func (JSONLFormat) NewEncoder(writer io.Writer) (encoder Encoder) {
+	return &jsonlEncoder{writer: writer}
+}
+
+type jsonlEncoder struct {
+	writer io.Writer
+}
+
+func (e *jsonlEncoder) Encode(domain string, result sources.Result) (err error) {
+	var data []byte
+
+	data, err = json.Marshal(NewRecord(domain, result))
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintln(e.writer, string(data))
+
+	return
+}
+
+func (e *jsonlEncoder) Flush(_ string) (err error) {
+	return
+}
+
+func (e *jsonlEncoder) Close() (err error) {
+	return
+}
+
+// Record is the JSON representation of a result, shared by the JSONL and JSON formats.
+type Record struct {
+	Domain    string            `json:"domain,omitempty"`
+	Subdomain string            `json:"subdomain,omitempty"`
+	Type      string            `json:"type,omitempty"`
+	Value     string            `json:"value,omitempty"`
+	Source    string            `json:"source"`
+	Sources   []string          `json:"sources,omitempty"`
+	Origin    string            `json:"origin,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	FirstSeen string            `json:"first_seen,omitempty"`
+	LastSeen  string            `json:"last_seen,omitempty"`
+	Score     float64           `json:"score,omitempty"`
+}
+
+// NewRecord returns the JSON representation of result, found for domain.
+//
+// Parameters:
+//   - domain (string): The target domain, or "" to leave it out.
+//   - result (sources.Result): The result.
+//
+// Returns:
+//   - data (Record): The JSON representation.
+func NewRecord(domain string, result sources.Result) (data Record) {
+	data = Record{
+		Domain:   domain,
+		Source:   result.Source,
+		Sources:  result.Sources,
+		Origin:   result.Origin,
+		Metadata: result.Metadata,
+	}
+
+	if !result.FirstSeen.IsZero() {
+		data.FirstSeen = result.FirstSeen.Format(time.RFC3339)
+	}
+
+	if !result.LastSeen.IsZero() {
+		data.LastSeen = result.LastSeen.Format(time.RFC3339)
+	}
+
+	if result.Type == sources.ResultSubdomain {
+		data.Subdomain = result.Value
+		data.Score = result.Score
+	} else {
+		data.Type = result.Type.String()
+		data.Value = result.Value
+	}
+
+	return
+}
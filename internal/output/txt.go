@@ -0,0 +1,59 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// TXTFormat writes one value per line. DNS records are written as "host type data", and
+// subdomains removed since the diff baseline are prefixed with "-".
+type TXTFormat struct{}
+
+func (TXTFormat) Name() (name string) {
+	return "txt"
+}
+
+func (TXTFormat) Extension() (extension string) {
+	return ".txt"
+}
+
+func (TXTFormat) Appendable() (ok bool) {
+	return true
+}
+
+func (TXTFormat) NewEncoder(writer io.Writer) (encoder Encoder) {
+	return &txtEncoder{writer: writer}
+}
+
+type txtEncoder struct {
+	writer io.Writer
+}
+
+func (e *txtEncoder) Encode(_ string, result sources.Result) (err error) {
+	switch result.Type {
+	case sources.ResultDNSRecord:
+		_, err = fmt.Fprintln(e.writer, result.Metadata[sources.MetadataHost], result.Metadata[sources.MetadataRecordType], result.Value)
+	case sources.ResultSubdomain:
+		if result.Metadata[MetadataChange] == ChangeRemoved {
+			_, err = fmt.Fprintln(e.writer, "-"+result.Value)
+
+			return
+		}
+
+		_, err = fmt.Fprintln(e.writer, result.Value)
+	default:
+		_, err = fmt.Fprintln(e.writer, result.Value)
+	}
+
+	return
+}
+
+func (e *txtEncoder) Flush(_ string) (err error) {
+	return
+}
+
+func (e *txtEncoder) Close() (err error) {
+	return
+}
@@ -0,0 +1,41 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/input"
+)
+
+// Configuration configures the job queue, as the `queue` section of the configuration
+// file.
+//
+// Fields:
+//   - Retention (string): How long finished jobs and their results are kept, e.g. "7d",
+//     or "0" to keep them until removed.
+type Configuration struct {
+	Retention string `yaml:"retention"`
+}
+
+// ParseRetention parses a retention period: a number of days such as "7d", or a Go
+// duration such as "12h". An empty value is the default retention.
+//
+// Parameters:
+//   - value (string): The retention period.
+//
+// Returns:
+//   - retention (time.Duration): The parsed retention period.
+//   - err (error): An error if value is not a valid duration.
+func ParseRetention(value string) (retention time.Duration, err error) {
+	if value == "" {
+		retention = DefaultRetention
+
+		return
+	}
+
+	retention, err = input.ParseDuration(value)
+
+	return
+}
+
+// DefaultRetention is the retention period of finished jobs when none is configured.
+const DefaultRetention = 7 * 24 * time.Hour
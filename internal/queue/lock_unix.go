@@ -0,0 +1,21 @@
+//go:build unix
+
+package queue
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile takes an exclusive lock on file, held until it is closed, or returns ErrLocked
+// if another process holds it.
+func lockFile(file *os.File) (err error) {
+	err = unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		err = ErrLocked
+	}
+
+	return
+}
@@ -0,0 +1,670 @@
+// Package queue implements a durable job queue: enumeration jobs, their state and their
+// results are kept in a directory, so that they survive restarts. A restarted process
+// resumes pending jobs, retries jobs that were interrupted while running, and keeps
+// finished jobs, with their results, for a retention period.
+//
+// Every job is kept as two files named after it: <id>.json, holding its state, written
+// atomically at every change, and <id>.jsonl, holding its results, one per line,
+// appended while it runs.
+//
+// A directory is used by one process at a time: Open takes an exclusive lock on it,
+// held until Close.
+package queue
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// State is the state of a job.
+type State string
+
+// Done reports whether a job in this state has finished.
+func (s State) Done() (ok bool) {
+	ok = s == StateCompleted || s == StateCancelled || s == StateFailed
+
+	return
+}
+
+// Job is a queued job.
+//
+// Fields:
+//   - ID (string): The identifier of the job.
+//   - Kind (string): What queued the job, e.g. KindServer, so that processes sharing a
+//     queue only handle their own jobs.
+//   - Payload (json.RawMessage): What to run, as understood by the kind of the job.
+//   - State (State): The state of the job.
+//   - Attempts (int): The number of times the job was started.
+//   - Created, Started, Finished (time.Time): When the job was queued, last started and
+//     finished; zero until then.
+type Job struct {
+	ID       string          `json:"id"`
+	Kind     string          `json:"kind"`
+	Payload  json.RawMessage `json:"payload"`
+	State    State           `json:"state"`
+	Attempts int             `json:"attempts"`
+	Created  time.Time       `json:"created"`
+	Started  time.Time       `json:"started,omitzero"`
+	Finished time.Time       `json:"finished,omitzero"`
+}
+
+// Queue is a durable job queue kept in a directory. It is safe for concurrent use.
+//
+// Fields:
+//   - directory (string): The directory the jobs are kept in.
+//   - retention (time.Duration): How long finished jobs are kept; 0 keeps them until
+//     removed.
+//   - lock (*os.File): The lock file of the directory, locked while the queue is open.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - jobs (map[string]*entry): The jobs, keyed by identifier.
+type Queue struct {
+	directory string
+	retention time.Duration
+	lock      *os.File
+	mu        sync.Mutex
+	jobs      map[string]*entry
+}
+
+// entry is a job of the queue, with the results file it is writing to while it runs.
+type entry struct {
+	job    Job
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// Push queues a job.
+//
+// Parameters:
+//   - id (string): The identifier of the job, e.g. from NewID.
+//   - kind (string): What queues the job.
+//   - payload (any): What to run, encoded in JSON.
+//
+// Returns:
+//   - job (Job): The queued job.
+//   - err (error): An error wrapping ErrInvalidID for identifiers that are not alphanumeric
+//     or already used, or an error writing the job.
+func (q *Queue) Push(id, kind string, payload any) (job Job, err error) {
+	if !validID.MatchString(id) {
+		err = fmt.Errorf("%w: %q", ErrInvalidID, id)
+
+		return
+	}
+
+	var data []byte
+
+	data, err = json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	if _, ok := q.jobs[id]; ok {
+		err = fmt.Errorf("%w: %q already queued", ErrInvalidID, id)
+
+		return
+	}
+
+	e := &entry{
+		job: Job{
+			ID:      id,
+			Kind:    kind,
+			Payload: data,
+			State:   StatePending,
+			Created: time.Now().UTC(),
+		},
+	}
+
+	if err = q.save(e); err != nil {
+		return
+	}
+
+	q.jobs[id] = e
+
+	job = e.job
+
+	return
+}
+
+// Jobs returns the jobs of the given kind, in the order they were queued.
+//
+// Parameters:
+//   - kind (string): The kind of the jobs.
+//
+// Returns:
+//   - jobs ([]Job): The jobs.
+func (q *Queue) Jobs(kind string) (jobs []Job) {
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	for _, e := range q.jobs {
+		if e.job.Kind == kind {
+			jobs = append(jobs, e.job)
+		}
+	}
+
+	slices.SortFunc(jobs, func(a, b Job) int {
+		if c := a.Created.Compare(b.Created); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return
+}
+
+// Start marks a job running and discards the results of its previous attempts.
+//
+// Parameters:
+//   - id (string): The identifier of the job.
+//
+// Returns:
+//   - err (error): ErrNotFound if there is no such job, or an error writing it.
+func (q *Queue) Start(id string) (err error) {
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	e, ok := q.jobs[id]
+	if !ok {
+		err = fmt.Errorf("%w: %q", ErrNotFound, id)
+
+		return
+	}
+
+	q.release(e)
+
+	e.file, err = os.OpenFile(q.path(id, resultsExtension), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return
+	}
+
+	e.writer = bufio.NewWriter(e.file)
+
+	e.job.State = StateRunning
+	e.job.Attempts++
+	e.job.Started = time.Now().UTC()
+	e.job.Finished = time.Time{}
+
+	err = q.save(e)
+
+	return
+}
+
+// Append records a result of a running job.
+//
+// Parameters:
+//   - id (string): The identifier of the job.
+//   - result (sources.Result): The result.
+//
+// Returns:
+//   - err (error): ErrNotRunning if the job is not running, or an error writing the result.
+func (q *Queue) Append(id string, result sources.Result) (err error) {
+	var data []byte
+
+	data, err = json.Marshal(NewRecord(result))
+	if err != nil {
+		return
+	}
+
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	e, ok := q.jobs[id]
+	if !ok || e.writer == nil {
+		err = fmt.Errorf("%w: %q", ErrNotRunning, id)
+
+		return
+	}
+
+	_, err = e.writer.Write(append(data, '\n'))
+
+	return
+}
+
+// Finish marks a job finished, writing out its results.
+//
+// Parameters:
+//   - id (string): The identifier of the job.
+//   - state (State): How the job finished: StateCompleted, StateCancelled or StateFailed.
+//
+// Returns:
+//   - err (error): ErrNotFound if there is no such job, or an error writing it.
+func (q *Queue) Finish(id string, state State) (err error) {
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	e, ok := q.jobs[id]
+	if !ok {
+		err = fmt.Errorf("%w: %q", ErrNotFound, id)
+
+		return
+	}
+
+	if e.writer != nil {
+		err = e.writer.Flush()
+
+		if err == nil {
+			err = e.file.Sync()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	q.release(e)
+
+	e.job.State = state
+	e.job.Finished = time.Now().UTC()
+
+	err = q.save(e)
+
+	return
+}
+
+// Results returns the results recorded for a job.
+//
+// Parameters:
+//   - id (string): The identifier of the job.
+//
+// Returns:
+//   - results ([]sources.Result): The results, in the order they were recorded.
+//   - err (error): ErrNotFound if there is no such job, or an error reading the results.
+func (q *Queue) Results(id string) (results []sources.Result, err error) {
+	q.mu.Lock()
+
+	e, ok := q.jobs[id]
+	if ok && e.writer != nil {
+		err = e.writer.Flush()
+	}
+
+	q.mu.Unlock()
+
+	if !ok {
+		err = fmt.Errorf("%w: %q", ErrNotFound, id)
+
+		return
+	}
+
+	if err != nil {
+		return
+	}
+
+	var file *os.File
+
+	file, err = os.Open(q.path(id, resultsExtension))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+
+		return
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+
+	for scanner.Scan() {
+		var r Record
+
+		if err = json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return
+		}
+
+		var result sources.Result
+
+		result, err = r.Result()
+		if err != nil {
+			return
+		}
+
+		results = append(results, result)
+	}
+
+	err = scanner.Err()
+
+	return
+}
+
+// Remove removes a job and its results.
+//
+// Parameters:
+//   - id (string): The identifier of the job.
+//
+// Returns:
+//   - err (error): An error removing its files.
+func (q *Queue) Remove(id string) (err error) {
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	err = q.remove(id)
+
+	return
+}
+
+// Prune removes the finished jobs kept longer than the retention period.
+//
+// Returns:
+//   - removed ([]string): The identifiers of the removed jobs.
+//   - err (error): An error removing their files.
+func (q *Queue) Prune() (removed []string, err error) {
+	if q.retention <= 0 {
+		return
+	}
+
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	expiry := time.Now().Add(-q.retention)
+
+	for id, e := range q.jobs {
+		if !e.job.State.Done() || e.job.Finished.After(expiry) {
+			continue
+		}
+
+		if err = q.remove(id); err != nil {
+			return
+		}
+
+		removed = append(removed, id)
+	}
+
+	return
+}
+
+// Close writes out the results of running jobs and closes their files, then releases
+// the lock on the directory. Running jobs stay running, to be retried when the queue is
+// next opened.
+//
+// Returns:
+//   - err (error): An error writing out results.
+func (q *Queue) Close() (err error) {
+	q.mu.Lock()
+
+	defer q.mu.Unlock()
+
+	for _, e := range q.jobs {
+		if e.writer != nil {
+			err = errors.Join(err, e.writer.Flush())
+		}
+
+		q.release(e)
+	}
+
+	if q.lock != nil {
+		err = errors.Join(err, q.lock.Close())
+
+		q.lock = nil
+	}
+
+	return
+}
+
+// load reads the jobs kept in the directory, marking the jobs that were running, i.e.
+// interrupted, pending again, or failed once they have been attempted MaxAttempts times.
+func (q *Queue) load() (err error) {
+	var paths []string
+
+	paths, err = filepath.Glob(filepath.Join(q.directory, "*"+stateExtension))
+	if err != nil {
+		return
+	}
+
+	for _, path := range paths {
+		var data []byte
+
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return
+		}
+
+		e := &entry{}
+
+		if err = json.Unmarshal(data, &e.job); err != nil {
+			err = fmt.Errorf("%s: %w", path, err)
+
+			return
+		}
+
+		if e.job.ID+stateExtension != filepath.Base(path) {
+			err = fmt.Errorf("%s: %w: %q", path, ErrInvalidID, e.job.ID)
+
+			return
+		}
+
+		if e.job.State == StateRunning {
+			e.job.State = StatePending
+
+			if e.job.Attempts >= MaxAttempts {
+				e.job.State = StateFailed
+				e.job.Finished = time.Now().UTC()
+			}
+
+			if err = q.save(e); err != nil {
+				return
+			}
+		}
+
+		q.jobs[e.job.ID] = e
+	}
+
+	return
+}
+
+// save writes the state of a job. The caller must hold mu.
+func (q *Queue) save(e *entry) (err error) {
+	var data []byte
+
+	data, err = json.MarshalIndent(e.job, "", "\t")
+	if err != nil {
+		return
+	}
+
+	err = writeFile(q.path(e.job.ID, stateExtension), data)
+
+	return
+}
+
+// remove removes a job and its files. The caller must hold mu.
+func (q *Queue) remove(id string) (err error) {
+	if e, ok := q.jobs[id]; ok {
+		q.release(e)
+	}
+
+	delete(q.jobs, id)
+
+	for _, extension := range []string{stateExtension, resultsExtension} {
+		if rerr := os.Remove(q.path(id, extension)); rerr != nil && !errors.Is(rerr, os.ErrNotExist) {
+			err = errors.Join(err, rerr)
+		}
+	}
+
+	return
+}
+
+// release closes the results file of a job, if open. The caller must hold mu.
+func (q *Queue) release(e *entry) {
+	if e.file != nil {
+		e.file.Close()
+	}
+
+	e.file = nil
+	e.writer = nil
+}
+
+// path returns the path of a file of a job.
+func (q *Queue) path(id, extension string) (path string) {
+	path = filepath.Join(q.directory, id+extension)
+
+	return
+}
+
+// writeFile replaces the file at path with data, atomically.
+func writeFile(path string, data []byte) (err error) {
+	var file *os.File
+
+	file, err = os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			file.Close()
+
+			os.Remove(file.Name())
+		}
+	}()
+
+	if _, err = file.Write(data); err != nil {
+		return
+	}
+
+	if err = file.Sync(); err != nil {
+		return
+	}
+
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(file.Name(), path)
+
+	return
+}
+
+// Open opens the queue kept in directory, creating it if needed, and locks the directory
+// until Close, so that no other process uses it meanwhile. Jobs that were running when
+// the queue was last used are marked pending again, and finished jobs kept longer than
+// retention are removed.
+//
+// Parameters:
+//   - directory (string): The directory the jobs are kept in.
+//   - retention (time.Duration): How long finished jobs are kept; 0 keeps them until
+//     removed.
+//
+// Returns:
+//   - q (*Queue): The queue.
+//   - err (error): An error wrapping ErrLocked if another process has the directory open,
+//     or an error reading or writing the directory.
+func Open(directory string, retention time.Duration) (q *Queue, err error) {
+	if err = os.MkdirAll(directory, 0o750); err != nil {
+		return
+	}
+
+	var lock *os.File
+
+	lock, err = os.OpenFile(filepath.Join(directory, lockName), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return
+	}
+
+	if err = lockFile(lock); err != nil {
+		lock.Close()
+
+		err = fmt.Errorf("%w: %s", err, directory)
+
+		return
+	}
+
+	q = &Queue{
+		directory: directory,
+		retention: retention,
+		lock:      lock,
+		jobs:      map[string]*entry{},
+	}
+
+	if err = q.load(); err == nil {
+		_, err = q.Prune()
+	}
+
+	if err != nil {
+		q.Close()
+
+		q = nil
+	}
+
+	return
+}
+
+// NewID returns a random job identifier.
+//
+// Returns:
+//   - id (string): The identifier.
+//   - err (error): An error reading random bytes.
+func NewID() (id string, err error) {
+	b := make([]byte, 8)
+
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+
+	id = hex.EncodeToString(b)
+
+	return
+}
+
+// States of a job.
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateCompleted State = "completed"
+	StateCancelled State = "cancelled"
+	StateFailed    State = "failed"
+)
+
+// Kinds of jobs.
+const (
+	KindBatch  = "batch"
+	KindServer = "server"
+)
+
+const (
+	// MaxAttempts is the number of times a job is started before it is marked failed
+	// rather than retried, should it keep being interrupted, e.g. by crashing the process.
+	MaxAttempts = 3
+
+	stateExtension   = ".json"
+	resultsExtension = ".jsonl"
+	lockName         = ".lock"
+	maxRecordSize    = 1024 * 1024
+)
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+var (
+	// ErrInvalidID is returned for job identifiers that are not alphanumeric or already
+	// used.
+	ErrInvalidID = errors.New("invalid job identifier")
+	// ErrLocked is returned when opening a queue another process has open.
+	ErrLocked = errors.New("queue in use by another process")
+	// ErrNotFound is returned for unknown jobs.
+	ErrNotFound = errors.New("job not found")
+	// ErrNotRunning is returned when recording results of a job that is not running.
+	ErrNotRunning = errors.New("job not running")
+)
@@ -0,0 +1,338 @@
+package queue
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// TestOpenLocked checks that a queue directory cannot be opened twice at once, and can
+// once closed.
+func TestOpenLocked(t *testing.T) {
+	directory := t.TempDir()
+
+	q, err := Open(directory, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = q.Push("first", KindBatch, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Open(directory, 0); !errors.Is(err, ErrLocked) {
+		t.Fatalf("got %v opening a queue in use, want %v", err, ErrLocked)
+	}
+
+	if err = q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err = Open(directory, 0)
+	if err != nil {
+		t.Fatalf("got %v opening a closed queue", err)
+	}
+
+	defer q.Close()
+
+	if jobs := q.Jobs(KindBatch); len(jobs) != 1 || jobs[0].ID != "first" {
+		t.Errorf("got jobs %+v", jobs)
+	}
+}
+
+// reopen closes q, as a crash would leave it, and opens its directory again.
+func reopen(t *testing.T, q *Queue, retention time.Duration) (reopened *Queue) {
+	t.Helper()
+
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(q.directory, retention)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		reopened.Close()
+	})
+
+	return
+}
+
+// job returns the job of q with the given identifier.
+func job(t *testing.T, q *Queue, id string) (job Job) {
+	t.Helper()
+
+	jobs := q.Jobs(KindBatch)
+
+	i := slices.IndexFunc(jobs, func(job Job) bool {
+		return job.ID == id
+	})
+	if i < 0 {
+		t.Fatalf("job %s not found", id)
+	}
+
+	job = jobs[i]
+
+	return
+}
+
+// TestReopenRunning checks that jobs interrupted while running are pending again once the
+// queue is reopened, and that starting them again discards the results of the attempt.
+func TestReopenRunning(t *testing.T) {
+	q, err := Open(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = q.Push("interrupted", KindBatch, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = q.Start("interrupted"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = q.Append("interrupted", sources.Result{Type: sources.ResultSubdomain, Source: sources.CRTSH, Value: "a.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	q = reopen(t, q, 0)
+
+	if got := job(t, q, "interrupted"); got.State != StatePending || got.Attempts != 1 {
+		t.Errorf("got a reopened job %s after %d attempts, want it pending after 1", got.State, got.Attempts)
+	}
+
+	if err = q.Append("interrupted", sources.Result{Type: sources.ResultSubdomain, Source: sources.CRTSH, Value: "b.example.com"}); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("got %v appending to a pending job, want %v", err, ErrNotRunning)
+	}
+
+	if err = q.Start("interrupted"); err != nil {
+		t.Fatal(err)
+	}
+
+	if results, err := q.Results("interrupted"); err != nil || len(results) != 0 {
+		t.Errorf("got %v, %v, want the results of the interrupted attempt discarded", results, err)
+	}
+
+	if got := job(t, q, "interrupted"); got.State != StateRunning || got.Attempts != 2 {
+		t.Errorf("got a restarted job %s after %d attempts, want it running after 2", got.State, got.Attempts)
+	}
+}
+
+// TestMaxAttempts checks that a job interrupted MaxAttempts times is failed rather than
+// retried.
+func TestMaxAttempts(t *testing.T) {
+	q, err := Open(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = q.Push("crashing", KindBatch, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		if err = q.Start("crashing"); err != nil {
+			t.Fatal(err)
+		}
+
+		q = reopen(t, q, 0)
+
+		got := job(t, q, "crashing")
+
+		switch {
+		case attempt < MaxAttempts && (got.State != StatePending || !got.Finished.IsZero()):
+			t.Errorf("attempt %d: got a job %s, want it pending", attempt, got.State)
+		case attempt == MaxAttempts && (got.State != StateFailed || got.Finished.IsZero()):
+			t.Errorf("attempt %d: got a job %s, want it failed", attempt, got.State)
+		}
+	}
+
+	// Failed jobs stay failed.
+	q = reopen(t, q, 0)
+
+	if got := job(t, q, "crashing"); got.State != StateFailed || got.Attempts != MaxAttempts {
+		t.Errorf("got a job %s after %d attempts, want it failed after %d", got.State, got.Attempts, MaxAttempts)
+	}
+}
+
+// TestPrune checks that finished jobs are removed, with their files, once kept longer
+// than the retention period, and that jobs not finished are kept.
+func TestPrune(t *testing.T) {
+	q, err := Open(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer q.Close()
+
+	for _, id := range []string{"expired", "recent", "pending"} {
+		if _, err = q.Push(id, KindBatch, "example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, id := range []string{"expired", "recent"} {
+		if err = q.Start(id); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = q.Finish(id, StateCompleted); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q.mu.Lock()
+
+	q.jobs["expired"].job.Finished = time.Now().Add(-2 * time.Hour)
+	q.jobs["pending"].job.Created = time.Now().Add(-2 * time.Hour)
+
+	q.mu.Unlock()
+
+	removed, err := q.Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(removed, []string{"expired"}) {
+		t.Errorf("got %v removed, want the expired job only", removed)
+	}
+
+	for _, extension := range []string{stateExtension, resultsExtension} {
+		if _, err = os.Stat(q.path("expired", extension)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got %v for the %s file of the expired job, want it removed", err, extension)
+		}
+	}
+
+	if jobs := q.Jobs(KindBatch); len(jobs) != 2 || jobs[0].ID != "pending" || jobs[1].ID != "recent" {
+		t.Errorf("got jobs %+v, want the recent and pending jobs kept", jobs)
+	}
+
+	// Without a retention period, finished jobs are kept until removed.
+	q.retention = 0
+
+	q.mu.Lock()
+
+	q.jobs["recent"].job.Finished = time.Now().Add(-24 * 365 * time.Hour)
+
+	q.mu.Unlock()
+
+	if removed, err = q.Prune(); err != nil || len(removed) != 0 {
+		t.Errorf("got %v, %v without retention, want nothing removed", removed, err)
+	}
+}
+
+// TestResults checks that results, errors included, are restored as they were recorded.
+func TestResults(t *testing.T) {
+	q, err := Open(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = q.Push("job", KindBatch, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = q.Start("job"); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	recorded := []sources.Result{
+		{
+			Type:      sources.ResultSubdomain,
+			Source:    sources.CRTSH,
+			Value:     "a.example.com",
+			Origin:    "https://crt.sh/?id=1",
+			FirstSeen: seen.Add(-time.Hour),
+			LastSeen:  seen,
+			Score:     0.75,
+			Sources:   []string{sources.CRTSH, sources.OPENTHREATEXCHANGE},
+		},
+		{
+			Type:     sources.ResultIP,
+			Source:   sources.SHODAN,
+			Value:    "192.0.2.1",
+			Metadata: map[string]string{sources.MetadataHost: "a.example.com"},
+		},
+	}
+
+	for _, result := range recorded {
+		if err = q.Append("job", result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	classified := sources.NewError(sources.ErrRateLimited, sources.SHODAN, errors.New("slow down"))
+
+	classified.StatusCode = 429
+	classified.URL = "https://api.shodan.io/dns/domain/example.com?key=REDACTED"
+
+	failures := []sources.Result{
+		{Type: sources.ResultError, Source: sources.SHODAN, Error: classified},
+		{Type: sources.ResultError, Source: sources.OPENTHREATEXCHANGE, Error: errors.New("boom")},
+	}
+
+	for _, result := range failures {
+		if err = q.Append("job", result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = q.Finish("job", StateCompleted); err != nil {
+		t.Fatal(err)
+	}
+
+	q = reopen(t, q, 0)
+
+	results, err := q.Results("job")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != len(recorded)+len(failures) {
+		t.Fatalf("got %d results, want %d", len(results), len(recorded)+len(failures))
+	}
+
+	for i, result := range recorded {
+		got := results[i]
+
+		if !got.FirstSeen.Equal(result.FirstSeen) || !got.LastSeen.Equal(result.LastSeen) {
+			t.Errorf("got seen %s-%s, want %s-%s", got.FirstSeen, got.LastSeen, result.FirstSeen, result.LastSeen)
+		}
+
+		got.FirstSeen, got.LastSeen = result.FirstSeen, result.LastSeen
+
+		if !reflect.DeepEqual(got, result) {
+			t.Errorf("got %+v, want %+v", got, result)
+		}
+	}
+
+	restored := results[len(recorded)]
+
+	var e *sources.Error
+
+	if !errors.Is(restored.Error, sources.ErrRateLimited) || !errors.As(restored.Error, &e) {
+		t.Fatalf("got %v, want a rate limiting error", restored.Error)
+	}
+
+	if e.Source != sources.SHODAN || e.StatusCode != 429 || e.URL != classified.URL || e.Err == nil || e.Err.Error() != "slow down" {
+		t.Errorf("got %#v, want %#v", e, classified)
+	}
+
+	if restored = results[len(recorded)+1]; !errors.Is(restored.Error, sources.ErrUnclassified) || restored.Source != sources.OPENTHREATEXCHANGE {
+		t.Errorf("got %v from %s, want an unclassified error from %s", restored.Error, restored.Source, sources.OPENTHREATEXCHANGE)
+	}
+
+	if _, err = q.Results("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for an unknown job, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,166 @@
+package queue
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Record is the JSON representation of a result, in results files and wherever results
+// have to be passed on intact. Errors are kept with their kind, so that restored results
+// classify as the original ones did.
+//
+// Fields:
+//   - Type (string): The type of the result, as returned by sources.ResultType.String.
+//   - Source, Value, Origin, Metadata, FirstSeen, LastSeen, Score, Sources: As in
+//     sources.Result.
+//   - Error (*ErrorRecord): The error of error results.
+type Record struct {
+	Type      string            `json:"type"`
+	Source    string            `json:"source"`
+	Value     string            `json:"value,omitempty"`
+	Origin    string            `json:"origin,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	FirstSeen time.Time         `json:"first_seen,omitzero"`
+	LastSeen  time.Time         `json:"last_seen,omitzero"`
+	Score     float64           `json:"score,omitempty"`
+	Sources   []string          `json:"sources,omitempty"`
+	Error     *ErrorRecord      `json:"error,omitempty"`
+}
+
+// ErrorRecord is the representation of a source error. Only the redacted description of
+// the underlying error is kept, so that API keys are never written out.
+//
+// Fields:
+//   - Kind (string): The category of the error, one of the sources.Err* sentinels.
+//   - StatusCode (int): The HTTP status code of the response, or 0 if there was none.
+//   - URL (string): The redacted request URL, or "" if unknown.
+//   - Message (string): The redacted description of the underlying error, if any.
+type ErrorRecord struct {
+	Kind       string `json:"kind"`
+	StatusCode int    `json:"status_code,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Result returns the result represented by the record.
+//
+// Returns:
+//   - result (sources.Result): The result.
+//   - err (error): An error if the type of the record is unknown.
+func (r *Record) Result() (result sources.Result, err error) {
+	result = sources.Result{
+		Source:    r.Source,
+		Value:     r.Value,
+		Origin:    r.Origin,
+		Metadata:  r.Metadata,
+		FirstSeen: r.FirstSeen,
+		LastSeen:  r.LastSeen,
+		Score:     r.Score,
+		Sources:   r.Sources,
+	}
+
+	result.Type, err = sources.ParseResultType(r.Type)
+	if err != nil {
+		return
+	}
+
+	if r.Error == nil {
+		return
+	}
+
+	kind := sources.ErrUnclassified
+
+	for _, candidate := range kinds {
+		if candidate.Error() == r.Error.Kind {
+			kind = candidate
+
+			break
+		}
+	}
+
+	e := sources.NewError(kind, r.Source, nil)
+
+	e.StatusCode = r.Error.StatusCode
+	e.URL = r.Error.URL
+
+	if r.Error.Message != "" {
+		e.Err = errors.New(r.Error.Message)
+	}
+
+	result.Error = e
+
+	return
+}
+
+// NewRecord returns the record representing result.
+//
+// Parameters:
+//   - result (sources.Result): The result.
+//
+// Returns:
+//   - r (Record): The record.
+func NewRecord(result sources.Result) (r Record) {
+	r = Record{
+		Type:      result.Type.String(),
+		Source:    result.Source,
+		Value:     result.Value,
+		Origin:    result.Origin,
+		Metadata:  result.Metadata,
+		FirstSeen: result.FirstSeen,
+		LastSeen:  result.LastSeen,
+		Score:     result.Score,
+		Sources:   result.Sources,
+	}
+
+	if result.Type != sources.ResultError {
+		return
+	}
+
+	r.Error = &ErrorRecord{
+		Kind: sources.KindOf(result.Error).Error(),
+	}
+
+	var e *sources.Error
+
+	if !errors.As(result.Error, &e) {
+		if result.Error != nil {
+			r.Error.Message = result.Error.Error()
+		}
+
+		return
+	}
+
+	r.Error.StatusCode = e.StatusCode
+	r.Error.URL = e.URL
+
+	if e.Err == nil {
+		return
+	}
+
+	// The description of the underlying error is taken from the redacted description of
+	// the whole error, less what precedes it.
+	prefix := *e
+
+	prefix.Err = nil
+
+	r.Error.Message = strings.TrimPrefix(e.Error(), prefix.Error()+": ")
+
+	return
+}
+
+// kinds are the categories of source errors.
+var kinds = []error{
+	sources.ErrMissingKey,
+	sources.ErrAuth,
+	sources.ErrQuotaExhausted,
+	sources.ErrRateLimited,
+	sources.ErrUpstream,
+	sources.ErrTimeout,
+	sources.ErrParse,
+	sources.ErrInvalidResponse,
+	sources.ErrRequest,
+	sources.ErrUnclassified,
+}
@@ -0,0 +1,206 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	xsubfind3rv1 "github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/rpc/v1"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// run is an enumeration started by an Enumerate call.
+//
+// Fields:
+//   - id (string): The identifier of the run.
+//   - domain (string): The normalized target domain.
+//   - tenant (string): The name of the tenant that started the run.
+//   - cancel (context.CancelFunc): Cancels the run, and the sources it runs.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - state (xsubfind3rv1.RunState): The state of the run.
+//   - started, finished (time.Time): When the run started and finished; finished is zero
+//     until then.
+//   - stats (*xsubfind3rv1.Stats): The statistics of the results found so far, less the
+//     duration.
+type run struct {
+	id       string
+	domain   string
+	tenant   string
+	cancel   context.CancelFunc
+	mu       sync.Mutex
+	state    xsubfind3rv1.RunState
+	started  time.Time
+	finished time.Time
+	stats    *xsubfind3rv1.Stats
+}
+
+// add records result in the statistics of the run.
+func (r *run) add(result sources.Result) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats.Sources[result.Source]
+	if !ok {
+		stats = &xsubfind3rv1.SourceStats{}
+
+		r.stats.Sources[result.Source] = stats
+	}
+
+	switch result.Type {
+	case sources.ResultError:
+		r.stats.Errors++
+
+		stats.Errors++
+	case sources.ResultSubdomain:
+		r.stats.Subdomains++
+		r.stats.Results++
+
+		stats.Subdomains++
+		stats.Results++
+	default:
+		r.stats.Results++
+
+		stats.Results++
+	}
+}
+
+// finish marks the run finished: cancelled if it was cancelled before completing,
+// completed otherwise.
+func (r *run) finish(cancelled bool) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	r.state = xsubfind3rv1.RunState_RUN_STATE_COMPLETED
+
+	if cancelled {
+		r.state = xsubfind3rv1.RunState_RUN_STATE_CANCELLED
+	}
+
+	r.finished = time.Now()
+}
+
+// snapshot returns the state and statistics of the run.
+func (r *run) snapshot() (response *xsubfind3rv1.GetStatsResponse) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	stats, _ := proto.Clone(r.stats).(*xsubfind3rv1.Stats)
+
+	end := r.finished
+
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	stats.Duration = durationpb.New(end.Sub(r.started))
+
+	response = &xsubfind3rv1.GetStatsResponse{
+		RunId:   r.id,
+		Domain:  r.domain,
+		State:   r.state,
+		Started: timestamppb.New(r.started),
+		Stats:   stats,
+	}
+
+	if !r.finished.IsZero() {
+		response.Finished = timestamppb.New(r.finished)
+	}
+
+	return
+}
+
+// expired reports whether the run finished longer than retention before now.
+func (r *run) expired(now time.Time, retention time.Duration) (ok bool) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	ok = !r.finished.IsZero() && now.Sub(r.finished) > retention
+
+	return
+}
+
+// newRun returns a running run started by tenant.
+func newRun(id, domain, tenant string, cancel context.CancelFunc) (r *run) {
+	r = &run{
+		id:      id,
+		domain:  domain,
+		tenant:  tenant,
+		cancel:  cancel,
+		state:   xsubfind3rv1.RunState_RUN_STATE_RUNNING,
+		started: time.Now(),
+		stats: &xsubfind3rv1.Stats{
+			Sources: map[string]*xsubfind3rv1.SourceStats{},
+		},
+	}
+
+	return
+}
+
+// newResult returns the message of result. Errors are redacted as they are in the
+// queue, so that API keys are never sent to clients.
+func newResult(result sources.Result) (message *xsubfind3rv1.Result) {
+	message = &xsubfind3rv1.Result{
+		Type:     types[result.Type],
+		Source:   result.Source,
+		Value:    result.Value,
+		Origin:   result.Origin,
+		Metadata: result.Metadata,
+		Score:    result.Score,
+		Sources:  result.Sources,
+	}
+
+	if !result.FirstSeen.IsZero() {
+		message.FirstSeen = timestamppb.New(result.FirstSeen)
+	}
+
+	if !result.LastSeen.IsZero() {
+		message.LastSeen = timestamppb.New(result.LastSeen)
+	}
+
+	if result.Type != sources.ResultError {
+		return
+	}
+
+	record := queue.NewRecord(result)
+
+	message.Error = &xsubfind3rv1.SourceError{
+		Kind:       kinds[record.Error.Kind],
+		StatusCode: int32(record.Error.StatusCode), //nolint:gosec // HTTP status codes fit.
+		Url:        record.Error.URL,
+		Message:    record.Error.Message,
+	}
+
+	return
+}
+
+// types maps the types of results to those of messages.
+var types = map[sources.ResultType]xsubfind3rv1.ResultType{
+	sources.ResultSubdomain: xsubfind3rv1.ResultType_RESULT_TYPE_SUBDOMAIN,
+	sources.ResultError:     xsubfind3rv1.ResultType_RESULT_TYPE_ERROR,
+	sources.ResultIP:        xsubfind3rv1.ResultType_RESULT_TYPE_IP,
+	sources.ResultURL:       xsubfind3rv1.ResultType_RESULT_TYPE_URL,
+	sources.ResultDNSRecord: xsubfind3rv1.ResultType_RESULT_TYPE_RECORD,
+	sources.ResultASN:       xsubfind3rv1.ResultType_RESULT_TYPE_ASN,
+}
+
+// kinds maps the kinds of source errors, as recorded in the queue, to those of messages.
+var kinds = map[string]xsubfind3rv1.ErrorKind{
+	sources.ErrMissingKey.Error():      xsubfind3rv1.ErrorKind_ERROR_KIND_MISSING_KEY,
+	sources.ErrAuth.Error():            xsubfind3rv1.ErrorKind_ERROR_KIND_AUTH,
+	sources.ErrQuotaExhausted.Error():  xsubfind3rv1.ErrorKind_ERROR_KIND_QUOTA_EXHAUSTED,
+	sources.ErrRateLimited.Error():     xsubfind3rv1.ErrorKind_ERROR_KIND_RATE_LIMITED,
+	sources.ErrUpstream.Error():        xsubfind3rv1.ErrorKind_ERROR_KIND_UPSTREAM,
+	sources.ErrTimeout.Error():         xsubfind3rv1.ErrorKind_ERROR_KIND_TIMEOUT,
+	sources.ErrParse.Error():           xsubfind3rv1.ErrorKind_ERROR_KIND_PARSE,
+	sources.ErrInvalidResponse.Error(): xsubfind3rv1.ErrorKind_ERROR_KIND_INVALID_RESPONSE,
+	sources.ErrRequest.Error():         xsubfind3rv1.ErrorKind_ERROR_KIND_REQUEST,
+	sources.ErrUnclassified.Error():    xsubfind3rv1.ErrorKind_ERROR_KIND_UNCLASSIFIED,
+}
@@ -0,0 +1,542 @@
+// Package rpc exposes a Finder as the gRPC FinderService: its sources, streaming
+// enumerations ("runs") of domains, their cancellation and statistics.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/input"
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/internal/server"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	xsubfind3rv1 "github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/rpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements FinderService with a Finder. Each Enumerate call is a run, cancelled,
+// along with the sources it runs, once its call is cancelled or CancelRun is called for
+// it. Finished runs are kept for Retention, for GetStats.
+//
+// Calls are made by the tenants of a server.Server, as its HTTP API is, authenticated by
+// their token as "authorization: Bearer <token>" metadata. Runs are subject to the
+// permissions, quotas and concurrency limits of their tenant, and to the concurrency
+// limit of that server; tenants see and cancel only their own runs, unless they are
+// administrators.
+//
+// Fields:
+//   - finder (*xsubfind3r.Finder): The finder running the runs.
+//   - tenants (*server.Server): The server whose tenants and slots runs are subject to.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - closed (bool): Whether the server is closed.
+//   - runs (map[string]*run): The runs, keyed by identifier.
+type Server struct {
+	xsubfind3rv1.UnimplementedFinderServiceServer
+
+	finder  *xsubfind3r.Finder
+	tenants *server.Server
+	mu      sync.Mutex
+	closed  bool
+	runs    map[string]*run
+}
+
+// ListSources lists the sources of the finder the tenant may use, sorted by name.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - request (*xsubfind3rv1.ListSourcesRequest): The request.
+//
+// Returns:
+//   - response (*xsubfind3rv1.ListSourcesResponse): The sources.
+//   - err (error): An Unauthenticated error if the call carries no valid token.
+func (s *Server) ListSources(ctx context.Context, _ *xsubfind3rv1.ListSourcesRequest) (response *xsubfind3rv1.ListSourcesResponse, err error) {
+	var tenant server.Tenant
+
+	tenant, err = s.authenticate(ctx)
+	if err != nil {
+		return
+	}
+
+	response = &xsubfind3rv1.ListSourcesResponse{}
+
+	for _, info := range s.finder.Describe() {
+		if !permits(tenant, info.Name) {
+			continue
+		}
+
+		response.Sources = append(response.Sources, &xsubfind3rv1.Source{
+			Name:     info.Name,
+			Keyed:    info.Keyed,
+			Keys:     int32(info.Keys), //nolint:gosec // Key counts fit.
+			Enricher: info.Enricher,
+		})
+	}
+
+	return
+}
+
+// Enumerate runs an enumeration of the requested domain once a slot is free, streaming a
+// RunStarted event, then its results and periodic Progress events, then a RunFinished
+// event. The run is cancelled once the call is, in which case no RunFinished event can be
+// sent, or once CancelRun is called for it. Runs of tenants restricted to some sources
+// use only those.
+//
+// Parameters:
+//   - request (*xsubfind3rv1.EnumerateRequest): The request.
+//   - stream (xsubfind3rv1.FinderService_EnumerateServer): The stream of events.
+//
+// Returns:
+//   - err (error): An Unauthenticated error if the call carries no valid token,
+//     InvalidArgument if request is invalid, PermissionDenied if it names sources the
+//     tenant may not use, ResourceExhausted if the tenant has exhausted its daily quota,
+//     Unavailable if the server is closed, the error of the call if it was cancelled, or
+//     the error of the stream if sending failed.
+func (s *Server) Enumerate(request *xsubfind3rv1.EnumerateRequest, stream xsubfind3rv1.FinderService_EnumerateServer) (err error) {
+	var tenant server.Tenant
+
+	tenant, err = s.authenticate(stream.Context())
+	if err != nil {
+		return
+	}
+
+	normalizer := &input.Normalizer{}
+
+	var domain string
+
+	domain, err = normalizer.Normalize(request.GetDomain())
+	if err != nil {
+		err = status.Errorf(codes.InvalidArgument, "domain: %v", err)
+
+		return
+	}
+
+	var options []xsubfind3r.FindOption
+
+	options, err = s.options(tenant, request)
+	if err != nil {
+		return
+	}
+
+	var release func()
+
+	release, err = s.tenants.Acquire(stream.Context(), tenant.Name)
+	if err != nil {
+		err = statusOf(err)
+
+		return
+	}
+
+	defer release()
+
+	var id string
+
+	id, err = queue.NewID()
+	if err != nil {
+		err = status.Errorf(codes.Internal, "run identifier: %v", err)
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+
+	defer cancel()
+
+	r := newRun(id, domain, tenant.Name, cancel)
+
+	if err = s.add(r); err != nil {
+		return
+	}
+
+	started := &xsubfind3rv1.RunStarted{
+		RunId:  id,
+		Domain: domain,
+	}
+
+	for _, name := range s.finder.Sources() {
+		if (len(request.GetSources()) == 0 || slices.Contains(request.GetSources(), name)) && !slices.Contains(request.GetExclude(), name) && permits(tenant, name) {
+			started.Sources = append(started.Sources, name)
+		}
+	}
+
+	if err = stream.Send(&xsubfind3rv1.EnumerateResponse{Event: &xsubfind3rv1.EnumerateResponse_Started{Started: started}}); err != nil {
+		cancel()
+
+		r.finish(true)
+
+		return
+	}
+
+	interval := DefaultProgressInterval
+
+	if request.GetProgressInterval().AsDuration() > 0 {
+		interval = max(request.GetProgressInterval().AsDuration(), MinProgressInterval)
+	}
+
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	options = append(options, xsubfind3r.WithContext(ctx))
+
+	results := s.finder.Find(domain, options...)
+
+	for results != nil {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				results = nil
+
+				continue
+			}
+
+			r.add(result)
+
+			if err != nil {
+				continue
+			}
+
+			err = stream.Send(&xsubfind3rv1.EnumerateResponse{Event: &xsubfind3rv1.EnumerateResponse_Result{Result: newResult(result)}})
+		case <-ticker.C:
+			if err != nil {
+				continue
+			}
+
+			progress := &xsubfind3rv1.Progress{Stats: r.snapshot().GetStats()}
+
+			err = stream.Send(&xsubfind3rv1.EnumerateResponse{Event: &xsubfind3rv1.EnumerateResponse_Progress{Progress: progress}})
+		}
+
+		if err != nil {
+			// The results left, until the run stops, are only counted.
+			cancel()
+		}
+	}
+
+	r.finish(ctx.Err() != nil)
+
+	if err != nil {
+		return
+	}
+
+	if err = stream.Context().Err(); err != nil {
+		err = status.FromContextError(err).Err()
+
+		return
+	}
+
+	snapshot := r.snapshot()
+
+	finished := &xsubfind3rv1.RunFinished{
+		State: snapshot.GetState(),
+		Stats: snapshot.GetStats(),
+	}
+
+	err = stream.Send(&xsubfind3rv1.EnumerateResponse{Event: &xsubfind3rv1.EnumerateResponse_Finished{Finished: finished}})
+
+	return
+}
+
+// CancelRun cancels a run. Cancelling a finished run does nothing.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - request (*xsubfind3rv1.CancelRunRequest): The request.
+//
+// Returns:
+//   - response (*xsubfind3rv1.CancelRunResponse): The state of the run.
+//   - err (error): An Unauthenticated error if the call carries no valid token, or
+//     NotFound if the run is unknown, no longer kept, or another tenant's.
+func (s *Server) CancelRun(ctx context.Context, request *xsubfind3rv1.CancelRunRequest) (response *xsubfind3rv1.CancelRunResponse, err error) {
+	var r *run
+
+	r, err = s.lookup(ctx, request.GetRunId())
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	state := r.state
+
+	if state == xsubfind3rv1.RunState_RUN_STATE_RUNNING {
+		r.cancel()
+
+		// The run is marked cancelled once its call has wound it down.
+		state = xsubfind3rv1.RunState_RUN_STATE_CANCELLED
+	}
+
+	response = &xsubfind3rv1.CancelRunResponse{State: state}
+
+	return
+}
+
+// GetStats returns the state and statistics of a run.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - request (*xsubfind3rv1.GetStatsRequest): The request.
+//
+// Returns:
+//   - response (*xsubfind3rv1.GetStatsResponse): The state and statistics of the run.
+//   - err (error): An Unauthenticated error if the call carries no valid token, or
+//     NotFound if the run is unknown, no longer kept, or another tenant's.
+func (s *Server) GetStats(ctx context.Context, request *xsubfind3rv1.GetStatsRequest) (response *xsubfind3rv1.GetStatsResponse, err error) {
+	var r *run
+
+	r, err = s.lookup(ctx, request.GetRunId())
+	if err != nil {
+		return
+	}
+
+	response = r.snapshot()
+
+	return
+}
+
+// Close cancels every run. The server accepts no runs afterwards.
+func (s *Server) Close() {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	for _, r := range s.runs {
+		r.cancel()
+	}
+}
+
+// options returns the find options of request, run on behalf of tenant: restricted to
+// the sources of tenant, if it is.
+func (s *Server) options(tenant server.Tenant, request *xsubfind3rv1.EnumerateRequest) (options []xsubfind3r.FindOption, err error) {
+	names := s.finder.Sources()
+
+	for _, name := range slices.Concat(request.GetSources(), request.GetExclude()) {
+		if !slices.Contains(names, name) {
+			err = status.Errorf(codes.InvalidArgument, "unknown source %q", name)
+
+			return
+		}
+	}
+
+	for _, name := range request.GetSources() {
+		if !permits(tenant, name) {
+			err = status.Errorf(codes.PermissionDenied, "source %q not permitted", name)
+
+			return
+		}
+	}
+
+	if len(request.GetSources()) > 0 {
+		options = append(options, xsubfind3r.WithSources(request.GetSources()...))
+	} else if len(tenant.Sources) > 0 {
+		options = append(options, xsubfind3r.WithSources(tenant.Sources...))
+	}
+
+	if len(request.GetExclude()) > 0 {
+		options = append(options, xsubfind3r.WithoutSources(request.GetExclude()...))
+	}
+
+	if request.GetTimeout() != nil {
+		if err = request.GetTimeout().CheckValid(); err != nil || request.GetTimeout().AsDuration() <= 0 {
+			err = status.Error(codes.InvalidArgument, "timeout must be positive")
+
+			return
+		}
+
+		options = append(options, xsubfind3r.WithTimeout(request.GetTimeout().AsDuration()))
+	}
+
+	if request.GetMaxResults() < 0 {
+		err = status.Error(codes.InvalidArgument, "max_results must not be negative")
+
+		return
+	}
+
+	if request.GetMaxResults() > 0 {
+		options = append(options, xsubfind3r.WithMaxResults(int(request.GetMaxResults())))
+	}
+
+	if len(request.GetOutOfScope()) > 0 {
+		options = append(options, xsubfind3r.WithOutOfScope(request.GetOutOfScope()...))
+	}
+
+	if request.GetMaxAge() != nil {
+		if err = request.GetMaxAge().CheckValid(); err != nil || request.GetMaxAge().AsDuration() <= 0 {
+			err = status.Error(codes.InvalidArgument, "max_age must be positive")
+
+			return
+		}
+
+		options = append(options, xsubfind3r.WithMaxAge(request.GetMaxAge().AsDuration()))
+	}
+
+	if request.GetMinScore() < 0 || request.GetMinScore() > 1 {
+		err = status.Error(codes.InvalidArgument, "min_score must be between 0 and 1")
+
+		return
+	}
+
+	if request.GetMinScore() > 0 {
+		options = append(options, xsubfind3r.WithMinScore(request.GetMinScore()))
+	}
+
+	if request.GetProgressInterval() != nil {
+		if err = request.GetProgressInterval().CheckValid(); err != nil {
+			err = status.Errorf(codes.InvalidArgument, "progress_interval: %v", err)
+
+			return
+		}
+	}
+
+	return
+}
+
+// add registers r, forgetting the runs finished longer than Retention ago.
+func (s *Server) add(r *run) (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if s.closed {
+		err = status.Error(codes.Unavailable, ErrClosed.Error())
+
+		return
+	}
+
+	now := time.Now()
+
+	for id, old := range s.runs {
+		if old.expired(now, Retention) {
+			delete(s.runs, id)
+		}
+	}
+
+	s.runs[r.id] = r
+
+	return
+}
+
+// authenticate returns the tenant authenticated by the bearer token of the call, carried
+// as "authorization" metadata.
+func (s *Server) authenticate(ctx context.Context) (tenant server.Tenant, err error) {
+	var token string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			scheme, value, _ := strings.Cut(values[0], " ")
+
+			if strings.EqualFold(scheme, "Bearer") {
+				token = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	tenant, ok := s.tenants.Authenticate(token)
+	if !ok {
+		err = status.Error(codes.Unauthenticated, server.ErrUnauthorized.Error())
+	}
+
+	return
+}
+
+// lookup returns the run identified by id, if the tenant authenticated by the call
+// started it or is an administrator.
+func (s *Server) lookup(ctx context.Context, id string) (r *run, err error) {
+	var tenant server.Tenant
+
+	tenant, err = s.authenticate(ctx)
+	if err != nil {
+		return
+	}
+
+	r, err = s.run(id)
+	if err != nil {
+		return
+	}
+
+	if !tenant.Admin && r.tenant != tenant.Name {
+		r = nil
+
+		err = status.Errorf(codes.NotFound, "unknown run %q", id)
+	}
+
+	return
+}
+
+// run returns the run identified by id.
+func (s *Server) run(id string) (r *run, err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	r, ok := s.runs[id]
+	if !ok || r.expired(time.Now(), Retention) {
+		err = status.Errorf(codes.NotFound, "unknown run %q", id)
+	}
+
+	return
+}
+
+// permits reports whether the runs of tenant may use the named source.
+func permits(tenant server.Tenant, name string) (ok bool) {
+	ok = len(tenant.Sources) == 0 || slices.Contains(tenant.Sources, name)
+
+	return
+}
+
+// statusOf returns the status error of an error of server.Server.Acquire.
+func statusOf(err error) (serr error) {
+	switch {
+	case errors.Is(err, server.ErrQuotaExceeded):
+		serr = status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, server.ErrForbidden):
+		serr = status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, server.ErrClosed):
+		serr = status.Error(codes.Unavailable, ErrClosed.Error())
+	default:
+		serr = status.FromContextError(err).Err()
+	}
+
+	return
+}
+
+// New returns a server running enumerations with finder, on behalf of the tenants of
+// tenants and within its slots.
+//
+// Parameters:
+//   - finder (*xsubfind3r.Finder): The finder running the enumerations.
+//   - tenants (*server.Server): The server whose tenants and slots runs are subject to.
+//
+// Returns:
+//   - s (*Server): The server.
+func New(finder *xsubfind3r.Finder, tenants *server.Server) (s *Server) {
+	s = &Server{
+		finder:  finder,
+		tenants: tenants,
+		runs:    map[string]*run{},
+	}
+
+	return
+}
+
+const (
+	// DefaultProgressInterval is how often progress events are sent, unless requested
+	// otherwise.
+	DefaultProgressInterval = 5 * time.Second
+	// MinProgressInterval is the shortest interval progress events may be requested at.
+	MinProgressInterval = 100 * time.Millisecond
+	// Retention is how long finished runs are kept for GetStats.
+	Retention = time.Hour
+)
+
+// ErrClosed is returned for runs started on a closed server.
+var ErrClosed = errors.New("server closed")
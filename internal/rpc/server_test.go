@@ -0,0 +1,270 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/server"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	xsubfind3rv1 "github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/rpc/v1"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tenants are the tenants of the services under test: alice is restricted to the hosts
+// file and to one run per day, bob is not restricted.
+var tenants = []server.Tenant{
+	{Name: "alice", Token: "alice-token", Sources: []string{sources.HOSTSFILE}, Quota: 1},
+	{Name: "bob", Token: "bob-token"},
+}
+
+// serve serves a service running at most concurrency runs at once, with a finder reading
+// a hosts file and a Certificate Transparency log that answers once gate is closed, and
+// returns a client of it.
+func serve(t *testing.T, concurrency int, gate chan struct{}) (client xsubfind3rv1.FinderServiceClient) {
+	t.Helper()
+
+	log := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-gate:
+		case <-r.Context().Done():
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		fmt.Fprint(w, `{"tree_size":0}`)
+	}))
+
+	t.Cleanup(log.Close)
+
+	hosts := filepath.Join(t.TempDir(), "hosts")
+
+	if err := os.WriteFile(hosts, []byte("127.0.0.1 a.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	finder, err := xsubfind3r.New(&xsubfind3r.Configuration{
+		SourcesToUSe: []string{sources.CTLOGS, sources.HOSTSFILE},
+		CTLogs: sources.CTLogsConfiguration{
+			Logs: []sources.CTLogConfiguration{{URL: log.URL + "/"}},
+		},
+		Imports: sources.ImportsConfiguration{
+			Hosts: []string{hosts},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slots, err := server.New(finder, concurrency, tenants)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(finder, slots)
+
+	srv := grpc.NewServer()
+
+	xsubfind3rv1.RegisterFinderServiceServer(srv, s)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+
+		s.Close()
+
+		slots.Close()
+
+		srv.Stop()
+	})
+
+	client = xsubfind3rv1.NewFinderServiceClient(conn)
+
+	return
+}
+
+// as returns a context carrying token.
+func as(token string) (ctx context.Context) {
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+
+	return
+}
+
+// enumerate runs an enumeration of request, returning its events, or the error ending it.
+func enumerate(ctx context.Context, client xsubfind3rv1.FinderServiceClient, request *xsubfind3rv1.EnumerateRequest) (events []*xsubfind3rv1.EnumerateResponse, err error) {
+	stream, err := client.Enumerate(ctx, request)
+	if err != nil {
+		return
+	}
+
+	for {
+		var event *xsubfind3rv1.EnumerateResponse
+
+		event, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			err = nil
+
+			return
+		}
+
+		if err != nil {
+			return
+		}
+
+		events = append(events, event)
+	}
+}
+
+// TestAuthenticate checks that calls require the token of a tenant, and that tenants list
+// only the sources they may use.
+func TestAuthenticate(t *testing.T) {
+	client := serve(t, 1, nil)
+
+	for _, ctx := range []context.Context{context.Background(), as("wrong")} {
+		if _, err := client.ListSources(ctx, &xsubfind3rv1.ListSourcesRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", err)
+		}
+	}
+
+	if _, err := enumerate(context.Background(), client, &xsubfind3rv1.EnumerateRequest{Domain: "example.com"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got %v enumerating, want Unauthenticated", err)
+	}
+
+	response, err := client.ListSources(as("alice-token"), &xsubfind3rv1.ListSourcesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.GetSources()) != 1 || response.GetSources()[0].GetName() != sources.HOSTSFILE {
+		t.Errorf("got sources %v, want %s only", response.GetSources(), sources.HOSTSFILE)
+	}
+}
+
+// TestEnumerateTenant checks that runs are restricted to the sources and quota of their
+// tenant, and hidden from other tenants.
+func TestEnumerateTenant(t *testing.T) {
+	client := serve(t, 1, nil)
+
+	request := &xsubfind3rv1.EnumerateRequest{Domain: "example.com", Sources: []string{sources.CTLOGS}}
+
+	if _, err := enumerate(as("alice-token"), client, request); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got %v naming a source not permitted, want PermissionDenied", err)
+	}
+
+	events, err := enumerate(as("alice-token"), client, &xsubfind3rv1.EnumerateRequest{Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := events[0].GetStarted()
+
+	if !slices.Equal(started.GetSources(), []string{sources.HOSTSFILE}) {
+		t.Errorf("got sources %v, want %s only", started.GetSources(), sources.HOSTSFILE)
+	}
+
+	if events[len(events)-1].GetFinished() == nil {
+		t.Errorf("got %v, want the run to finish", events[len(events)-1])
+	}
+
+	if _, err = enumerate(as("alice-token"), client, &xsubfind3rv1.EnumerateRequest{Domain: "example.com"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got %v beyond the quota, want ResourceExhausted", err)
+	}
+
+	stats := &xsubfind3rv1.GetStatsRequest{RunId: started.GetRunId()}
+
+	if _, err = client.GetStats(as("bob-token"), stats); status.Code(err) != codes.NotFound {
+		t.Errorf("got %v for the run of another tenant, want NotFound", err)
+	}
+
+	if _, err = client.CancelRun(as("bob-token"), &xsubfind3rv1.CancelRunRequest{RunId: started.GetRunId()}); status.Code(err) != codes.NotFound {
+		t.Errorf("got %v cancelling the run of another tenant, want NotFound", err)
+	}
+
+	if _, err = client.GetStats(as("alice-token"), stats); err != nil {
+		t.Errorf("got %v for the run of the tenant", err)
+	}
+}
+
+// TestEnumerateSlots checks that runs beyond the concurrency of the service wait for a
+// slot before they start.
+func TestEnumerateSlots(t *testing.T) {
+	gate := make(chan struct{})
+
+	client := serve(t, 1, gate)
+
+	ctx, cancel := context.WithTimeout(as("bob-token"), time.Minute)
+
+	defer cancel()
+
+	first, err := client.Enumerate(ctx, &xsubfind3rv1.EnumerateRequest{Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = first.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := client.Enumerate(ctx, &xsubfind3rv1.EnumerateRequest{Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan error, 1)
+
+	go func() {
+		_, err := second.Recv()
+
+		started <- err
+	}()
+
+	select {
+	case err = <-started:
+		t.Fatalf("second run started (%v) while the first held the only slot", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	close(gate)
+
+	for {
+		if _, err = first.Recv(); err != nil {
+			break
+		}
+	}
+
+	if !errors.Is(err, io.EOF) {
+		t.Fatal(err)
+	}
+
+	if err = <-started; err != nil {
+		t.Errorf("second run: %v", err)
+	}
+}
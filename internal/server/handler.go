@@ -0,0 +1,388 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/output"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Handler returns the HTTP API of the server:
+//
+//	GET    /healthz                 liveness probe
+//	GET    /v1/sources              the sources jobs may use
+//	POST   /v1/jobs                 submit a job, with a Request as body
+//	GET    /v1/jobs                 list the jobs
+//	GET    /v1/jobs/{id}            the state and statistics of a job
+//	DELETE /v1/jobs/{id}            cancel a job
+//	GET    /v1/jobs/{id}/results    the results of a finished job
+//	GET    /v1/jobs/{id}/stream     stream the results of a job as they are found
+//	GET    /v1/usage                the usage of the tenant
+//	GET    /v1/admin/usage          the usage of every tenant, for administrators
+//
+// Unless the API is open, every endpoint but the liveness probe requires the token of a
+// tenant, as "Authorization: Bearer <token>". Tenants see, and cancel, only their own
+// jobs; administrators those of every tenant.
+//
+// Errors are reported as {"error": "..."} with an appropriate status code.
+//
+// Returns:
+//   - handler (http.Handler): The handler.
+func (s *Server) Handler() (handler http.Handler) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /v1/sources", s.authenticated(s.handleSources))
+	mux.HandleFunc("POST /v1/jobs", s.authenticated(s.handleSubmit))
+	mux.HandleFunc("GET /v1/jobs", s.authenticated(s.handleList))
+	mux.HandleFunc("GET /v1/jobs/{id}", s.authenticated(s.handleJob))
+	mux.HandleFunc("DELETE /v1/jobs/{id}", s.authenticated(s.handleCancel))
+	mux.HandleFunc("GET /v1/jobs/{id}/results", s.authenticated(s.handleResults))
+	mux.HandleFunc("GET /v1/jobs/{id}/stream", s.authenticated(s.handleStream))
+	mux.HandleFunc("GET /v1/usage", s.authenticated(s.handleUsage))
+	mux.HandleFunc("GET /v1/admin/usage", s.authenticated(s.handleUsages))
+
+	handler = mux
+
+	return
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// authenticated wraps handler, passing it the tenant authenticated by the request, or
+// responds with 401 Unauthorized if there is none.
+func (s *Server) authenticated(handler func(http.ResponseWriter, *http.Request, *account)) (wrapped http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+
+		if !strings.EqualFold(scheme, "Bearer") {
+			token = ""
+		}
+
+		acc, ok := s.authenticate(strings.TrimSpace(token))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="xsubfind3r"`)
+
+			writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+
+			return
+		}
+
+		handler(w, r, acc)
+	}
+}
+
+func (s *Server) handleSources(w http.ResponseWriter, _ *http.Request, acc *account) {
+	names := []string{}
+
+	for _, name := range s.finder.Sources() {
+		if acc.permits(name) {
+			names = append(names, name)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]string{"sources": names})
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request, acc *account) {
+	var request Request
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %w", ErrInvalidRequest, err))
+
+		return
+	}
+
+	job, err := s.Submit(acc.tenant.Name, request)
+	if err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			retry := time.Until(nextDay(time.Now())).Seconds()
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry))))
+		}
+
+		writeError(w, statusOf(err), err)
+
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+
+	writeJSON(w, http.StatusAccepted, job.View())
+}
+
+func (s *Server) handleList(w http.ResponseWriter, _ *http.Request, acc *account) {
+	views := []View{}
+
+	for _, job := range s.Jobs() {
+		if acc.tenant.Admin || job.account == acc {
+			views = append(views, job.View())
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]View{"jobs": views})
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request, acc *account) {
+	job, ok := s.lookup(w, r, acc)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job.View())
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request, acc *account) {
+	job, ok := s.lookup(w, r, acc)
+	if !ok {
+		return
+	}
+
+	job.Cancel()
+
+	writeJSON(w, http.StatusAccepted, job.View())
+}
+
+// handleResults responds with the results of a finished job, or 409 Conflict while it is
+// queued or running:
+//
+//	{"job": {...}, "results": [...], "errors": [{"source", "kind", "error"}]}
+//
+// Results are represented as in JSONL output.
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request, acc *account) {
+	job, ok := s.lookup(w, r, acc)
+	if !ok {
+		return
+	}
+
+	results, status, _ := job.Since(0)
+
+	if !status.Done() {
+		writeError(w, http.StatusConflict, fmt.Errorf("%w: job is %s", ErrNotFinished, status))
+
+		return
+	}
+
+	response := resultsResponse{
+		Job:     job.View(),
+		Results: []output.Record{},
+		Errors:  []errorRecord{},
+	}
+
+	for _, result := range results {
+		if result.Type == sources.ResultError {
+			response.Errors = append(response.Errors, newErrorRecord(result))
+
+			continue
+		}
+
+		response.Results = append(response.Results, output.NewRecord("", result))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleStream streams the results of a job, from the first, as they are found, until the
+// job finishes or the client goes away. Two formats are supported:
+//
+//   - NDJSON (the default): one result per line, as in JSONL output. Errors are left out.
+//   - Server-Sent Events, with "?format=sse" or "Accept: text/event-stream": "result" events
+//     carrying a result as in JSONL output, "error" events carrying {"source", "kind",
+//     "error"}, and a final "done" event carrying the state of the job.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request, acc *account) {
+	job, ok := s.lookup(w, r, acc)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming unsupported"))
+
+		return
+	}
+
+	sse := r.URL.Query().Get("format") == "sse" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	index := 0
+
+	for {
+		results, status, changed := job.Since(index)
+
+		index += len(results)
+
+		for _, result := range results {
+			var err error
+
+			switch {
+			case sse && result.Type == sources.ResultError:
+				err = writeEvent(w, "error", newErrorRecord(result))
+			case sse:
+				err = writeEvent(w, "result", output.NewRecord(job.Request.Domain, result))
+			case result.Type == sources.ResultError:
+				continue
+			default:
+				err = json.NewEncoder(w).Encode(output.NewRecord(job.Request.Domain, result))
+			}
+
+			if err != nil {
+				return
+			}
+		}
+
+		if status.Done() {
+			if sse {
+				_ = writeEvent(w, "done", job.View())
+			}
+
+			flusher.Flush()
+
+			return
+		}
+
+		flusher.Flush()
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) handleUsage(w http.ResponseWriter, _ *http.Request, acc *account) {
+	usage, _ := s.Usage(acc.tenant.Name)
+
+	writeJSON(w, http.StatusOK, usage)
+}
+
+func (s *Server) handleUsages(w http.ResponseWriter, _ *http.Request, acc *account) {
+	if !acc.tenant.Admin {
+		writeError(w, http.StatusForbidden, ErrForbidden)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]Usage{"usage": s.Usages()})
+}
+
+// lookup returns the job named by the request path, responding with 404 Not Found if
+// there is none, or if it is another tenant's and acc is not an administrator.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request, acc *account) (job *Job, ok bool) {
+	job, ok = s.Job(r.PathValue("id"))
+	if ok && !acc.tenant.Admin && job.account != acc {
+		job, ok = nil, false
+	}
+
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrNotFound)
+	}
+
+	return
+}
+
+// resultsResponse is the response of the results endpoint.
+type resultsResponse struct {
+	Job     View            `json:"job"`
+	Results []output.Record `json:"results"`
+	Errors  []errorRecord   `json:"errors"`
+}
+
+// errorRecord is the JSON representation of a source error.
+type errorRecord struct {
+	Source string `json:"source"`
+	Kind   string `json:"kind"`
+	Error  string `json:"error"`
+}
+
+func newErrorRecord(result sources.Result) (data errorRecord) {
+	data = errorRecord{
+		Source: result.Source,
+		Kind:   sources.KindOf(result.Error).Error(),
+	}
+
+	if result.Error != nil {
+		data.Error = result.Error.Error()
+	}
+
+	return
+}
+
+// writeJSON responds with v in JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError responds with err as {"error": "..."}.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeEvent writes a Server-Sent Event named event carrying v in JSON.
+func writeEvent(w http.ResponseWriter, event string, v any) (err error) {
+	var data []byte
+
+	data, err = json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+
+	return
+}
+
+// statusOf returns the status code reporting err.
+func statusOf(err error) (status int) {
+	switch {
+	case errors.Is(err, ErrInvalidRequest):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrQuotaExceeded):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, ErrQueueFull), errors.Is(err, ErrClosed):
+		status = http.StatusServiceUnavailable
+	default:
+		status = http.StatusInternalServerError
+	}
+
+	return
+}
+
+// maxRequestSize bounds the size of job submissions.
+const maxRequestSize = 1 << 20
+
+var (
+	// ErrNotFound is reported for unknown jobs.
+	ErrNotFound = errors.New("job not found")
+	// ErrNotFinished is reported when the results of a job are requested before it
+	// finishes.
+	ErrNotFinished = errors.New("job not finished")
+)
@@ -0,0 +1,384 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/input"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Status is the state of a job.
+type Status string
+
+// Done reports whether a job in this state has finished.
+func (s Status) Done() (ok bool) {
+	ok = s == StatusCompleted || s == StatusCancelled || s == StatusFailed
+
+	return
+}
+
+// Request is the submission of a job.
+//
+// Fields:
+//   - Domain (string): The target domain.
+//   - Sources ([]string): The sources to use, or none for every source of the server.
+//   - Exclude ([]string): The sources not to use.
+//   - MaxAge (string): Drop results last seen longer ago than this, e.g. "90d".
+//   - MinScore (float64): Drop subdomains scoring below this.
+//   - MaxResults (int): Stop after this many subdomains.
+//   - Timeout (string): Stop after this long, e.g. "10m".
+type Request struct {
+	Domain     string   `json:"domain"`
+	Sources    []string `json:"sources,omitempty"`
+	Exclude    []string `json:"exclude,omitempty"`
+	MaxAge     string   `json:"max_age,omitempty"`
+	MinScore   float64  `json:"min_score,omitempty"`
+	MaxResults int      `json:"max_results,omitempty"`
+	Timeout    string   `json:"timeout,omitempty"`
+}
+
+// options returns the find options of the request.
+func (request *Request) options() (options []xsubfind3r.FindOption, err error) {
+	for _, name := range slices.Concat(request.Sources, request.Exclude) {
+		if !sources.Supported(name) {
+			err = fmt.Errorf("%w: unknown source %q", ErrInvalidRequest, name)
+
+			return
+		}
+	}
+
+	if len(request.Sources) > 0 {
+		options = append(options, xsubfind3r.WithSources(request.Sources...))
+	}
+
+	if len(request.Exclude) > 0 {
+		options = append(options, xsubfind3r.WithoutSources(request.Exclude...))
+	}
+
+	if request.MaxAge != "" {
+		var age time.Duration
+
+		age, err = input.ParseDuration(request.MaxAge)
+		if err != nil {
+			err = fmt.Errorf("%w: max_age: %w", ErrInvalidRequest, err)
+
+			return
+		}
+
+		options = append(options, xsubfind3r.WithMaxAge(age))
+	}
+
+	if request.MinScore < 0 || request.MinScore > 1 {
+		err = fmt.Errorf("%w: min_score must be between 0 and 1", ErrInvalidRequest)
+
+		return
+	}
+
+	if request.MinScore > 0 {
+		options = append(options, xsubfind3r.WithMinScore(request.MinScore))
+	}
+
+	if request.MaxResults < 0 {
+		err = fmt.Errorf("%w: max_results must not be negative", ErrInvalidRequest)
+
+		return
+	}
+
+	if request.MaxResults > 0 {
+		options = append(options, xsubfind3r.WithMaxResults(request.MaxResults))
+	}
+
+	if request.Timeout != "" {
+		var timeout time.Duration
+
+		timeout, err = input.ParseDuration(request.Timeout)
+		if err != nil {
+			err = fmt.Errorf("%w: timeout: %w", ErrInvalidRequest, err)
+
+			return
+		}
+
+		options = append(options, xsubfind3r.WithTimeout(timeout))
+	}
+
+	return
+}
+
+// Job is an enumeration job. Its results are kept in the order they were found, errors
+// included, so that streams can replay them.
+//
+// Fields:
+//   - ID (string): The identifier of the job.
+//   - Tenant (string): The name of the tenant that submitted the job.
+//   - Request (Request): The submission, with the domain normalized.
+//   - account (*account): The tenant that submitted the job.
+//   - ctx (context.Context): Done once the job is cancelled.
+//   - cancel (context.CancelFunc): Cancels the job.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - status (Status): The state of the job.
+//   - created, started, finished (time.Time): When the job was submitted, started and
+//     finished; zero until then.
+//   - results ([]sources.Result): The results found so far, errors included.
+//   - stats (Stats): The statistics of the results found so far.
+//   - changed (chan struct{}): Closed, and replaced, whenever the job changes.
+type Job struct {
+	ID      string
+	Tenant  string
+	Request Request
+
+	account  *account
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mu       sync.Mutex
+	status   Status
+	created  time.Time
+	started  time.Time
+	finished time.Time
+	results  []sources.Result
+	stats    Stats
+	changed  chan struct{}
+}
+
+// Stats summarizes the results of a job.
+//
+// Fields:
+//   - Subdomains (int): The number of subdomains found.
+//   - Results (int): The number of results found, subdomains included, errors excluded.
+//   - Errors (int): The number of errors reported by sources.
+//   - Sources (map[string]*SourceStats): The statistics of each source, keyed by name.
+type Stats struct {
+	Subdomains int                     `json:"subdomains"`
+	Results    int                     `json:"results"`
+	Errors     int                     `json:"errors"`
+	Sources    map[string]*SourceStats `json:"sources"`
+}
+
+// SourceStats summarizes the results of a single source of a job.
+//
+// Fields:
+//   - Subdomains (int): The number of subdomains credited to the source, i.e. that it
+//     found first.
+//   - Errors (int): The number of errors the source reported.
+type SourceStats struct {
+	Subdomains int `json:"subdomains"`
+	Errors     int `json:"errors"`
+}
+
+// View is the JSON representation of the state of a job.
+//
+// Fields:
+//   - ID (string): The identifier of the job.
+//   - Tenant (string): The name of the tenant that submitted the job.
+//   - Status (Status): The state of the job.
+//   - Request (Request): The submission.
+//   - CreatedAt, StartedAt, FinishedAt (string): When the job was submitted, started and
+//     finished, in RFC 3339, or "" until then.
+//   - Duration (string): How long the job ran, or has been running.
+//   - Stats (Stats): The statistics of the results found so far.
+type View struct {
+	ID         string  `json:"id"`
+	Tenant     string  `json:"tenant,omitempty"`
+	Status     Status  `json:"status"`
+	Request    Request `json:"request"`
+	CreatedAt  string  `json:"created_at"`
+	StartedAt  string  `json:"started_at,omitempty"`
+	FinishedAt string  `json:"finished_at,omitempty"`
+	Duration   string  `json:"duration,omitempty"`
+	Stats      Stats   `json:"stats"`
+}
+
+// View returns the current state of the job.
+//
+// Returns:
+//   - view (View): The state of the job.
+func (job *Job) View() (view View) {
+	job.mu.Lock()
+
+	defer job.mu.Unlock()
+
+	view = View{
+		ID:        job.ID,
+		Tenant:    job.Tenant,
+		Status:    job.status,
+		Request:   job.Request,
+		CreatedAt: job.created.Format(time.RFC3339),
+		Stats: Stats{
+			Subdomains: job.stats.Subdomains,
+			Results:    job.stats.Results,
+			Errors:     job.stats.Errors,
+			Sources:    map[string]*SourceStats{},
+		},
+	}
+
+	for name, stats := range job.stats.Sources {
+		view.Stats.Sources[name] = &SourceStats{
+			Subdomains: stats.Subdomains,
+			Errors:     stats.Errors,
+		}
+	}
+
+	if !job.started.IsZero() {
+		view.StartedAt = job.started.Format(time.RFC3339)
+
+		end := job.finished
+
+		if end.IsZero() {
+			end = time.Now()
+		}
+
+		view.Duration = end.Sub(job.started).Round(time.Millisecond).String()
+	}
+
+	if !job.finished.IsZero() {
+		view.FinishedAt = job.finished.Format(time.RFC3339)
+	}
+
+	return
+}
+
+// Status returns the state of the job.
+func (job *Job) Status() (status Status) {
+	job.mu.Lock()
+
+	defer job.mu.Unlock()
+
+	status = job.status
+
+	return
+}
+
+// Cancel cancels the job. Cancelling a finished job does nothing.
+func (job *Job) Cancel() {
+	job.cancel()
+}
+
+// Since returns the results found from index on, the state of the job, and a channel
+// closed at its next change.
+//
+// Parameters:
+//   - index (int): The number of results already seen.
+//
+// Returns:
+//   - results ([]sources.Result): The results found from index on, errors included.
+//   - status (Status): The state of the job.
+//   - changed (<-chan struct{}): Closed at the next change of the job.
+func (job *Job) Since(index int) (results []sources.Result, status Status, changed <-chan struct{}) {
+	job.mu.Lock()
+
+	defer job.mu.Unlock()
+
+	if index < len(job.results) {
+		results = job.results[index:len(job.results):len(job.results)]
+	}
+
+	status = job.status
+	changed = job.changed
+
+	return
+}
+
+// start marks the job running, unless it was cancelled while queued.
+func (job *Job) start() (ok bool) {
+	job.mu.Lock()
+
+	defer job.mu.Unlock()
+
+	if job.ctx.Err() != nil {
+		return
+	}
+
+	job.status = StatusRunning
+	job.started = time.Now()
+
+	job.notify()
+
+	ok = true
+
+	return
+}
+
+// add records result.
+func (job *Job) add(result sources.Result) {
+	job.mu.Lock()
+
+	defer job.mu.Unlock()
+
+	job.results = append(job.results, result)
+
+	stats, ok := job.stats.Sources[result.Source]
+	if !ok {
+		stats = &SourceStats{}
+
+		job.stats.Sources[result.Source] = stats
+	}
+
+	switch result.Type {
+	case sources.ResultError:
+		job.stats.Errors++
+
+		stats.Errors++
+	case sources.ResultSubdomain:
+		job.stats.Subdomains++
+		job.stats.Results++
+
+		stats.Subdomains++
+	default:
+		job.stats.Results++
+	}
+
+	job.notify()
+}
+
+// finish marks the job finished: cancelled if it was cancelled before completing,
+// completed otherwise.
+func (job *Job) finish() {
+	job.mu.Lock()
+
+	defer job.mu.Unlock()
+
+	job.status = StatusCompleted
+
+	if job.ctx.Err() != nil {
+		job.status = StatusCancelled
+	}
+
+	job.finished = time.Now()
+
+	job.notify()
+}
+
+// restore marks the job finished as it was before a restart.
+func (job *Job) restore(status Status, started, finished time.Time) {
+	job.mu.Lock()
+
+	defer job.mu.Unlock()
+
+	job.cancel()
+
+	job.status = status
+	job.started = started
+	job.finished = finished
+
+	job.notify()
+}
+
+// notify wakes those waiting for a change of the job. The caller must hold mu.
+func (job *Job) notify() {
+	close(job.changed)
+
+	job.changed = make(chan struct{})
+}
+
+// States of a job.
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+	StatusFailed    Status = "failed"
+)
@@ -0,0 +1,746 @@
+// Package server exposes enumeration as a service: an HTTP API to submit jobs, follow
+// their progress, stream their results and fetch them once finished.
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	hqgologger "github.com/hueristiq/hq-go-logger"
+	"github.com/hueristiq/xsubfind3r/internal/input"
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Server runs enumeration jobs with a Finder, at most concurrency at a time; further jobs
+// wait in a queue. Jobs are submitted by tenants, subject to their permissions, quotas
+// and concurrency limits. Jobs and their results are kept in memory, up to
+// MaxFinishedJobs finished jobs, and, once Resume is called, in a durable queue.
+//
+// Fields:
+//   - finder (*xsubfind3r.Finder): The finder running the jobs.
+//   - slots (chan struct{}): Bounds the number of running jobs.
+//   - open (bool): Whether the API is open, i.e. no tenants are configured; jobs are then
+//     submitted by a single anonymous administrator.
+//   - accounts ([]*account): The tenants.
+//   - queue (*queue.Queue): The queue persisting the jobs, or nil.
+//   - ctx (context.Context): Done once the server is closed.
+//   - cancel (context.CancelFunc): Closes the server.
+//   - wg (sync.WaitGroup): Tracks the jobs not yet finished.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - jobs (map[string]*Job): The jobs, keyed by identifier.
+//   - order ([]*Job): The jobs, in the order they were submitted.
+type Server struct {
+	finder   *xsubfind3r.Finder
+	slots    chan struct{}
+	open     bool
+	accounts []*account
+	queue    *queue.Queue
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	jobs     map[string]*Job
+	order    []*Job
+}
+
+// Submit validates request and queues a job for it on behalf of tenant. Jobs of tenants
+// restricted to some sources use only those.
+//
+// Parameters:
+//   - tenant (string): The name of the tenant, or "" if no tenants are configured.
+//   - request (Request): The submission.
+//
+// Returns:
+//   - job (*Job): The queued job.
+//   - err (error): An error wrapping ErrInvalidRequest if request is invalid, ErrForbidden
+//     if it names sources the tenant may not use, ErrQuotaExceeded if the tenant has
+//     exhausted its daily quota, or ErrQueueFull if too many jobs are waiting.
+func (s *Server) Submit(tenant string, request Request) (job *Job, err error) {
+	acc, ok := s.account(tenant)
+	if !ok {
+		err = fmt.Errorf("%w: unknown tenant %q", ErrForbidden, tenant)
+
+		return
+	}
+
+	normalizer := &input.Normalizer{}
+
+	request.Domain, err = normalizer.Normalize(request.Domain)
+	if err != nil {
+		err = fmt.Errorf("%w: domain: %w", ErrInvalidRequest, err)
+
+		return
+	}
+
+	var options []xsubfind3r.FindOption
+
+	options, err = acc.options(request)
+	if err != nil {
+		return
+	}
+
+	var id string
+
+	id, err = queue.NewID()
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		err = ErrClosed
+
+		return
+	}
+
+	if s.queued() >= MaxQueuedJobs {
+		err = ErrQueueFull
+
+		return
+	}
+
+	now := time.Now()
+
+	acc.today(now)
+
+	if acc.tenant.Quota > 0 && acc.usage.Submitted >= acc.tenant.Quota {
+		acc.usage.Rejected++
+
+		err = fmt.Errorf("%w: %d jobs per day", ErrQuotaExceeded, acc.tenant.Quota)
+
+		return
+	}
+
+	if s.queue != nil {
+		if _, err = s.queue.Push(id, queue.KindServer, payload{Tenant: acc.tenant.Name, Request: request}); err != nil {
+			return
+		}
+	}
+
+	acc.usage.Submitted++
+	acc.usage.Jobs++
+
+	job = s.newJob(id, acc, request, now)
+
+	s.admit(job, options)
+
+	s.prune()
+
+	return
+}
+
+// Resume persists jobs in q from then on, and restores the jobs q holds: finished jobs,
+// with their results, and pending ones, including those interrupted while running, which
+// are queued again. Jobs of tenants no longer configured are left untouched in q. Resume
+// must be called before any job is submitted.
+//
+// Parameters:
+//   - q (*queue.Queue): The queue.
+//
+// Returns:
+//   - err (error): An error reading the jobs.
+func (s *Server) Resume(q *queue.Queue) (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.queue = q
+
+	now := time.Now()
+
+	for _, stored := range q.Jobs(queue.KindServer) {
+		var p payload
+
+		if err = json.Unmarshal(stored.Payload, &p); err != nil {
+			err = fmt.Errorf("job %s: %w", stored.ID, err)
+
+			return
+		}
+
+		acc, ok := s.account(p.Tenant)
+		if !ok {
+			continue
+		}
+
+		acc.today(now)
+
+		if acc.usage.Date == stored.Created.UTC().Format(time.DateOnly) {
+			acc.usage.Submitted++
+		}
+
+		acc.usage.Jobs++
+
+		job := s.newJob(stored.ID, acc, p.Request, stored.Created)
+
+		options, oerr := acc.options(p.Request)
+
+		switch {
+		case stored.State.Done():
+			var results []sources.Result
+
+			results, err = q.Results(stored.ID)
+			if err != nil {
+				err = fmt.Errorf("job %s: %w", stored.ID, err)
+
+				return
+			}
+
+			for _, result := range results {
+				job.add(result)
+			}
+
+			job.restore(statuses[stored.State], stored.Started, stored.Finished)
+		case oerr != nil:
+			// The tenant may no longer run the job, e.g. its sources were restricted since.
+			if err = q.Finish(stored.ID, queue.StateCancelled); err != nil {
+				return
+			}
+
+			job.restore(StatusCancelled, time.Time{}, now)
+		default:
+			s.admit(job, options)
+
+			continue
+		}
+
+		acc.settle(job.View())
+
+		s.jobs[job.ID] = job
+		s.order = append(s.order, job)
+	}
+
+	s.prune()
+
+	s.wg.Add(1)
+
+	go s.sweep()
+
+	return
+}
+
+// Job returns the job with the given identifier.
+//
+// Parameters:
+//   - id (string): The identifier of the job.
+//
+// Returns:
+//   - job (*Job): The job.
+//   - ok (bool): Whether there is such a job.
+func (s *Server) Job(id string) (job *Job, ok bool) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	job, ok = s.jobs[id]
+
+	return
+}
+
+// Jobs returns every job, in the order they were submitted.
+//
+// Returns:
+//   - jobs ([]*Job): The jobs.
+func (s *Server) Jobs() (jobs []*Job) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	jobs = append(jobs, s.order...)
+
+	return
+}
+
+// Usage returns the usage of the server by tenant.
+//
+// Parameters:
+//   - tenant (string): The name of the tenant, or "" if no tenants are configured.
+//
+// Returns:
+//   - usage (Usage): The usage of the tenant.
+//   - ok (bool): Whether there is such a tenant.
+func (s *Server) Usage(tenant string) (usage Usage, ok bool) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	acc, ok := s.account(tenant)
+	if !ok {
+		return
+	}
+
+	usage = s.usage(acc)
+
+	return
+}
+
+// Usages returns the usage of the server by every tenant, in the order they are
+// configured.
+//
+// Returns:
+//   - usages ([]Usage): The usage of the tenants.
+func (s *Server) Usages() (usages []Usage) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	for _, acc := range s.accounts {
+		usages = append(usages, s.usage(acc))
+	}
+
+	return
+}
+
+// Authenticate returns the tenant authenticated by token, as the HTTP API does, e.g. for
+// another API sharing the tenants of the server. If no tenants are configured, every
+// caller is the anonymous administrator, whatever its token.
+//
+// Parameters:
+//   - token (string): The bearer token of the caller.
+//
+// Returns:
+//   - tenant (Tenant): The tenant.
+//   - ok (bool): Whether token is the token of a tenant.
+func (s *Server) Authenticate(token string) (tenant Tenant, ok bool) {
+	acc, ok := s.authenticate(token)
+	if ok {
+		tenant = acc.tenant
+	}
+
+	return
+}
+
+// Acquire admits work run on behalf of tenant outside of jobs, e.g. by another API
+// sharing the tenants of the server, as a job would be: it counts against the daily
+// quota of the tenant, then waits for a slot within the concurrency limits of the tenant
+// and of the server. The slot is held until release is called.
+//
+// Parameters:
+//   - ctx (context.Context): Stops waiting for a slot once done.
+//   - tenant (string): The name of the tenant, or "" if no tenants are configured.
+//
+// Returns:
+//   - release (func()): Releases the slot.
+//   - err (error): ErrQuotaExceeded if the tenant has exhausted its daily quota,
+//     ErrClosed if the server is closed, or the error of ctx.
+func (s *Server) Acquire(ctx context.Context, tenant string) (release func(), err error) {
+	acc, ok := s.account(tenant)
+	if !ok {
+		err = fmt.Errorf("%w: unknown tenant %q", ErrForbidden, tenant)
+
+		return
+	}
+
+	s.mu.Lock()
+
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+
+		err = ErrClosed
+
+		return
+	}
+
+	acc.today(time.Now())
+
+	if acc.tenant.Quota > 0 && acc.usage.Submitted >= acc.tenant.Quota {
+		acc.usage.Rejected++
+
+		s.mu.Unlock()
+
+		err = fmt.Errorf("%w: %d jobs per day", ErrQuotaExceeded, acc.tenant.Quota)
+
+		return
+	}
+
+	acc.usage.Submitted++
+
+	s.mu.Unlock()
+
+	if acc.slots != nil {
+		if err = s.wait(ctx, acc.slots); err != nil {
+			return
+		}
+	}
+
+	if err = s.wait(ctx, s.slots); err != nil {
+		if acc.slots != nil {
+			<-acc.slots
+		}
+
+		return
+	}
+
+	release = func() {
+		<-s.slots
+
+		if acc.slots != nil {
+			<-acc.slots
+		}
+	}
+
+	return
+}
+
+// wait takes a slot of slots, unless ctx is done or the server closed first.
+func (s *Server) wait(ctx context.Context, slots chan struct{}) (err error) {
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-s.ctx.Done():
+		err = ErrClosed
+	}
+
+	return
+}
+
+// Close cancels every job and waits for them to finish. The server accepts no jobs
+// afterwards.
+func (s *Server) Close() {
+	s.cancel()
+
+	s.wg.Wait()
+}
+
+// run waits for a slot, then runs job.
+func (s *Server) run(job *Job, options []xsubfind3r.FindOption) {
+	defer s.wg.Done()
+
+	defer job.cancel()
+
+	defer s.settle(job)
+
+	defer job.finish()
+
+	if job.account.slots != nil {
+		select {
+		case job.account.slots <- struct{}{}:
+		case <-job.ctx.Done():
+			return
+		}
+
+		defer func() {
+			<-job.account.slots
+		}()
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-job.ctx.Done():
+		return
+	}
+
+	defer func() {
+		<-s.slots
+	}()
+
+	if !job.start() {
+		return
+	}
+
+	if s.queue != nil {
+		if err := s.queue.Start(job.ID); err != nil {
+			hqgologger.Error("failed persisting job!", hqgologger.WithError(err), hqgologger.WithString("job", job.ID))
+		}
+	}
+
+	options = append(options, xsubfind3r.WithContext(job.ctx))
+
+	for result := range s.finder.Find(job.Request.Domain, options...) {
+		job.add(result)
+
+		if s.queue != nil {
+			if err := s.queue.Append(job.ID, result); err != nil {
+				hqgologger.Error("failed persisting result!", hqgologger.WithError(err), hqgologger.WithString("job", job.ID))
+			}
+		}
+	}
+}
+
+// newJob returns a queued job. The caller must hold mu.
+func (s *Server) newJob(id string, acc *account, request Request, created time.Time) (job *Job) {
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	job = &Job{
+		ID:      id,
+		Tenant:  acc.tenant.Name,
+		Request: request,
+		account: acc,
+		ctx:     ctx,
+		cancel:  cancel,
+		status:  StatusQueued,
+		created: created,
+		stats: Stats{
+			Sources: map[string]*SourceStats{},
+		},
+		changed: make(chan struct{}),
+	}
+
+	return
+}
+
+// admit registers job and starts waiting for a slot to run it. The caller must hold mu.
+func (s *Server) admit(job *Job, options []xsubfind3r.FindOption) {
+	s.jobs[job.ID] = job
+	s.order = append(s.order, job)
+
+	s.wg.Add(1)
+
+	go s.run(job, options)
+}
+
+// sweep forgets, until the server is closed, the jobs that the queue removes once their
+// retention period has passed.
+func (s *Server) sweep() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(sweepInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+
+		removed, err := s.queue.Prune()
+		if err != nil {
+			hqgologger.Error("failed removing expired jobs!", hqgologger.WithError(err))
+		}
+
+		s.mu.Lock()
+
+		for _, id := range removed {
+			delete(s.jobs, id)
+		}
+
+		s.order = slices.DeleteFunc(s.order, func(job *Job) bool {
+			return slices.Contains(removed, job.ID)
+		})
+
+		s.mu.Unlock()
+	}
+}
+
+// settle adds the finished job to the usage of its tenant and persists it as finished.
+// Jobs interrupted by the server closing are left as they are in the queue, so that they
+// are resumed.
+func (s *Server) settle(job *Job) {
+	view := job.View()
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	job.account.settle(view)
+
+	if s.queue == nil || s.ctx.Err() != nil {
+		return
+	}
+
+	state := queue.StateCompleted
+
+	if view.Status == StatusCancelled {
+		state = queue.StateCancelled
+	}
+
+	if err := s.queue.Finish(job.ID, state); err != nil {
+		hqgologger.Error("failed persisting job!", hqgologger.WithError(err), hqgologger.WithString("job", job.ID))
+	}
+}
+
+// authenticate returns the tenant authenticated by token. If the API is open, every
+// request is the anonymous administrator's, whatever its token.
+func (s *Server) authenticate(token string) (acc *account, ok bool) {
+	if s.open {
+		acc, ok = s.accounts[0], true
+
+		return
+	}
+
+	digest := sha256.Sum256([]byte(token))
+
+	// Every token is compared, so that the time taken does not tell which matched.
+	for _, candidate := range s.accounts {
+		if candidate.matches(digest) {
+			acc, ok = candidate, true
+		}
+	}
+
+	return
+}
+
+// account returns the tenant named name.
+func (s *Server) account(name string) (acc *account, ok bool) {
+	for _, candidate := range s.accounts {
+		if candidate.tenant.Name == name {
+			acc, ok = candidate, true
+
+			return
+		}
+	}
+
+	return
+}
+
+// usage returns the usage of acc, counting its queued and running jobs. The caller must
+// hold mu.
+func (s *Server) usage(acc *account) (usage Usage) {
+	acc.today(time.Now())
+
+	usage = acc.usage
+
+	usage.Stats.Sources = map[string]*SourceStats{}
+
+	for name, stats := range acc.usage.Stats.Sources {
+		usage.Stats.Sources[name] = &SourceStats{
+			Subdomains: stats.Subdomains,
+			Errors:     stats.Errors,
+		}
+	}
+
+	for _, job := range s.order {
+		if job.account != acc {
+			continue
+		}
+
+		switch job.Status() {
+		case StatusQueued:
+			usage.Queued++
+		case StatusRunning:
+			usage.Running++
+		}
+	}
+
+	return
+}
+
+// queued returns the number of jobs waiting for a slot. The caller must hold mu.
+func (s *Server) queued() (n int) {
+	for _, job := range s.order {
+		if status := job.Status(); status == StatusQueued {
+			n++
+		}
+	}
+
+	return
+}
+
+// prune forgets the oldest finished jobs beyond MaxFinishedJobs, removing them from the
+// queue too. The caller must hold mu.
+func (s *Server) prune() {
+	finished := 0
+
+	for _, job := range s.order {
+		if status := job.Status(); status.Done() {
+			finished++
+		}
+	}
+
+	kept := s.order[:0]
+
+	for _, job := range s.order {
+		if status := job.Status(); status.Done() && finished > MaxFinishedJobs {
+			finished--
+
+			delete(s.jobs, job.ID)
+
+			if s.queue != nil {
+				if err := s.queue.Remove(job.ID); err != nil {
+					hqgologger.Error("failed removing job!", hqgologger.WithError(err), hqgologger.WithString("job", job.ID))
+				}
+			}
+
+			continue
+		}
+
+		kept = append(kept, job)
+	}
+
+	clear(s.order[len(kept):])
+
+	s.order = kept
+}
+
+// payload is what the queue keeps of a job.
+//
+// Fields:
+//   - Tenant (string): The name of the tenant that submitted the job.
+//   - Request (Request): The submission.
+type payload struct {
+	Tenant  string  `json:"tenant"`
+	Request Request `json:"request"`
+}
+
+// New returns a server running jobs with finder on behalf of tenants.
+//
+// Parameters:
+//   - finder (*xsubfind3r.Finder): The finder running the jobs.
+//   - concurrency (int): The maximum number of jobs running at once.
+//   - tenants ([]Tenant): The clients of the server, or none to leave the API open.
+//
+// Returns:
+//   - s (*Server): The server.
+//   - err (error): An error wrapping ErrTenant if tenants are misconfigured.
+func New(finder *xsubfind3r.Finder, concurrency int, tenants []Tenant) (s *Server, err error) {
+	var accounts []*account
+
+	accounts, err = newAccounts(tenants)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s = &Server{
+		finder:   finder,
+		slots:    make(chan struct{}, max(concurrency, 1)),
+		open:     len(tenants) == 0,
+		accounts: accounts,
+		ctx:      ctx,
+		cancel:   cancel,
+		jobs:     map[string]*Job{},
+	}
+
+	return
+}
+
+const (
+	// MaxQueuedJobs is the number of jobs that may wait for a slot; further submissions
+	// are rejected with ErrQueueFull.
+	MaxQueuedJobs = 1024
+	// MaxFinishedJobs is the number of finished jobs kept; older ones are forgotten.
+	MaxFinishedJobs = 1000
+
+	// sweepInterval is how often jobs past their retention period are removed.
+	sweepInterval = time.Minute
+)
+
+// statuses maps the states of finished jobs in the queue to those of jobs.
+var statuses = map[queue.State]Status{
+	queue.StateCompleted: StatusCompleted,
+	queue.StateCancelled: StatusCancelled,
+	queue.StateFailed:    StatusFailed,
+}
+
+var (
+	// ErrInvalidRequest is returned for invalid job submissions.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrQueueFull is returned when MaxQueuedJobs jobs are already waiting.
+	ErrQueueFull = errors.New("job queue full")
+	// ErrClosed is returned for submissions to a closed server.
+	ErrClosed = errors.New("server closed")
+)
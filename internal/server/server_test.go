@@ -0,0 +1,499 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/internal/output"
+	"github.com/hueristiq/xsubfind3r/internal/queue"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// newFinder returns a finder reading a.example.com from a hosts file, and querying a
+// Certificate Transparency log that answers once gate is closed; a nil gate never is.
+func newFinder(t *testing.T, gate chan struct{}) (finder *xsubfind3r.Finder) {
+	t.Helper()
+
+	log := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-gate:
+		case <-r.Context().Done():
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		fmt.Fprint(w, `{"tree_size":0}`)
+	}))
+
+	t.Cleanup(log.Close)
+
+	hosts := filepath.Join(t.TempDir(), "hosts")
+
+	if err := os.WriteFile(hosts, []byte("127.0.0.1 a.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	finder, err := xsubfind3r.New(&xsubfind3r.Configuration{
+		SourcesToUSe: []string{sources.CTLOGS, sources.HOSTSFILE},
+		CTLogs: sources.CTLogsConfiguration{
+			Logs: []sources.CTLogConfiguration{{URL: log.URL + "/"}},
+		},
+		Imports: sources.ImportsConfiguration{
+			Hosts: []string{hosts},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// serve serves the API of a server of tenants, running at most concurrency jobs at once
+// with finder, until the test ends.
+func serve(t *testing.T, finder *xsubfind3r.Finder, concurrency int, tenants []Tenant) (s *Server, api *httptest.Server) {
+	t.Helper()
+
+	s, err := New(finder, concurrency, tenants)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api = httptest.NewServer(s.Handler())
+
+	t.Cleanup(func() {
+		s.Close()
+
+		api.Close()
+	})
+
+	return
+}
+
+// call makes a request to the API with token, if any, and returns the response with its
+// body read.
+func call(t *testing.T, api *httptest.Server, method, path, token, body string) (res *http.Response, data []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, api.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err = api.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	data, err = io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// submit submits a job of body with token, and returns it.
+func submit(t *testing.T, api *httptest.Server, token, body string) (view View) {
+	t.Helper()
+
+	res, data := call(t, api, http.MethodPost, "/v1/jobs", token, body)
+
+	if res.StatusCode != http.StatusAccepted {
+		t.Fatalf("got %d %s submitting %s, want 202", res.StatusCode, data, body)
+	}
+
+	if err := json.Unmarshal(data, &view); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Header.Get("Location") != "/v1/jobs/"+view.ID {
+		t.Errorf("got Location %q for job %s", res.Header.Get("Location"), view.ID)
+	}
+
+	return
+}
+
+// await polls the job id with token until it is in a state satisfying done, and returns
+// it.
+func await(t *testing.T, api *httptest.Server, token, id string, done func(status Status) bool) (view View) {
+	t.Helper()
+
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		res, data := call(t, api, http.MethodGet, "/v1/jobs/"+id, token, "")
+
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("got %d %s for job %s", res.StatusCode, data, id)
+		}
+
+		if err := json.Unmarshal(data, &view); err != nil {
+			t.Fatal(err)
+		}
+
+		if done(view.Status) {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s still %s", id, view.Status)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// is returns a condition satisfied by status.
+func is(status Status) (condition func(Status) bool) {
+	return func(s Status) bool {
+		return s == status
+	}
+}
+
+// results returns the results of the finished job id.
+func results(t *testing.T, api *httptest.Server, token, id string) (response resultsResponse) {
+	t.Helper()
+
+	res, data := call(t, api, http.MethodGet, "/v1/jobs/"+id+"/results", token, "")
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got %d %s for the results of job %s", res.StatusCode, data, id)
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// subdomains returns the subdomains of records.
+func subdomains(records []output.Record) (subdomains []string) {
+	for _, record := range records {
+		subdomains = append(subdomains, record.Subdomain)
+	}
+
+	return
+}
+
+func TestSubmitValidation(t *testing.T) {
+	_, api := serve(t, newFinder(t, nil), 1, nil)
+
+	for _, body := range []string{
+		``,
+		`{"domain":`,
+		`{"domain": "example.com", "unknown": true}`,
+		`{}`,
+		`{"domain": "192.0.2.1"}`,
+		`{"domain": "example.com", "sources": ["unknown"]}`,
+		`{"domain": "example.com", "exclude": ["unknown"]}`,
+		`{"domain": "example.com", "max_age": "soon"}`,
+		`{"domain": "example.com", "min_score": 2}`,
+		`{"domain": "example.com", "max_results": -1}`,
+		`{"domain": "example.com", "timeout": "later"}`,
+	} {
+		res, data := call(t, api, http.MethodPost, "/v1/jobs", "", body)
+
+		if res.StatusCode != http.StatusBadRequest || !strings.Contains(string(data), `"error"`) {
+			t.Errorf("%s: got %d %s, want 400", body, res.StatusCode, data)
+		}
+	}
+
+	res, data := call(t, api, http.MethodGet, "/v1/jobs", "", "")
+
+	if res.StatusCode != http.StatusOK || strings.TrimSpace(string(data)) != `{"jobs":[]}` {
+		t.Errorf("got %d %s, want no job queued by invalid submissions", res.StatusCode, data)
+	}
+
+	view := submit(t, api, "", `{"domain": "https://WWW.Example.com/path", "sources": ["hostsfile"]}`)
+
+	if view.Request.Domain != "www.example.com" {
+		t.Errorf("got domain %q, want it normalized", view.Request.Domain)
+	}
+}
+
+func TestJobLifecycle(t *testing.T) {
+	gate := make(chan struct{})
+
+	_, api := serve(t, newFinder(t, gate), 1, nil)
+
+	view := submit(t, api, "", `{"domain": "example.com"}`)
+
+	if view.Status != StatusQueued && view.Status != StatusRunning {
+		t.Errorf("got a submitted job %s", view.Status)
+	}
+
+	view = await(t, api, "", view.ID, is(StatusRunning))
+
+	if view.StartedAt == "" || view.FinishedAt != "" {
+		t.Errorf("got a running job started at %q, finished at %q", view.StartedAt, view.FinishedAt)
+	}
+
+	res, data := call(t, api, http.MethodGet, "/v1/jobs/"+view.ID+"/results", "", "")
+
+	if res.StatusCode != http.StatusConflict {
+		t.Errorf("got %d %s for the results of a running job, want 409", res.StatusCode, data)
+	}
+
+	close(gate)
+
+	view = await(t, api, "", view.ID, is(StatusCompleted))
+
+	if view.FinishedAt == "" || view.Stats.Subdomains != 1 || view.Stats.Sources[sources.HOSTSFILE].Subdomains != 1 {
+		t.Errorf("got %+v, want the subdomain of the hosts file counted", view)
+	}
+
+	response := results(t, api, "", view.ID)
+
+	if response.Job.Status != StatusCompleted || !slices.Equal(subdomains(response.Results), []string{"a.example.com"}) {
+		t.Errorf("got %+v, want a.example.com", response)
+	}
+
+	res, data = call(t, api, http.MethodGet, "/v1/jobs", "", "")
+
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(data), view.ID) {
+		t.Errorf("got %d %s, want the job listed", res.StatusCode, data)
+	}
+
+	for _, path := range []string{"/v1/jobs/unknown", "/v1/jobs/unknown/results", "/v1/jobs/unknown/stream"} {
+		if res, data = call(t, api, http.MethodGet, path, "", ""); res.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: got %d %s, want 404", path, res.StatusCode, data)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	_, api := serve(t, newFinder(t, nil), 1, nil)
+
+	running := submit(t, api, "", `{"domain": "example.com"}`)
+	queued := submit(t, api, "", `{"domain": "example.org"}`)
+
+	await(t, api, "", running.ID, is(StatusRunning))
+
+	if view := await(t, api, "", queued.ID, func(Status) bool { return true }); view.Status != StatusQueued {
+		t.Fatalf("got the second job %s, want it queued behind the first", view.Status)
+	}
+
+	for _, view := range []View{queued, running} {
+		res, data := call(t, api, http.MethodDelete, "/v1/jobs/"+view.ID, "", "")
+
+		if res.StatusCode != http.StatusAccepted {
+			t.Errorf("got %d %s cancelling, want 202", res.StatusCode, data)
+		}
+
+		view = await(t, api, "", view.ID, Status.Done)
+
+		if view.Status != StatusCancelled {
+			t.Errorf("got a cancelled job %s", view.Status)
+		}
+	}
+
+	// Cancelling a finished job changes nothing.
+	res, _ := call(t, api, http.MethodDelete, "/v1/jobs/"+running.ID, "", "")
+
+	if view := await(t, api, "", running.ID, Status.Done); res.StatusCode != http.StatusAccepted || view.Status != StatusCancelled {
+		t.Errorf("got %d, %s cancelling a cancelled job", res.StatusCode, view.Status)
+	}
+
+	if response := results(t, api, "", running.ID); response.Job.Status != StatusCancelled {
+		t.Errorf("got the results of a %s job", response.Job.Status)
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	gate := make(chan struct{})
+
+	_, api := serve(t, newFinder(t, gate), 1, nil)
+
+	view := submit(t, api, "", `{"domain": "example.com"}`)
+
+	res, err := api.Client().Get(api.URL + "/v1/jobs/" + view.ID + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("got %d %s, want an NDJSON stream", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+
+	// The subdomain of the hosts file is streamed while the job waits for the log.
+	if !scanner.Scan() {
+		t.Fatal(scanner.Err())
+	}
+
+	var record output.Record
+
+	if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Domain != "example.com" || record.Subdomain != "a.example.com" || record.Source != sources.HOSTSFILE {
+		t.Errorf("got %+v, want a.example.com", record)
+	}
+
+	close(gate)
+
+	for scanner.Scan() {
+		t.Errorf("got %s after the only result", scanner.Text())
+	}
+
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if view = await(t, api, "", view.ID, func(Status) bool { return true }); view.Status != StatusCompleted {
+		t.Errorf("stream ended while the job is %s", view.Status)
+	}
+}
+
+func TestStreamSSE(t *testing.T) {
+	gate := make(chan struct{})
+
+	close(gate)
+
+	_, api := serve(t, newFinder(t, gate), 1, nil)
+
+	view := submit(t, api, "", `{"domain": "example.com"}`)
+
+	req, err := http.NewRequest(http.MethodGet, api.URL+"/v1/jobs/"+view.ID+"/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := api.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	if res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("got %s, want an event stream", res.Header.Get("Content-Type"))
+	}
+
+	events := []string{}
+	data := map[string]string{}
+
+	scanner := bufio.NewScanner(res.Body)
+
+	for scanner.Scan() {
+		if event, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+			events = append(events, event)
+		}
+
+		if line, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			data[events[len(events)-1]] = line
+		}
+	}
+
+	if !slices.Equal(events, []string{"result", "done"}) {
+		t.Fatalf("got events %v, want a result, then done", events)
+	}
+
+	var record output.Record
+
+	if err = json.Unmarshal([]byte(data["result"]), &record); err != nil || record.Subdomain != "a.example.com" {
+		t.Errorf("got result %s, want a.example.com", data["result"])
+	}
+
+	if err = json.Unmarshal([]byte(data["done"]), &view); err != nil || view.Status != StatusCompleted {
+		t.Errorf("got done %s, want the completed job", data["done"])
+	}
+
+	// A finished job is replayed whole.
+	res, body := call(t, api, http.MethodGet, "/v1/jobs/"+view.ID+"/stream?format=sse", "", "")
+
+	if res.StatusCode != http.StatusOK || strings.Count(string(body), "event: ") != 2 {
+		t.Errorf("got %d %s replaying the job", res.StatusCode, body)
+	}
+}
+
+// TestResume checks that a server resuming from a queue restores the finished jobs with
+// their results, and runs again those interrupted by a restart.
+func TestResume(t *testing.T) {
+	directory := t.TempDir()
+
+	q, err := queue.Open(directory, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, api := serve(t, newFinder(t, nil), 1, nil)
+
+	if err = s.Resume(q); err != nil {
+		t.Fatal(err)
+	}
+
+	completed := submit(t, api, "", `{"domain": "example.com", "sources": ["hostsfile"]}`)
+
+	await(t, api, "", completed.ID, is(StatusCompleted))
+
+	interrupted := submit(t, api, "", `{"domain": "example.com"}`)
+
+	await(t, api, "", interrupted.ID, is(StatusRunning))
+
+	s.Close()
+
+	if err = q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if q, err = queue.Open(directory, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	defer q.Close()
+
+	gate := make(chan struct{})
+
+	close(gate)
+
+	s, api = serve(t, newFinder(t, gate), 1, nil)
+
+	if err = s.Resume(q); err != nil {
+		t.Fatal(err)
+	}
+
+	response := results(t, api, "", completed.ID)
+
+	if response.Job.Status != StatusCompleted || !slices.Equal(subdomains(response.Results), []string{"a.example.com"}) {
+		t.Errorf("got %+v, want the restored job with its results", response)
+	}
+
+	await(t, api, "", interrupted.ID, is(StatusCompleted))
+
+	if response = results(t, api, "", interrupted.ID); !slices.Equal(subdomains(response.Results), []string{"a.example.com"}) {
+		t.Errorf("got %+v, want the interrupted job run again", response)
+	}
+
+	res, data := call(t, api, http.MethodGet, "/v1/usage", "", "")
+
+	var usage Usage
+
+	if err = json.Unmarshal(data, &usage); err != nil || res.StatusCode != http.StatusOK || usage.Jobs != 2 || usage.Completed != 2 {
+		t.Errorf("got %d %s, want the usage of both jobs", res.StatusCode, data)
+	}
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Configuration configures server mode, as the `server` section of the configuration
+// file.
+//
+// Fields:
+//   - Tenants ([]Tenant): The clients of the server. With none, the API is open to anyone
+//     reaching it, without limits.
+type Configuration struct {
+	Tenants []Tenant `yaml:"tenants"`
+}
+
+// Tenant is a client of the server, authenticated by a bearer token.
+//
+// Fields:
+//   - Name (string): The name of the tenant, under which its jobs and usage are reported.
+//   - Token (string): The token the tenant authenticates with, as
+//     "Authorization: Bearer <token>".
+//   - Admin (bool): Whether the tenant may see and cancel the jobs of every tenant, and
+//     read the usage of every tenant.
+//   - Sources ([]string): The sources the jobs of the tenant may use, or none for every
+//     source of the server.
+//   - Concurrency (int): The maximum number of jobs of the tenant running at once, or 0 for
+//     no limit besides that of the server.
+//   - Quota (int): The maximum number of jobs the tenant may submit per day (UTC), or 0 for
+//     no limit.
+type Tenant struct {
+	Name        string   `yaml:"name"`
+	Token       string   `yaml:"token"`
+	Admin       bool     `yaml:"admin"`
+	Sources     []string `yaml:"sources"`
+	Concurrency int      `yaml:"concurrency"`
+	Quota       int      `yaml:"quota"`
+}
+
+// Usage is the usage of the server by a tenant since the server started, or, if jobs are
+// persisted, over the jobs kept in the queue.
+//
+// Fields:
+//   - Tenant (string): The name of the tenant.
+//   - Date (string): The current day (UTC), e.g. "2026-01-31".
+//   - Submitted (int): The number of jobs submitted during the day, counting the work
+//     admitted with Server.Acquire.
+//   - Rejected (int): The number of submissions rejected during the day for exceeding the
+//     quota.
+//   - Quota (int): The daily quota of the tenant, or 0 for none.
+//   - Queued, Running (int): The number of jobs of the tenant queued and running.
+//   - Jobs (int): The number of jobs submitted.
+//   - Completed (int): The number of jobs that completed.
+//   - Cancelled (int): The number of jobs that were cancelled, or failed.
+//   - Stats (Stats): The statistics of the results of the finished jobs.
+type Usage struct {
+	Tenant    string `json:"tenant"`
+	Date      string `json:"date"`
+	Submitted int    `json:"submitted"`
+	Rejected  int    `json:"rejected"`
+	Quota     int    `json:"quota,omitempty"`
+	Queued    int    `json:"queued"`
+	Running   int    `json:"running"`
+	Jobs      int    `json:"jobs"`
+	Completed int    `json:"completed"`
+	Cancelled int    `json:"cancelled"`
+	Stats     Stats  `json:"stats"`
+}
+
+// account is the state of a tenant on the server.
+//
+// Fields:
+//   - tenant (Tenant): The tenant.
+//   - digest ([sha256.Size]byte): The digest of the token of the tenant.
+//   - slots (chan struct{}): Bounds the number of running jobs of the tenant; nil if
+//     unbounded.
+//   - usage (Usage): The usage of the tenant, less the jobs queued and running.
+type account struct {
+	tenant Tenant
+	digest [sha256.Size]byte
+	slots  chan struct{}
+	usage  Usage
+}
+
+// matches reports whether token is the token of the tenant, in constant time.
+func (acc *account) matches(token [sha256.Size]byte) (ok bool) {
+	ok = subtle.ConstantTimeCompare(acc.digest[:], token[:]) == 1
+
+	return
+}
+
+// permits reports whether the jobs of the tenant may use the named source.
+func (acc *account) permits(name string) (ok bool) {
+	ok = len(acc.tenant.Sources) == 0 || slices.Contains(acc.tenant.Sources, name)
+
+	return
+}
+
+// options returns the find options of request, run on behalf of the tenant: restricted
+// to the sources of the tenant, if it is.
+func (acc *account) options(request Request) (options []xsubfind3r.FindOption, err error) {
+	options, err = request.options()
+	if err != nil {
+		return
+	}
+
+	for _, name := range request.Sources {
+		if !acc.permits(name) {
+			err = fmt.Errorf("%w: source %q not permitted", ErrForbidden, name)
+
+			return
+		}
+	}
+
+	if len(request.Sources) == 0 && len(acc.tenant.Sources) > 0 {
+		options = append(options, xsubfind3r.WithSources(acc.tenant.Sources...))
+	}
+
+	return
+}
+
+// today rolls the daily counters of the tenant over if now is on a later day than they
+// count. The caller must hold the server's mu.
+func (acc *account) today(now time.Time) {
+	date := now.UTC().Format(time.DateOnly)
+
+	if acc.usage.Date == date {
+		return
+	}
+
+	acc.usage.Date = date
+	acc.usage.Submitted = 0
+	acc.usage.Rejected = 0
+}
+
+// settle adds the finished job to the usage of the tenant. The caller must hold the
+// server's mu.
+func (acc *account) settle(view View) {
+	switch view.Status {
+	case StatusCancelled, StatusFailed:
+		acc.usage.Cancelled++
+	default:
+		acc.usage.Completed++
+	}
+
+	acc.usage.Stats.Subdomains += view.Stats.Subdomains
+	acc.usage.Stats.Results += view.Stats.Results
+	acc.usage.Stats.Errors += view.Stats.Errors
+
+	for name, stats := range view.Stats.Sources {
+		total, ok := acc.usage.Stats.Sources[name]
+		if !ok {
+			total = &SourceStats{}
+
+			acc.usage.Stats.Sources[name] = total
+		}
+
+		total.Subdomains += stats.Subdomains
+		total.Errors += stats.Errors
+	}
+}
+
+// newAccounts returns the accounts of tenants, or a single anonymous administrator
+// account if there are none.
+func newAccounts(tenants []Tenant) (accounts []*account, err error) {
+	if len(tenants) == 0 {
+		accounts = []*account{newAccount(Tenant{Admin: true})}
+
+		return
+	}
+
+	names := map[string]bool{}
+	digests := map[[sha256.Size]byte]bool{}
+
+	for _, tenant := range tenants {
+		switch {
+		case tenant.Name == "":
+			err = fmt.Errorf("%w: tenant without a name", ErrTenant)
+		case names[tenant.Name]:
+			err = fmt.Errorf("%w: duplicate tenant %q", ErrTenant, tenant.Name)
+		case tenant.Token == "":
+			err = fmt.Errorf("%w: tenant %q without a token", ErrTenant, tenant.Name)
+		case digests[sha256.Sum256([]byte(tenant.Token))]:
+			err = fmt.Errorf("%w: tenant %q shares its token with another tenant", ErrTenant, tenant.Name)
+		case tenant.Concurrency < 0, tenant.Quota < 0:
+			err = fmt.Errorf("%w: tenant %q: concurrency and quota must not be negative", ErrTenant, tenant.Name)
+		}
+
+		if err != nil {
+			return
+		}
+
+		for _, name := range tenant.Sources {
+			if !sources.Supported(name) {
+				err = fmt.Errorf("%w: tenant %q: unknown source %q", ErrTenant, tenant.Name, name)
+
+				return
+			}
+		}
+
+		acc := newAccount(tenant)
+
+		names[tenant.Name] = true
+		digests[acc.digest] = true
+
+		accounts = append(accounts, acc)
+	}
+
+	return
+}
+
+func newAccount(tenant Tenant) (acc *account) {
+	acc = &account{
+		tenant: tenant,
+		digest: sha256.Sum256([]byte(tenant.Token)),
+		usage: Usage{
+			Tenant: tenant.Name,
+			Quota:  tenant.Quota,
+			Stats: Stats{
+				Sources: map[string]*SourceStats{},
+			},
+		},
+	}
+
+	if tenant.Concurrency > 0 {
+		acc.slots = make(chan struct{}, tenant.Concurrency)
+	}
+
+	return
+}
+
+// nextDay returns the start of the day (UTC) following now.
+func nextDay(now time.Time) (next time.Time) {
+	next = now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+	return
+}
+
+var (
+	// ErrTenant is returned for invalid tenant configurations.
+	ErrTenant = errors.New("invalid tenant")
+	// ErrUnauthorized is reported for requests without a valid token.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is returned for requests the tenant is not permitted to make.
+	ErrForbidden = errors.New("forbidden")
+	// ErrQuotaExceeded is returned for submissions beyond the daily quota of the tenant.
+	ErrQuotaExceeded = errors.New("daily quota exceeded")
+)
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// tenants are the tenants of the servers under test: alice is restricted to the hosts
+// file, to one job at a time and two per day, bob is not restricted, and carol is an
+// administrator.
+var tenants = []Tenant{
+	{Name: "alice", Token: "alice-token", Sources: []string{sources.HOSTSFILE}, Concurrency: 1, Quota: 2},
+	{Name: "bob", Token: "bob-token"},
+	{Name: "carol", Token: "carol-token", Admin: true},
+}
+
+func TestUnauthorized(t *testing.T) {
+	_, api := serve(t, newFinder(t, nil), 1, tenants)
+
+	for _, token := range []string{"", "wrong", "alice"} {
+		for _, path := range []string{"/v1/sources", "/v1/jobs", "/v1/usage", "/v1/admin/usage"} {
+			res, data := call(t, api, http.MethodGet, path, token, "")
+
+			if res.StatusCode != http.StatusUnauthorized || res.Header.Get("WWW-Authenticate") == "" {
+				t.Errorf("%s with %q: got %d %s, want 401", path, token, res.StatusCode, data)
+			}
+		}
+
+		if res, data := call(t, api, http.MethodPost, "/v1/jobs", token, `{"domain": "example.com"}`); res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("submitting with %q: got %d %s, want 401", token, res.StatusCode, data)
+		}
+	}
+
+	// The token must be sent as a bearer token.
+	req, err := http.NewRequest(http.MethodGet, api.URL+"/v1/sources", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.SetBasicAuth("alice", "alice-token")
+
+	res, err := api.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got %d with basic authentication, want 401", res.StatusCode)
+	}
+
+	if res, _ := call(t, api, http.MethodGet, "/healthz", "", ""); res.StatusCode != http.StatusOK {
+		t.Errorf("got %d for the liveness probe, want it open", res.StatusCode)
+	}
+}
+
+func TestTenantIsolation(t *testing.T) {
+	_, api := serve(t, newFinder(t, nil), 4, tenants)
+
+	view := submit(t, api, "alice-token", `{"domain": "example.com"}`)
+
+	for _, request := range []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/v1/jobs/" + view.ID},
+		{http.MethodGet, "/v1/jobs/" + view.ID + "/results"},
+		{http.MethodGet, "/v1/jobs/" + view.ID + "/stream"},
+		{http.MethodDelete, "/v1/jobs/" + view.ID},
+	} {
+		if res, data := call(t, api, request.method, request.path, "bob-token", ""); res.StatusCode != http.StatusNotFound {
+			t.Errorf("%s %s of another tenant: got %d %s, want 404", request.method, request.path, res.StatusCode, data)
+		}
+	}
+
+	if res, data := call(t, api, http.MethodGet, "/v1/jobs", "bob-token", ""); strings.Contains(string(data), view.ID) {
+		t.Errorf("got %d %s, want the job of another tenant unlisted", res.StatusCode, data)
+	}
+
+	if view = await(t, api, "alice-token", view.ID, Status.Done); view.Status != StatusCompleted {
+		t.Errorf("got a job %s by another tenant, want it untouched", view.Status)
+	}
+
+	// Administrators see every job.
+	if res, data := call(t, api, http.MethodGet, "/v1/jobs/"+view.ID, "carol-token", ""); res.StatusCode != http.StatusOK {
+		t.Errorf("got %d %s for an administrator, want the job", res.StatusCode, data)
+	}
+}
+
+func TestAdminUsage(t *testing.T) {
+	_, api := serve(t, newFinder(t, nil), 1, tenants)
+
+	for _, token := range []string{"alice-token", "bob-token"} {
+		if res, data := call(t, api, http.MethodGet, "/v1/admin/usage", token, ""); res.StatusCode != http.StatusForbidden {
+			t.Errorf("%s: got %d %s, want 403", token, res.StatusCode, data)
+		}
+	}
+
+	res, data := call(t, api, http.MethodGet, "/v1/admin/usage", "carol-token", "")
+
+	var usages struct {
+		Usage []Usage `json:"usage"`
+	}
+
+	if err := json.Unmarshal(data, &usages); err != nil || res.StatusCode != http.StatusOK || len(usages.Usage) != len(tenants) {
+		t.Errorf("got %d %s, want the usage of every tenant", res.StatusCode, data)
+	}
+
+	res, data = call(t, api, http.MethodGet, "/v1/usage", "alice-token", "")
+
+	var usage Usage
+
+	if err := json.Unmarshal(data, &usage); err != nil || res.StatusCode != http.StatusOK || usage.Tenant != "alice" || usage.Quota != 2 {
+		t.Errorf("got %d %s, want the usage of alice", res.StatusCode, data)
+	}
+}
+
+func TestTenantSources(t *testing.T) {
+	_, api := serve(t, newFinder(t, nil), 1, tenants)
+
+	res, data := call(t, api, http.MethodGet, "/v1/sources", "alice-token", "")
+
+	if res.StatusCode != http.StatusOK || strings.TrimSpace(string(data)) != `{"sources":["hostsfile"]}` {
+		t.Errorf("got %d %s, want the hosts file only", res.StatusCode, data)
+	}
+
+	res, data = call(t, api, http.MethodPost, "/v1/jobs", "alice-token", `{"domain": "example.com", "sources": ["ctlogs"]}`)
+
+	if res.StatusCode != http.StatusForbidden {
+		t.Errorf("got %d %s naming a source not permitted, want 403", res.StatusCode, data)
+	}
+
+	// Without sources, the job of a restricted tenant uses those it may, so it does not
+	// wait for the log that never answers.
+	view := submit(t, api, "alice-token", `{"domain": "example.com"}`)
+
+	if view = await(t, api, "alice-token", view.ID, Status.Done); view.Status != StatusCompleted {
+		t.Fatalf("got a job %s", view.Status)
+	}
+
+	if names := slices.Sorted(func(yield func(string) bool) {
+		for name := range view.Stats.Sources {
+			if !yield(name) {
+				return
+			}
+		}
+	}); !slices.Equal(names, []string{sources.HOSTSFILE}) {
+		t.Errorf("got results of %v, want the hosts file only", names)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	s, api := serve(t, newFinder(t, nil), 1, tenants)
+
+	body := `{"domain": "example.com", "sources": ["hostsfile"]}`
+
+	submit(t, api, "alice-token", body)
+	submit(t, api, "alice-token", body)
+
+	res, data := call(t, api, http.MethodPost, "/v1/jobs", "alice-token", body)
+
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got %d %s beyond the quota, want 429", res.StatusCode, data)
+	}
+
+	retry, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil {
+		t.Fatalf("got Retry-After %q: %v", res.Header.Get("Retry-After"), err)
+	}
+
+	if want := time.Until(nextDay(time.Now())).Seconds(); math.Abs(float64(retry)-want) > 5 {
+		t.Errorf("got Retry-After %d, want %.0f, until midnight UTC", retry, want)
+	}
+
+	// Quotas are per tenant.
+	submit(t, api, "bob-token", body)
+
+	usage, _ := s.Usage("alice")
+
+	if usage.Submitted != 2 || usage.Rejected != 1 {
+		t.Errorf("got %d submitted, %d rejected, want 2 and 1", usage.Submitted, usage.Rejected)
+	}
+
+	// The day is over.
+	acc, _ := s.account("alice")
+
+	s.mu.Lock()
+
+	acc.usage.Date = time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+
+	s.mu.Unlock()
+
+	submit(t, api, "alice-token", body)
+
+	if usage, _ = s.Usage("alice"); usage.Submitted != 1 || usage.Rejected != 0 || usage.Jobs != 3 {
+		t.Errorf("got %+v, want the daily counters rolled over", usage)
+	}
+}
+
+func TestToday(t *testing.T) {
+	acc := newAccount(Tenant{Name: "alice", Quota: 1})
+
+	// Just before midnight UTC, in a zone where the day already changed.
+	before := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC).In(time.FixedZone("UTC+2", 2*60*60))
+
+	acc.today(before)
+
+	acc.usage.Submitted = 1
+	acc.usage.Rejected = 1
+
+	acc.today(before.Add(-time.Hour))
+
+	if acc.usage.Date != "2026-01-31" || acc.usage.Submitted != 1 {
+		t.Errorf("got %+v, want the counters of the same UTC day kept", acc.usage)
+	}
+
+	acc.today(before.Add(time.Second))
+
+	if acc.usage.Date != "2026-02-01" || acc.usage.Submitted != 0 || acc.usage.Rejected != 0 {
+		t.Errorf("got %+v, want the counters rolled over at midnight UTC", acc.usage)
+	}
+
+	if next := nextDay(before); !next.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got the next day at %s, want midnight UTC", next)
+	}
+}
+
+func TestTenantConcurrency(t *testing.T) {
+	_, api := serve(t, newFinder(t, nil), 4, tenants)
+
+	body := `{"domain": "example.com", "sources": ["ctlogs"]}`
+
+	first := submit(t, api, "bob-token", body)
+
+	await(t, api, "bob-token", first.ID, is(StatusRunning))
+
+	// Tenants of no concurrency of their own share the slots of the server.
+	second := submit(t, api, "bob-token", body)
+
+	await(t, api, "bob-token", second.ID, is(StatusRunning))
+
+	dave := Tenant{Name: "dave", Token: "dave-token", Concurrency: 1}
+
+	_, api = serve(t, newFinder(t, nil), 4, []Tenant{dave})
+
+	first = submit(t, api, "dave-token", body)
+
+	await(t, api, "dave-token", first.ID, is(StatusRunning))
+
+	second = submit(t, api, "dave-token", body)
+
+	time.Sleep(200 * time.Millisecond)
+
+	if view := await(t, api, "dave-token", second.ID, func(Status) bool { return true }); view.Status != StatusQueued {
+		t.Fatalf("got a job %s while the only slot of its tenant is held, want it queued", view.Status)
+	}
+
+	if res, data := call(t, api, http.MethodDelete, "/v1/jobs/"+first.ID, "dave-token", ""); res.StatusCode != http.StatusAccepted {
+		t.Fatalf("got %d %s cancelling", res.StatusCode, data)
+	}
+
+	await(t, api, "dave-token", second.ID, is(StatusRunning))
+}
@@ -0,0 +1,216 @@
+package store
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Query selects the subdomains returned by Store.Query.
+//
+// Fields:
+//   - Domain (string): The target domain whose subdomains to list.
+//   - Sources ([]string): Keep only subdomains reported by any of these sources in any
+//     run. Empty means keep all.
+//   - Since (time.Time): Keep only subdomains found by a run started at or after this
+//     time. Zero means no lower bound.
+//   - Until (time.Time): Keep only subdomains found by a run started before this time.
+//     Zero means no upper bound.
+type Query struct {
+	Domain  string
+	Sources []string
+	Since   time.Time
+	Until   time.Time
+}
+
+// Host is the recorded history of a subdomain, across every run that found it.
+//
+// Fields:
+//   - Subdomain (string): The subdomain.
+//   - Domain (string): The target domain it was found for.
+//   - FirstRun (time.Time): When the first run that found it started, i.e. when it first
+//     appeared.
+//   - LastRun (time.Time): When the latest run that found it started.
+//   - Runs (int): The number of runs that found it.
+//   - Sources ([]string): Every source that reported it, sorted.
+//   - FirstSeen (time.Time): The earliest first-seen time reported by a source, or the
+//     zero time if none was.
+//   - LastSeen (time.Time): The latest last-seen time reported by a source, or the zero
+//     time if none was.
+//   - Score (float64): The highest confidence score it was given.
+type Host struct {
+	Subdomain string
+	Domain    string
+	FirstRun  time.Time
+	LastRun   time.Time
+	Runs      int
+	Sources   []string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Score     float64
+}
+
+// Query returns the history of the subdomains selected by q, sorted by name. Filters
+// only select subdomains: the history of a selected subdomain always covers every run.
+//
+// Parameters:
+//   - q (Query): The selection.
+//
+// Returns:
+//   - hosts ([]Host): The selected subdomains.
+//   - err (error): An error if the database could not be queried.
+func (s *Store) Query(q Query) (hosts []Host, err error) {
+	conditions := []string{"d.name = ?"}
+	args := []any{q.Domain}
+
+	if len(q.Sources) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(q.Sources)), ", ")
+
+		conditions = append(conditions, `s.id IN (
+			SELECT ss.subdomain_id FROM sighting_sources ss
+			JOIN sources src ON src.id = ss.source_id
+			WHERE src.name IN (`+placeholders+`))`)
+
+		for _, source := range q.Sources {
+			args = append(args, source)
+		}
+	}
+
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		condition := `s.id IN (
+			SELECT si.subdomain_id FROM sightings si
+			JOIN runs r ON r.id = si.run_id
+			WHERE 1 = 1`
+
+		if !q.Since.IsZero() {
+			condition += " AND r.started_at >= ?"
+
+			args = append(args, formatTime(q.Since))
+		}
+
+		if !q.Until.IsZero() {
+			condition += " AND r.started_at < ?"
+
+			args = append(args, formatTime(q.Until))
+		}
+
+		conditions = append(conditions, condition+")")
+	}
+
+	var rows *sql.Rows
+
+	rows, err = s.db.Query(`
+		SELECT
+			s.name,
+			d.name,
+			MIN(r.started_at),
+			MAX(r.started_at),
+			COUNT(DISTINCT r.id),
+			(SELECT GROUP_CONCAT(name, ',') FROM (
+				SELECT DISTINCT src.name AS name FROM sighting_sources ss
+				JOIN sources src ON src.id = ss.source_id
+				WHERE ss.subdomain_id = s.id
+				ORDER BY src.name
+			)),
+			MIN(si.first_seen),
+			MAX(si.last_seen),
+			MAX(si.score)
+		FROM subdomains s
+		JOIN domains d ON d.id = s.domain_id
+		JOIN sightings si ON si.subdomain_id = s.id
+		JOIN runs r ON r.id = si.run_id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		GROUP BY s.id
+		ORDER BY s.name`,
+		args...,
+	)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			host                                   Host
+			firstRun, lastRun, firstSeen, lastSeen sql.NullString
+			reporters                              sql.NullString
+			score                                  sql.NullFloat64
+		)
+
+		if err = rows.Scan(&host.Subdomain, &host.Domain, &firstRun, &lastRun, &host.Runs, &reporters, &firstSeen, &lastSeen, &score); err != nil {
+			return
+		}
+
+		host.FirstRun = parseTime(firstRun)
+		host.LastRun = parseTime(lastRun)
+		host.FirstSeen = parseTime(firstSeen)
+		host.LastSeen = parseTime(lastSeen)
+		host.Score = score.Float64
+
+		if reporters.Valid {
+			host.Sources = strings.Split(reporters.String, ",")
+		}
+
+		hosts = append(hosts, host)
+	}
+
+	err = rows.Err()
+
+	return
+}
+
+// Latest returns the subdomains the latest completed run that enumerated domain found for
+// it, sorted. It returns none if no completed run did. Runs that recorded source errors
+// for domain, other than missing API keys, may have found it incomplete, so if the latest
+// runs did, the subdomains found by every completed run since the latest one that did
+// not are returned.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//
+// Returns:
+//   - subdomains ([]string): The subdomains.
+//   - err (error): An error if the database could not be queried.
+func (s *Store) Latest(domain string) (subdomains []string, err error) {
+	var rows *sql.Rows
+
+	rows, err = s.db.Query(`
+		SELECT DISTINCT s.name
+		FROM sightings si
+		JOIN subdomains s ON s.id = si.subdomain_id
+		JOIN domains d ON d.id = s.domain_id
+		JOIN runs r ON r.id = si.run_id
+		WHERE d.name = ? AND r.finished_at IS NOT NULL AND si.run_id >= COALESCE((
+			SELECT MAX(rd.run_id) FROM run_domains rd
+			JOIN runs cr ON cr.id = rd.run_id
+			WHERE rd.domain_id = d.id AND cr.finished_at IS NOT NULL AND NOT EXISTS (
+				SELECT 1 FROM errors e
+				WHERE e.run_id = rd.run_id AND e.domain_id = d.id AND e.kind != ?
+			)
+		), 0)
+		ORDER BY s.name`,
+		domain, sources.ErrMissingKey.Error(),
+	)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var subdomain string
+
+		if err = rows.Scan(&subdomain); err != nil {
+			return
+		}
+
+		subdomains = append(subdomains, subdomain)
+	}
+
+	err = rows.Err()
+
+	return
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// TestLatestFailed checks that runs a source failed for do not replace the subdomains
+// found by the latest run no source failed for, but add to them.
+func TestLatestFailed(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "xsubfind3r.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	record := func(results ...sources.Result) {
+		run, err := s.StartRun("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, result := range results {
+			if err = run.Add("example.com", result); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = run.Finish(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	subdomain := func(value string) sources.Result {
+		return sources.Result{Type: sources.ResultSubdomain, Source: sources.CRTSH, Value: value}
+	}
+
+	failure := func(kind error) sources.Result {
+		return sources.Result{Type: sources.ResultError, Source: sources.SHODAN, Error: sources.NewError(kind, sources.SHODAN, errors.New("failed"))}
+	}
+
+	record(subdomain("old.example.com"))
+	record(subdomain("a.example.com"), subdomain("b.example.com"), failure(sources.ErrMissingKey))
+	record(subdomain("a.example.com"), subdomain("c.example.com"), failure(sources.ErrUpstream))
+
+	latest, err := s.Latest("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"a.example.com", "b.example.com", "c.example.com"}; !slices.Equal(latest, want) {
+		t.Errorf("got %v, want %v", latest, want)
+	}
+
+	record(subdomain("a.example.com"))
+
+	if latest, _ = s.Latest("example.com"); !slices.Equal(latest, []string{"a.example.com"}) {
+		t.Errorf("got %v once a run succeeded", latest)
+	}
+}
@@ -0,0 +1,263 @@
+package store
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Run records the results of a single enumeration run. Results are written in batches,
+// so that other processes can read the database while a long run is in progress; Finish
+// writes the last batch. A Run is safe for concurrent use.
+//
+// Fields:
+//   - ID (int64): The identifier of the run in the database.
+//   - store (*Store): The store the run is recorded in.
+//   - mu (sync.Mutex): Guards the fields below.
+//   - tx (*sql.Tx): The transaction of the current batch, or nil.
+//   - pending (int): The number of results in the current batch.
+//   - domains (map[string]int64): The identifiers of the domains recorded so far.
+//   - sources (map[string]int64): The identifiers of the sources recorded so far.
+type Run struct {
+	ID int64
+
+	store   *Store
+	mu      sync.Mutex
+	tx      *sql.Tx
+	pending int
+	domains map[string]int64
+	sources map[string]int64
+}
+
+// Add records a result found for domain. Subdomains are recorded with the sources that
+// reported them, their first-seen and last-seen times and their score; errors with their
+// kind and message. Reports, sent with xsubfind3r.WithReports, add their source and times
+// to the subdomain they report, so that sources reporting a subdomain after it was
+// emitted are recorded too. Results of other types are ignored.
+//
+// Parameters:
+//   - domain (string): The target domain the result was found for.
+//   - result (sources.Result): The result.
+//
+// Returns:
+//   - err (error): An error if the result could not be recorded.
+func (r *Run) Add(domain string, result sources.Result) (err error) {
+	if result.Type != sources.ResultSubdomain && result.Type != sources.ResultReport && result.Type != sources.ResultError {
+		return
+	}
+
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	if r.tx == nil {
+		r.tx, err = r.store.db.Begin()
+		if err != nil {
+			return
+		}
+	}
+
+	var domainID, sourceID int64
+
+	domainID, err = r.domain(domain)
+	if err != nil {
+		return
+	}
+
+	sourceID, err = r.source(result.Source)
+	if err != nil {
+		return
+	}
+
+	switch result.Type {
+	case sources.ResultSubdomain, sources.ResultReport:
+		err = r.addSubdomain(domainID, result)
+	case sources.ResultError:
+		_, err = r.tx.Exec(
+			"INSERT INTO errors (run_id, domain_id, source_id, kind, message, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+			r.ID, domainID, sourceID, sources.KindOf(result.Error).Error(), result.Error.Error(), formatTime(time.Now()),
+		)
+	}
+
+	if err != nil {
+		return
+	}
+
+	r.pending++
+
+	if r.pending >= batchSize {
+		err = r.commit()
+	}
+
+	return
+}
+
+// Finish writes the last batch and records the end of the run.
+//
+// Returns:
+//   - err (error): An error if the run could not be recorded.
+func (r *Run) Finish() (err error) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	if err = r.commit(); err != nil {
+		return
+	}
+
+	_, err = r.store.db.Exec("UPDATE runs SET finished_at = ? WHERE id = ?", formatTime(time.Now()), r.ID)
+
+	return
+}
+
+// addSubdomain records a subdomain or report result of the run.
+func (r *Run) addSubdomain(domainID int64, result sources.Result) (err error) {
+	var subdomainID int64
+
+	err = r.tx.QueryRow(
+		"INSERT INTO subdomains (domain_id, name) VALUES (?, ?) ON CONFLICT (domain_id, name) DO UPDATE SET name = excluded.name RETURNING id",
+		domainID, result.Value,
+	).Scan(&subdomainID)
+	if err != nil {
+		return
+	}
+
+	var score any
+
+	if result.Score > 0 {
+		score = result.Score
+	}
+
+	_, err = r.tx.Exec(
+		`INSERT INTO sightings (run_id, subdomain_id, first_seen, last_seen, score) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (run_id, subdomain_id) DO UPDATE SET
+			first_seen = COALESCE(MIN(first_seen, excluded.first_seen), first_seen, excluded.first_seen),
+			last_seen = COALESCE(MAX(last_seen, excluded.last_seen), last_seen, excluded.last_seen),
+			score = COALESCE(MAX(score, excluded.score), score, excluded.score)`,
+		r.ID, subdomainID, formatTime(result.FirstSeen), formatTime(result.LastSeen), score,
+	)
+	if err != nil {
+		return
+	}
+
+	reporters := result.Sources
+
+	if len(reporters) == 0 {
+		reporters = []string{result.Source}
+	}
+
+	for _, name := range reporters {
+		var sourceID int64
+
+		sourceID, err = r.source(name)
+		if err != nil {
+			return
+		}
+
+		_, err = r.tx.Exec(
+			"INSERT OR IGNORE INTO sighting_sources (run_id, subdomain_id, source_id) VALUES (?, ?, ?)",
+			r.ID, subdomainID, sourceID,
+		)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// domain returns the identifier of the named domain, recording it and its participation
+// in the run if needed.
+func (r *Run) domain(name string) (id int64, err error) {
+	id, ok := r.domains[name]
+	if ok {
+		return
+	}
+
+	err = r.tx.QueryRow(
+		"INSERT INTO domains (name) VALUES (?) ON CONFLICT (name) DO UPDATE SET name = excluded.name RETURNING id",
+		name,
+	).Scan(&id)
+	if err != nil {
+		return
+	}
+
+	if _, err = r.tx.Exec("INSERT OR IGNORE INTO run_domains (run_id, domain_id) VALUES (?, ?)", r.ID, id); err != nil {
+		return
+	}
+
+	r.domains[name] = id
+
+	return
+}
+
+// source returns the identifier of the named source, recording it if needed.
+func (r *Run) source(name string) (id int64, err error) {
+	id, ok := r.sources[name]
+	if ok {
+		return
+	}
+
+	err = r.tx.QueryRow(
+		"INSERT INTO sources (name) VALUES (?) ON CONFLICT (name) DO UPDATE SET name = excluded.name RETURNING id",
+		name,
+	).Scan(&id)
+	if err != nil {
+		return
+	}
+
+	r.sources[name] = id
+
+	return
+}
+
+// commit commits the current batch, if any.
+func (r *Run) commit() (err error) {
+	if r.tx == nil {
+		return
+	}
+
+	err = r.tx.Commit()
+
+	r.tx = nil
+	r.pending = 0
+
+	return
+}
+
+// StartRun records the start of a run.
+//
+// Parameters:
+//   - version (string): The version of the program performing the run.
+//
+// Returns:
+//   - r (*Run): The run, to record results in.
+//   - err (error): An error if the run could not be recorded.
+func (s *Store) StartRun(version string) (r *Run, err error) {
+	var res sql.Result
+
+	res, err = s.db.Exec("INSERT INTO runs (version, started_at) VALUES (?, ?)", version, formatTime(time.Now()))
+	if err != nil {
+		return
+	}
+
+	r = &Run{
+		store:   s,
+		domains: map[string]int64{},
+		sources: map[string]int64{},
+	}
+
+	r.ID, err = res.LastInsertId()
+	if err != nil {
+		r = nil
+
+		return
+	}
+
+	return
+}
+
+// batchSize is the number of results written per transaction.
+const batchSize = 256
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// TestRunAddReport checks that a source reporting a subdomain after it was emitted is
+// recorded, and selects the subdomain when querying by source.
+func TestRunAddReport(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "xsubfind3r.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	run, err := s.StartRun("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, result := range []sources.Result{
+		{Type: sources.ResultSubdomain, Source: sources.CRTSH, Value: "a.example.com", Score: 0.5, Sources: []string{sources.CRTSH}},
+		{Type: sources.ResultReport, Source: sources.ANUBIS, Value: "a.example.com", LastSeen: seen},
+	} {
+		if err = run.Add("example.com", result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = run.Finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	hosts, err := s.Query(Query{Domain: "example.com", Sources: []string{sources.ANUBIS}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hosts) != 1 {
+		t.Fatalf("got %+v, want a.example.com", hosts)
+	}
+
+	if !slices.Equal(hosts[0].Sources, []string{sources.ANUBIS, sources.CRTSH}) || !hosts[0].LastSeen.Equal(seen) || hosts[0].Score != 0.5 {
+		t.Errorf("got %+v", hosts[0])
+	}
+}
@@ -0,0 +1,210 @@
+// Package store persists enumeration results in a SQLite database, so that the history
+// of every run can be queried later: which subdomains a domain has, which sources found
+// them, and when each of them first appeared.
+//
+// The database is normalized: runs, domains, sources and subdomains each have their own
+// table, sightings record which subdomains a run found and which sources reported them,
+// and errors record the failures of sources during a run.
+//
+// The SQLite driver requires cgo; binaries built with CGO_ENABLED=0 fail to open a store.
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Registers the "sqlite3" database/sql driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Store is a results database. It is safe for concurrent use.
+//
+// Fields:
+//   - db (*sql.DB): The database handle.
+type Store struct {
+	db *sql.DB
+}
+
+// Close closes the database.
+func (s *Store) Close() (err error) {
+	err = s.db.Close()
+
+	return
+}
+
+// migrate brings the schema of the database up to schemaVersion.
+func (s *Store) migrate() (err error) {
+	var version int
+
+	if err = s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return
+	}
+
+	if version > schemaVersion {
+		err = fmt.Errorf("%w: schema version %d, supported up to %d", ErrUnsupportedSchema, version, schemaVersion)
+
+		return
+	}
+
+	if version == schemaVersion {
+		return
+	}
+
+	var tx *sql.Tx
+
+	tx, err = s.db.Begin()
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec(schema); err != nil {
+		return
+	}
+
+	if _, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+
+	return
+}
+
+// Open opens the results database at path, creating it and its directory if needed.
+//
+// Parameters:
+//   - path (string): The path of the SQLite database file.
+//
+// Returns:
+//   - s (*Store): The store.
+//   - err (error): An error if the database could not be opened or migrated.
+func Open(path string) (s *Store, err error) {
+	if directory := filepath.Dir(path); directory != "" {
+		if err = os.MkdirAll(directory, 0o750); err != nil {
+			return
+		}
+	}
+
+	var db *sql.DB
+
+	db, err = sql.Open("sqlite3", "file:"+path+"?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return
+	}
+
+	s = &Store{
+		db: db,
+	}
+
+	if err = s.migrate(); err != nil {
+		db.Close()
+
+		s = nil
+
+		return
+	}
+
+	return
+}
+
+// formatTime formats t for storage, or returns nil for the zero time.
+func formatTime(t time.Time) (value any) {
+	if t.IsZero() {
+		return
+	}
+
+	value = t.UTC().Format(time.RFC3339)
+
+	return
+}
+
+// parseTime parses a time stored by formatTime, returning the zero time for NULL.
+func parseTime(value sql.NullString) (t time.Time) {
+	if !value.Valid {
+		return
+	}
+
+	t, _ = time.Parse(time.RFC3339, value.String)
+
+	return
+}
+
+// schemaVersion is the version of schema, recorded in the database's user_version.
+const schemaVersion = 1
+
+// schema creates the tables of the database.
+const schema = `
+CREATE TABLE runs (
+	id          INTEGER PRIMARY KEY,
+	version     TEXT NOT NULL,
+	started_at  TEXT NOT NULL,
+	finished_at TEXT
+);
+
+CREATE TABLE domains (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE sources (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE subdomains (
+	id        INTEGER PRIMARY KEY,
+	domain_id INTEGER NOT NULL REFERENCES domains (id),
+	name      TEXT NOT NULL,
+	UNIQUE (domain_id, name)
+);
+
+CREATE TABLE run_domains (
+	run_id    INTEGER NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+	domain_id INTEGER NOT NULL REFERENCES domains (id),
+	PRIMARY KEY (run_id, domain_id)
+);
+
+CREATE TABLE sightings (
+	run_id       INTEGER NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+	subdomain_id INTEGER NOT NULL REFERENCES subdomains (id),
+	first_seen   TEXT,
+	last_seen    TEXT,
+	score        REAL,
+	PRIMARY KEY (run_id, subdomain_id)
+);
+
+CREATE TABLE sighting_sources (
+	run_id       INTEGER NOT NULL,
+	subdomain_id INTEGER NOT NULL,
+	source_id    INTEGER NOT NULL REFERENCES sources (id),
+	PRIMARY KEY (run_id, subdomain_id, source_id),
+	FOREIGN KEY (run_id, subdomain_id) REFERENCES sightings (run_id, subdomain_id) ON DELETE CASCADE
+);
+
+CREATE TABLE errors (
+	id         INTEGER PRIMARY KEY,
+	run_id     INTEGER NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+	domain_id  INTEGER NOT NULL REFERENCES domains (id),
+	source_id  INTEGER NOT NULL REFERENCES sources (id),
+	kind       TEXT NOT NULL,
+	message    TEXT NOT NULL,
+	created_at TEXT NOT NULL
+);
+
+CREATE INDEX sighting_sources_source ON sighting_sources (source_id);
+CREATE INDEX sightings_subdomain ON sightings (subdomain_id);
+CREATE INDEX errors_run ON errors (run_id);
+`
+
+// ErrUnsupportedSchema is returned by Open for databases created by a newer version.
+var ErrUnsupportedSchema = errors.New("unsupported database schema")
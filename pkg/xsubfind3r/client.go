@@ -0,0 +1,148 @@
+package xsubfind3r
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	hqgohttp "github.com/hueristiq/hq-go-http"
+	hqgohttpheader "github.com/hueristiq/hq-go-http/header"
+)
+
+// client builds the HTTP clients of a Finder's calls. Each call gets its own client,
+// bound to the context of the call, so that stopping the call aborts its requests in
+// flight, not only those it has yet to send. The clients of a Finder share one
+// transport.
+//
+// Fields:
+//   - configuration (hqgohttp.ClientConfiguration): The configuration every client is
+//     built from; it is copied, never modified.
+//   - transport (http.RoundTripper): The transport shared by every client.
+type client struct {
+	configuration hqgohttp.ClientConfiguration
+	transport     http.RoundTripper
+}
+
+// bind returns a client whose requests are aborted, and not retried, once ctx is done.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - bound (*hqgohttp.Client): The client.
+//   - err (error): An error if the client could not be built.
+func (c *client) bind(ctx context.Context) (bound *hqgohttp.Client, err error) {
+	cc := c.configuration
+
+	cc.Headers = append([]hqgohttp.Header{}, c.configuration.Headers...)
+
+	cc.Client = &http.Client{
+		Transport: &boundTransport{
+			base: c.transport,
+			ctx:  ctx,
+		},
+	}
+
+	policy := cc.RetryPolicy
+
+	if policy == nil {
+		policy = hqgohttp.DefaultRetryPolicy()
+	}
+
+	cc.RetryPolicy = func(requestCTX context.Context, err error) (retry bool, errr error) {
+		if errr = ctx.Err(); errr != nil {
+			return
+		}
+
+		return policy(requestCTX, err)
+	}
+
+	bound, err = hqgohttp.NewClient(&cc)
+
+	return
+}
+
+// newClient returns the client of a Finder created with cfg.
+//
+// Parameters:
+//   - cfg (*Configuration): The configuration of the Finder.
+//
+// Returns:
+//   - c (*client): The client.
+func newClient(cfg *Configuration) (c *client) {
+	c = &client{
+		configuration: *hqgohttp.DefaultSprayingClientConfiguration,
+		transport:     hqgohttp.DefaultHTTPTransport(),
+	}
+
+	c.configuration.Headers = []hqgohttp.Header{}
+	c.configuration.Timeout = 1 * time.Hour
+
+	if cfg.Client != nil && cfg.Client.UserAgent != "" {
+		c.configuration.Headers = append(c.configuration.Headers, hqgohttp.NewSetHeader(hqgohttpheader.UserAgent.String(), cfg.Client.UserAgent))
+	}
+
+	return
+}
+
+// boundTransport sends requests with base, aborting them once ctx is done.
+//
+// Fields:
+//   - base (http.RoundTripper): The transport requests are sent with.
+//   - ctx (context.Context): The context of the call the requests belong to.
+type boundTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+}
+
+// RoundTrip sends req, cancelled once either its own context or the call's is done. The
+// request stays cancellable until its response body is closed.
+func (t *boundTransport) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	stop := context.AfterFunc(t.ctx, cancel)
+
+	release := func() {
+		stop()
+
+		cancel()
+	}
+
+	res, err = t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+
+		return
+	}
+
+	res.Body = &boundBody{ReadCloser: res.Body, release: release}
+
+	return
+}
+
+// CloseIdleConnections closes the idle connections of the base transport.
+func (t *boundTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// boundBody is the body of a response sent by a boundTransport. Closing it releases the
+// request's context.
+type boundBody struct {
+	io.ReadCloser
+
+	once    sync.Once
+	release func()
+}
+
+// Close closes the body and releases the request's context.
+func (b *boundBody) Close() (err error) {
+	err = b.ReadCloser.Close()
+
+	b.once.Do(b.release)
+
+	return
+}
@@ -0,0 +1,207 @@
+package xsubfind3r
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// All returns an iterator over the results of enumerating domain. Each subdomain is
+// yielded with a nil error; each source error is yielded as its result together with
+// the error it carries.
+//
+// Unlike with Find, the caller may stop at any time: breaking out of the loop stops the
+// call, so sources no longer block on sending results, their requests in flight are
+// aborted, and their goroutines exit.
+//
+//	for result, err := range finder.All("example.com") {
+//		if err != nil {
+//			continue
+//		}
+//
+//		fmt.Println(result.Value)
+//	}
+//
+// Parameters:
+//   - domain (string): The target domain for subdomain discovery.
+//   - options (...FindOption): Per-call overrides.
+//
+// Returns:
+//   - seq (iter.Seq2[sources.Result, error]): The iterator over the results.
+func (finder *Finder) All(domain string, options ...FindOption) (seq iter.Seq2[sources.Result, error]) {
+	return func(yield func(sources.Result, error) bool) {
+		r := newRun(domain, finder.configuration, finder.client, finder.scorer, options)
+
+		defer r.stop()
+
+		for result := range finder.find(r) {
+			if !yield(result, result.Error) {
+				return
+			}
+		}
+	}
+}
+
+// Collect enumerates domain to completion and returns the sorted, deduplicated
+// subdomains together with statistics about the call, the first-seen and last-seen
+// times reported for them, merged across sources, and their final confidence scores. Results of other types, such as
+// IP addresses, are not collected; use All or Find to receive them.
+//
+// Errors reported by sources do not abort the call: they are counted in the statistics
+// and joined into err, so errors.Is and errors.As can still inspect each of them. The
+// report is complete even when err is non-nil.
+//
+// Parameters:
+//   - domain (string): The target domain for subdomain discovery.
+//   - options (...FindOption): Per-call overrides.
+//
+// Returns:
+//   - report (*Report): The subdomains found and the statistics of the call.
+//   - err (error): The errors reported by sources, joined with errors.Join, or nil.
+func (finder *Finder) Collect(domain string, options ...FindOption) (report *Report, err error) {
+	report = &Report{
+		Domain:     domain,
+		Subdomains: []string{},
+		Sightings:  map[string]Sighting{},
+		Scores:     map[string]float64{},
+		Sources:    map[string][]string{},
+		Stats: Stats{
+			Sources: map[string]*SourceStats{},
+		},
+	}
+
+	errs := []error{}
+
+	started := time.Now()
+
+	r := newRun(domain, finder.configuration, finder.client, finder.scorer, options)
+
+	for result := range finder.find(r) {
+		stats, ok := report.Stats.Sources[result.Source]
+		if !ok {
+			stats = &SourceStats{}
+
+			report.Stats.Sources[result.Source] = stats
+		}
+
+		if result.Type == sources.ResultError {
+			stats.Errors++
+
+			report.Stats.Errors++
+
+			errs = append(errs, result.Error)
+
+			continue
+		}
+
+		if result.Type != sources.ResultSubdomain {
+			continue
+		}
+
+		stats.Subdomains++
+
+		report.Subdomains = append(report.Subdomains, result.Value)
+	}
+
+	report.Stats.Duration = time.Since(started)
+
+	slices.Sort(report.Subdomains)
+
+	report.Subdomains = slices.Compact(report.Subdomains)
+
+	report.Stats.Subdomains = len(report.Subdomains)
+
+	now := time.Now()
+
+	for _, subdomain := range report.Subdomains {
+		value, ok := r.seen.Load(subdomain)
+		if !ok {
+			continue
+		}
+
+		s := value.(*sighting)
+
+		s.mu.Lock()
+
+		if !s.first.IsZero() || !s.last.IsZero() {
+			report.Sightings[subdomain] = Sighting{
+				FirstSeen: s.first,
+				LastSeen:  s.last,
+			}
+		}
+
+		report.Scores[subdomain] = r.scorer.score(s.reporters, s.resolved, s.last, now)
+		report.Sources[subdomain] = slices.Sorted(maps.Keys(s.reporters))
+
+		s.mu.Unlock()
+	}
+
+	err = errors.Join(errs...)
+
+	return
+}
+
+// Report is the outcome of a Collect call.
+//
+// Fields:
+//   - Domain (string): The target domain.
+//   - Subdomains ([]string): The unique subdomains found, sorted.
+//   - Sightings (map[string]Sighting): The first-seen and last-seen times of the subdomains,
+//     merged across every source that reported them. Subdomains no source dated are absent.
+//   - Scores (map[string]float64): The confidence scores of the subdomains, computed from
+//     the evidence of every source once the call has finished. They may be higher than
+//     the scores carried by the results emitted during the call, which are snapshots
+//     taken when each subdomain was emitted.
+//   - Sources (map[string][]string): The names of every source that reported each
+//     subdomain during the call, sorted. Like Scores, they may be more than those carried
+//     by the emitted results.
+//   - Stats (Stats): Statistics about the call.
+type Report struct {
+	Domain     string
+	Subdomains []string
+	Sightings  map[string]Sighting
+	Scores     map[string]float64
+	Sources    map[string][]string
+	Stats      Stats
+}
+
+// Sighting holds when a subdomain was seen.
+//
+// Fields:
+//   - FirstSeen (time.Time): The earliest time any source saw the subdomain, or the zero
+//     time if unknown.
+//   - LastSeen (time.Time): The latest time any source saw the subdomain, or the zero time
+//     if unknown.
+type Sighting struct {
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Stats summarizes a Collect call.
+//
+// Fields:
+//   - Subdomains (int): The number of unique subdomains found.
+//   - Errors (int): The number of errors reported by sources.
+//   - Duration (time.Duration): How long the call took.
+//   - Sources (map[string]*SourceStats): Per-source statistics, keyed by source name.
+type Stats struct {
+	Subdomains int
+	Errors     int
+	Duration   time.Duration
+	Sources    map[string]*SourceStats
+}
+
+// SourceStats summarizes the results of a single source in a Collect call.
+//
+// Fields:
+//   - Subdomains (int): The number of subdomains credited to the source. Subdomains are
+//     deduplicated across sources, so each one is credited to the first source that found it.
+//   - Errors (int): The number of errors the source reported.
+type SourceStats struct {
+	Subdomains int
+	Errors     int
+}
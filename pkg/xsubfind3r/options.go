@@ -0,0 +1,264 @@
+package xsubfind3r
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FindOption customizes a single Find, FindMany or FindFrom call. Options compose with,
+// and never modify, the defaults the Finder was created with.
+type FindOption func(options *findOptions)
+
+// findOptions holds the per-call overrides collected from FindOption values.
+//
+// Fields:
+//   - sources ([]string): Restrict the call to these of the Finder's sources. Empty means all.
+//   - exclude ([]string): Skip these of the Finder's sources.
+//   - deadline (time.Time): Stop the call at this time. Zero means no deadline.
+//   - timeout (time.Duration): Stop the call this long after it starts. Zero means no timeout.
+//   - maxResults (int): Stop the call after this many subdomains. Zero means no limit.
+//   - outOfScope ([]string): Drop subdomains equal to or under any of these names.
+//   - seenSince (time.Time): Drop results last seen before this time. Zero means keep all.
+//   - maxAge (time.Duration): Drop results last seen longer than this before the call
+//     started. Zero means keep all.
+//   - minScore (float64): Drop subdomains scoring below this. Zero means keep all.
+//   - ctx (context.Context): Stop the call once this context is done. Nil means never.
+//   - ends (bool): FindMany and FindFrom mark the end of the results of each domain.
+//   - reports (bool): Send a report whenever a further source reports an emitted subdomain.
+type findOptions struct {
+	sources    []string
+	exclude    []string
+	deadline   time.Time
+	timeout    time.Duration
+	maxResults int
+	outOfScope []string
+	seenSince  time.Time
+	maxAge     time.Duration
+	minScore   float64
+	ctx        context.Context
+	ends       bool
+	reports    bool
+}
+
+// uses reports whether the named source takes part in the call.
+func (options *findOptions) uses(name string) bool {
+	if len(options.sources) > 0 && !slices.Contains(options.sources, name) {
+		return false
+	}
+
+	return !slices.Contains(options.exclude, name)
+}
+
+// stopAt returns the time at which a call starting at start must stop, or the zero time
+// if it has neither a deadline nor a timeout.
+func (options *findOptions) stopAt(start time.Time) (at time.Time) {
+	at = options.deadline
+
+	if options.timeout > 0 {
+		if byTimeout := start.Add(options.timeout); at.IsZero() || byTimeout.Before(at) {
+			at = byTimeout
+		}
+	}
+
+	return
+}
+
+// cutoff returns the time before which results last seen are dropped, for a call starting
+// at start, or the zero time if none are.
+func (options *findOptions) cutoff(start time.Time) (at time.Time) {
+	at = options.seenSince
+
+	if options.maxAge > 0 {
+		if byAge := start.Add(-options.maxAge); byAge.After(at) {
+			at = byAge
+		}
+	}
+
+	return
+}
+
+// inScope reports whether subdomain is outside every out-of-scope name.
+func (options *findOptions) inScope(subdomain string) bool {
+	for _, name := range options.outOfScope {
+		if subdomain == name || strings.HasSuffix(subdomain, "."+name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WithSources restricts the call to the named sources. Names of sources the Finder was
+// not created with are ignored, so the call can never use more sources than the Finder.
+//
+// Parameters:
+//   - names (...string): The sources to use.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithSources(names ...string) (option FindOption) {
+	return func(options *findOptions) {
+		options.sources = append(options.sources, names...)
+	}
+}
+
+// WithoutSources excludes the named sources from the call.
+//
+// Parameters:
+//   - names (...string): The sources to exclude.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithoutSources(names ...string) (option FindOption) {
+	return func(options *findOptions) {
+		options.exclude = append(options.exclude, names...)
+	}
+}
+
+// WithDeadline stops the call at deadline: no results are emitted afterwards and the
+// results channel is closed. When given more than once, the earliest deadline applies.
+//
+// Parameters:
+//   - deadline (time.Time): The time at which to stop.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithDeadline(deadline time.Time) (option FindOption) {
+	return func(options *findOptions) {
+		if options.deadline.IsZero() || deadline.Before(options.deadline) {
+			options.deadline = deadline
+		}
+	}
+}
+
+// WithTimeout stops the call once timeout has elapsed since it started. For FindMany and
+// FindFrom, the timeout applies to each domain separately. When combined with
+// WithDeadline, whichever is reached first applies.
+//
+// Parameters:
+//   - timeout (time.Duration): The time after which to stop.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithTimeout(timeout time.Duration) (option FindOption) {
+	return func(options *findOptions) {
+		options.timeout = timeout
+	}
+}
+
+// WithMaxResults stops the call once n unique subdomains have been emitted.
+//
+// Parameters:
+//   - n (int): The maximum number of subdomains.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithMaxResults(n int) (option FindOption) {
+	return func(options *findOptions) {
+		options.maxResults = n
+	}
+}
+
+// WithOutOfScope drops subdomains equal to or under any of the given names, e.g. to keep
+// a third-party hosted "shop.example.com" out of the results for "example.com".
+//
+// Parameters:
+//   - names (...string): The out-of-scope names.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithOutOfScope(names ...string) (option FindOption) {
+	return func(options *findOptions) {
+		for _, name := range names {
+			options.outOfScope = append(options.outOfScope, strings.TrimSuffix(strings.ToLower(name), "."))
+		}
+	}
+}
+
+// WithSeenSince drops results whose sources last saw them before t, e.g. names only
+// found in certificates that expired years ago. Last-seen times are merged across
+// sources, so a name is kept if any source saw it since t. Results without a known
+// last-seen time are always kept, and so are subdomains any source reports without one,
+// whatever the other sources report.
+//
+// Parameters:
+//   - t (time.Time): The earliest last-seen time to keep.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithSeenSince(t time.Time) (option FindOption) {
+	return func(options *findOptions) {
+		if t.After(options.seenSince) {
+			options.seenSince = t
+		}
+	}
+}
+
+// WithMaxAge drops results last seen longer than age before the call started. It is the
+// relative form of WithSeenSince; when both are given, the later cutoff applies.
+//
+// Parameters:
+//   - age (time.Duration): The maximum time since a result was last seen.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithMaxAge(age time.Duration) (option FindOption) {
+	return func(options *findOptions) {
+		options.maxAge = age
+	}
+}
+
+// WithMinScore drops subdomains whose confidence score is below score; see
+// ScoringConfiguration. A subdomain is held back until enough sources corroborate it, so
+// it may be emitted later in the call than without the option, or not at all.
+//
+// Parameters:
+//   - score (float64): The minimum score, between 0 and 1.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithMinScore(score float64) (option FindOption) {
+	return func(options *findOptions) {
+		options.minScore = score
+	}
+}
+
+// WithContext stops the call once ctx is done, like WithDeadline does at its deadline.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithContext(ctx context.Context) (option FindOption) {
+	return func(options *findOptions) {
+		options.ctx = ctx
+	}
+}
+
+// WithDomainEnds makes FindMany and FindFrom send, once every result of a domain has been
+// sent, a DomainResult for the domain with End set, e.g. so that output buffered per
+// domain can be written out without waiting for the other domains. Find ignores it.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithDomainEnds() (option FindOption) {
+	return func(options *findOptions) {
+		options.ends = true
+	}
+}
+
+// WithReports makes the call send a sources.ResultReport result whenever a source reports
+// a subdomain already emitted for the first time, e.g. so that a record of which sources
+// found each subdomain is complete: the Sources of an emitted subdomain only list those
+// known when it was emitted.
+//
+// Returns:
+//   - option (FindOption): The option.
+func WithReports() (option FindOption) {
+	return func(options *findOptions) {
+		options.reports = true
+	}
+}
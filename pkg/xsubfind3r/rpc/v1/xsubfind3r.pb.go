@@ -0,0 +1,1549 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: xsubfind3r/v1/xsubfind3r.proto
+
+package xsubfind3rv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ResultType int32
+
+const (
+	ResultType_RESULT_TYPE_UNSPECIFIED ResultType = 0
+	ResultType_RESULT_TYPE_SUBDOMAIN   ResultType = 1
+	ResultType_RESULT_TYPE_ERROR       ResultType = 2
+	ResultType_RESULT_TYPE_IP          ResultType = 3
+	ResultType_RESULT_TYPE_URL         ResultType = 4
+	ResultType_RESULT_TYPE_RECORD      ResultType = 5
+	ResultType_RESULT_TYPE_ASN         ResultType = 6
+)
+
+// Enum value maps for ResultType.
+var (
+	ResultType_name = map[int32]string{
+		0: "RESULT_TYPE_UNSPECIFIED",
+		1: "RESULT_TYPE_SUBDOMAIN",
+		2: "RESULT_TYPE_ERROR",
+		3: "RESULT_TYPE_IP",
+		4: "RESULT_TYPE_URL",
+		5: "RESULT_TYPE_RECORD",
+		6: "RESULT_TYPE_ASN",
+	}
+	ResultType_value = map[string]int32{
+		"RESULT_TYPE_UNSPECIFIED": 0,
+		"RESULT_TYPE_SUBDOMAIN":   1,
+		"RESULT_TYPE_ERROR":       2,
+		"RESULT_TYPE_IP":          3,
+		"RESULT_TYPE_URL":         4,
+		"RESULT_TYPE_RECORD":      5,
+		"RESULT_TYPE_ASN":         6,
+	}
+)
+
+func (x ResultType) Enum() *ResultType {
+	p := new(ResultType)
+	*p = x
+	return p
+}
+
+func (x ResultType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ResultType) Descriptor() protoreflect.EnumDescriptor {
+	return file_xsubfind3r_v1_xsubfind3r_proto_enumTypes[0].Descriptor()
+}
+
+func (ResultType) Type() protoreflect.EnumType {
+	return &file_xsubfind3r_v1_xsubfind3r_proto_enumTypes[0]
+}
+
+func (x ResultType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResultType.Descriptor instead.
+func (ResultType) EnumDescriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{0}
+}
+
+type ErrorKind int32
+
+const (
+	ErrorKind_ERROR_KIND_UNSPECIFIED      ErrorKind = 0
+	ErrorKind_ERROR_KIND_MISSING_KEY      ErrorKind = 1
+	ErrorKind_ERROR_KIND_AUTH             ErrorKind = 2
+	ErrorKind_ERROR_KIND_QUOTA_EXHAUSTED  ErrorKind = 3
+	ErrorKind_ERROR_KIND_RATE_LIMITED     ErrorKind = 4
+	ErrorKind_ERROR_KIND_UPSTREAM         ErrorKind = 5
+	ErrorKind_ERROR_KIND_TIMEOUT          ErrorKind = 6
+	ErrorKind_ERROR_KIND_PARSE            ErrorKind = 7
+	ErrorKind_ERROR_KIND_INVALID_RESPONSE ErrorKind = 8
+	ErrorKind_ERROR_KIND_REQUEST          ErrorKind = 9
+	ErrorKind_ERROR_KIND_UNCLASSIFIED     ErrorKind = 10
+)
+
+// Enum value maps for ErrorKind.
+var (
+	ErrorKind_name = map[int32]string{
+		0:  "ERROR_KIND_UNSPECIFIED",
+		1:  "ERROR_KIND_MISSING_KEY",
+		2:  "ERROR_KIND_AUTH",
+		3:  "ERROR_KIND_QUOTA_EXHAUSTED",
+		4:  "ERROR_KIND_RATE_LIMITED",
+		5:  "ERROR_KIND_UPSTREAM",
+		6:  "ERROR_KIND_TIMEOUT",
+		7:  "ERROR_KIND_PARSE",
+		8:  "ERROR_KIND_INVALID_RESPONSE",
+		9:  "ERROR_KIND_REQUEST",
+		10: "ERROR_KIND_UNCLASSIFIED",
+	}
+	ErrorKind_value = map[string]int32{
+		"ERROR_KIND_UNSPECIFIED":      0,
+		"ERROR_KIND_MISSING_KEY":      1,
+		"ERROR_KIND_AUTH":             2,
+		"ERROR_KIND_QUOTA_EXHAUSTED":  3,
+		"ERROR_KIND_RATE_LIMITED":     4,
+		"ERROR_KIND_UPSTREAM":         5,
+		"ERROR_KIND_TIMEOUT":          6,
+		"ERROR_KIND_PARSE":            7,
+		"ERROR_KIND_INVALID_RESPONSE": 8,
+		"ERROR_KIND_REQUEST":          9,
+		"ERROR_KIND_UNCLASSIFIED":     10,
+	}
+)
+
+func (x ErrorKind) Enum() *ErrorKind {
+	p := new(ErrorKind)
+	*p = x
+	return p
+}
+
+func (x ErrorKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_xsubfind3r_v1_xsubfind3r_proto_enumTypes[1].Descriptor()
+}
+
+func (ErrorKind) Type() protoreflect.EnumType {
+	return &file_xsubfind3r_v1_xsubfind3r_proto_enumTypes[1]
+}
+
+func (x ErrorKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorKind.Descriptor instead.
+func (ErrorKind) EnumDescriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{1}
+}
+
+type RunState int32
+
+const (
+	RunState_RUN_STATE_UNSPECIFIED RunState = 0
+	RunState_RUN_STATE_RUNNING     RunState = 1
+	RunState_RUN_STATE_COMPLETED   RunState = 2
+	RunState_RUN_STATE_CANCELLED   RunState = 3
+)
+
+// Enum value maps for RunState.
+var (
+	RunState_name = map[int32]string{
+		0: "RUN_STATE_UNSPECIFIED",
+		1: "RUN_STATE_RUNNING",
+		2: "RUN_STATE_COMPLETED",
+		3: "RUN_STATE_CANCELLED",
+	}
+	RunState_value = map[string]int32{
+		"RUN_STATE_UNSPECIFIED": 0,
+		"RUN_STATE_RUNNING":     1,
+		"RUN_STATE_COMPLETED":   2,
+		"RUN_STATE_CANCELLED":   3,
+	}
+)
+
+func (x RunState) Enum() *RunState {
+	p := new(RunState)
+	*p = x
+	return p
+}
+
+func (x RunState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RunState) Descriptor() protoreflect.EnumDescriptor {
+	return file_xsubfind3r_v1_xsubfind3r_proto_enumTypes[2].Descriptor()
+}
+
+func (RunState) Type() protoreflect.EnumType {
+	return &file_xsubfind3r_v1_xsubfind3r_proto_enumTypes[2]
+}
+
+func (x RunState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RunState.Descriptor instead.
+func (RunState) EnumDescriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{2}
+}
+
+type ListSourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSourcesRequest) Reset() {
+	*x = ListSourcesRequest{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSourcesRequest) ProtoMessage() {}
+
+func (x *ListSourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSourcesRequest.ProtoReflect.Descriptor instead.
+func (*ListSourcesRequest) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{0}
+}
+
+type ListSourcesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The sources, sorted by name.
+	Sources       []*Source `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSourcesResponse) Reset() {
+	*x = ListSourcesResponse{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSourcesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSourcesResponse) ProtoMessage() {}
+
+func (x *ListSourcesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSourcesResponse.ProtoReflect.Descriptor instead.
+func (*ListSourcesResponse) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListSourcesResponse) GetSources() []*Source {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+// Source describes a source.
+type Source struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the source, e.g. "crtsh".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Whether the source takes API keys.
+	Keyed bool `protobuf:"varint,2,opt,name=keyed,proto3" json:"keyed,omitempty"`
+	// The number of API keys configured for the source. A keyed source without keys
+	// reports a missing key error instead of results.
+	Keys int32 `protobuf:"varint,3,opt,name=keys,proto3" json:"keys,omitempty"`
+	// Whether the source derives results from the subdomains found by the other sources.
+	Enricher      bool `protobuf:"varint,4,opt,name=enricher,proto3" json:"enricher,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Source) Reset() {
+	*x = Source{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Source) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Source) ProtoMessage() {}
+
+func (x *Source) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Source.ProtoReflect.Descriptor instead.
+func (*Source) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Source) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Source) GetKeyed() bool {
+	if x != nil {
+		return x.Keyed
+	}
+	return false
+}
+
+func (x *Source) GetKeys() int32 {
+	if x != nil {
+		return x.Keys
+	}
+	return 0
+}
+
+func (x *Source) GetEnricher() bool {
+	if x != nil {
+		return x.Enricher
+	}
+	return false
+}
+
+type EnumerateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The domain to enumerate. Required.
+	Domain string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The sources to use, or none for every source.
+	Sources []string `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
+	// The sources not to use.
+	Exclude []string `protobuf:"bytes,3,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	// Stop the run after this long.
+	Timeout *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Stop the run after this many subdomains.
+	MaxResults int32 `protobuf:"varint,5,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	// Drop subdomains equal to or under any of these names.
+	OutOfScope []string `protobuf:"bytes,6,rep,name=out_of_scope,json=outOfScope,proto3" json:"out_of_scope,omitempty"`
+	// Drop results last seen longer ago than this.
+	MaxAge *durationpb.Duration `protobuf:"bytes,7,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	// Drop subdomains scoring below this (0-1).
+	MinScore float64 `protobuf:"fixed64,8,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"`
+	// How often to send progress events; 5 seconds unless set.
+	ProgressInterval *durationpb.Duration `protobuf:"bytes,9,opt,name=progress_interval,json=progressInterval,proto3" json:"progress_interval,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *EnumerateRequest) Reset() {
+	*x = EnumerateRequest{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnumerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumerateRequest) ProtoMessage() {}
+
+func (x *EnumerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumerateRequest.ProtoReflect.Descriptor instead.
+func (*EnumerateRequest) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EnumerateRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *EnumerateRequest) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *EnumerateRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *EnumerateRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *EnumerateRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+func (x *EnumerateRequest) GetOutOfScope() []string {
+	if x != nil {
+		return x.OutOfScope
+	}
+	return nil
+}
+
+func (x *EnumerateRequest) GetMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxAge
+	}
+	return nil
+}
+
+func (x *EnumerateRequest) GetMinScore() float64 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *EnumerateRequest) GetProgressInterval() *durationpb.Duration {
+	if x != nil {
+		return x.ProgressInterval
+	}
+	return nil
+}
+
+type EnumerateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*EnumerateResponse_Started
+	//	*EnumerateResponse_Result
+	//	*EnumerateResponse_Progress
+	//	*EnumerateResponse_Finished
+	Event         isEnumerateResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnumerateResponse) Reset() {
+	*x = EnumerateResponse{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnumerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumerateResponse) ProtoMessage() {}
+
+func (x *EnumerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumerateResponse.ProtoReflect.Descriptor instead.
+func (*EnumerateResponse) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EnumerateResponse) GetEvent() isEnumerateResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *EnumerateResponse) GetStarted() *RunStarted {
+	if x != nil {
+		if x, ok := x.Event.(*EnumerateResponse_Started); ok {
+			return x.Started
+		}
+	}
+	return nil
+}
+
+func (x *EnumerateResponse) GetResult() *Result {
+	if x != nil {
+		if x, ok := x.Event.(*EnumerateResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *EnumerateResponse) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Event.(*EnumerateResponse_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *EnumerateResponse) GetFinished() *RunFinished {
+	if x != nil {
+		if x, ok := x.Event.(*EnumerateResponse_Finished); ok {
+			return x.Finished
+		}
+	}
+	return nil
+}
+
+type isEnumerateResponse_Event interface {
+	isEnumerateResponse_Event()
+}
+
+type EnumerateResponse_Started struct {
+	Started *RunStarted `protobuf:"bytes,1,opt,name=started,proto3,oneof"`
+}
+
+type EnumerateResponse_Result struct {
+	Result *Result `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+type EnumerateResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,3,opt,name=progress,proto3,oneof"`
+}
+
+type EnumerateResponse_Finished struct {
+	Finished *RunFinished `protobuf:"bytes,4,opt,name=finished,proto3,oneof"`
+}
+
+func (*EnumerateResponse_Started) isEnumerateResponse_Event() {}
+
+func (*EnumerateResponse_Result) isEnumerateResponse_Event() {}
+
+func (*EnumerateResponse_Progress) isEnumerateResponse_Event() {}
+
+func (*EnumerateResponse_Finished) isEnumerateResponse_Event() {}
+
+// RunStarted is the first event of a run.
+type RunStarted struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The identifier of the run, for CancelRun and GetStats.
+	RunId string `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// The domain, normalized.
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The sources the run uses.
+	Sources       []string `protobuf:"bytes,3,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunStarted) Reset() {
+	*x = RunStarted{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunStarted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunStarted) ProtoMessage() {}
+
+func (x *RunStarted) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunStarted.ProtoReflect.Descriptor instead.
+func (*RunStarted) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RunStarted) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *RunStarted) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RunStarted) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+// Progress reports the statistics of a run so far.
+type Progress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *Stats                 `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Progress) GetStats() *Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// RunFinished is the last event of a run.
+type RunFinished struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         RunState               `protobuf:"varint,1,opt,name=state,proto3,enum=xsubfind3r.v1.RunState" json:"state,omitempty"`
+	Stats         *Stats                 `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunFinished) Reset() {
+	*x = RunFinished{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunFinished) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunFinished) ProtoMessage() {}
+
+func (x *RunFinished) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunFinished.ProtoReflect.Descriptor instead.
+func (*RunFinished) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RunFinished) GetState() RunState {
+	if x != nil {
+		return x.State
+	}
+	return RunState_RUN_STATE_UNSPECIFIED
+}
+
+func (x *RunFinished) GetStats() *Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// Result is a result of a run: a subdomain, another type of result or a source error.
+type Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  ResultType             `protobuf:"varint,1,opt,name=type,proto3,enum=xsubfind3r.v1.ResultType" json:"type,omitempty"`
+	// The source that found the result.
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Value  string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// The host the result was derived from, if any.
+	Origin    string                 `protobuf:"bytes,4,opt,name=origin,proto3" json:"origin,omitempty"`
+	Metadata  map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	FirstSeen *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
+	LastSeen  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	// The confidence score of subdomains (0-1).
+	Score float64 `protobuf:"fixed64,8,opt,name=score,proto3" json:"score,omitempty"`
+	// The sources that found the subdomain so far.
+	Sources []string `protobuf:"bytes,9,rep,name=sources,proto3" json:"sources,omitempty"`
+	// The error of error results.
+	Error         *SourceError `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Result) GetType() ResultType {
+	if x != nil {
+		return x.Type
+	}
+	return ResultType_RESULT_TYPE_UNSPECIFIED
+}
+
+func (x *Result) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Result) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Result) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Result) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Result) GetFirstSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return nil
+}
+
+func (x *Result) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Result) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Result) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *Result) GetError() *SourceError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// SourceError is an error reported by a source. API keys are redacted.
+type SourceError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  ErrorKind              `protobuf:"varint,1,opt,name=kind,proto3,enum=xsubfind3r.v1.ErrorKind" json:"kind,omitempty"`
+	// The HTTP status code of the response, if any.
+	StatusCode int32 `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	// The request URL, if known.
+	Url string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// The description of the underlying error, if any.
+	Message       string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceError) Reset() {
+	*x = SourceError{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceError) ProtoMessage() {}
+
+func (x *SourceError) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceError.ProtoReflect.Descriptor instead.
+func (*SourceError) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SourceError) GetKind() ErrorKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ErrorKind_ERROR_KIND_UNSPECIFIED
+}
+
+func (x *SourceError) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *SourceError) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SourceError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Stats are the statistics of a run.
+type Stats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The number of unique subdomains found.
+	Subdomains int32 `protobuf:"varint,1,opt,name=subdomains,proto3" json:"subdomains,omitempty"`
+	// The number of results of every type but errors.
+	Results int32 `protobuf:"varint,2,opt,name=results,proto3" json:"results,omitempty"`
+	// The number of source errors.
+	Errors int32 `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	// How long the run has taken.
+	Duration *durationpb.Duration `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	// The statistics of each source, keyed by name.
+	Sources       map[string]*SourceStats `protobuf:"bytes,5,rep,name=sources,proto3" json:"sources,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Stats) GetSubdomains() int32 {
+	if x != nil {
+		return x.Subdomains
+	}
+	return 0
+}
+
+func (x *Stats) GetResults() int32 {
+	if x != nil {
+		return x.Results
+	}
+	return 0
+}
+
+func (x *Stats) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *Stats) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Stats) GetSources() map[string]*SourceStats {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type SourceStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subdomains    int32                  `protobuf:"varint,1,opt,name=subdomains,proto3" json:"subdomains,omitempty"`
+	Results       int32                  `protobuf:"varint,2,opt,name=results,proto3" json:"results,omitempty"`
+	Errors        int32                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceStats) Reset() {
+	*x = SourceStats{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceStats) ProtoMessage() {}
+
+func (x *SourceStats) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceStats.ProtoReflect.Descriptor instead.
+func (*SourceStats) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SourceStats) GetSubdomains() int32 {
+	if x != nil {
+		return x.Subdomains
+	}
+	return 0
+}
+
+func (x *SourceStats) GetResults() int32 {
+	if x != nil {
+		return x.Results
+	}
+	return 0
+}
+
+func (x *SourceStats) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+type CancelRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunRequest) Reset() {
+	*x = CancelRunRequest{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunRequest) ProtoMessage() {}
+
+func (x *CancelRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunRequest.ProtoReflect.Descriptor instead.
+func (*CancelRunRequest) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CancelRunRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type CancelRunResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The state of the run, cancelled unless it had already finished.
+	State         RunState `protobuf:"varint,1,opt,name=state,proto3,enum=xsubfind3r.v1.RunState" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunResponse) Reset() {
+	*x = CancelRunResponse{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunResponse) ProtoMessage() {}
+
+func (x *CancelRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunResponse.ProtoReflect.Descriptor instead.
+func (*CancelRunResponse) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelRunResponse) GetState() RunState {
+	if x != nil {
+		return x.State
+	}
+	return RunState_RUN_STATE_UNSPECIFIED
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetStatsRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type GetStatsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	RunId   string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Domain  string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	State   RunState               `protobuf:"varint,3,opt,name=state,proto3,enum=xsubfind3r.v1.RunState" json:"state,omitempty"`
+	Started *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=started,proto3" json:"started,omitempty"`
+	// When the run finished, unset while it runs.
+	Finished      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=finished,proto3" json:"finished,omitempty"`
+	Stats         *Stats                 `protobuf:"bytes,6,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetStatsResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *GetStatsResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetStatsResponse) GetState() RunState {
+	if x != nil {
+		return x.State
+	}
+	return RunState_RUN_STATE_UNSPECIFIED
+}
+
+func (x *GetStatsResponse) GetStarted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Started
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetFinished() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Finished
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetStats() *Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+var File_xsubfind3r_v1_xsubfind3r_proto protoreflect.FileDescriptor
+
+const file_xsubfind3r_v1_xsubfind3r_proto_rawDesc = "" +
+	"\n" +
+	"\x1exsubfind3r/v1/xsubfind3r.proto\x12\rxsubfind3r.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x14\n" +
+	"\x12ListSourcesRequest\"F\n" +
+	"\x13ListSourcesResponse\x12/\n" +
+	"\asources\x18\x01 \x03(\v2\x15.xsubfind3r.v1.SourceR\asources\"b\n" +
+	"\x06Source\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05keyed\x18\x02 \x01(\bR\x05keyed\x12\x12\n" +
+	"\x04keys\x18\x03 \x01(\x05R\x04keys\x12\x1a\n" +
+	"\benricher\x18\x04 \x01(\bR\benricher\"\xef\x02\n" +
+	"\x10EnumerateRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x18\n" +
+	"\asources\x18\x02 \x03(\tR\asources\x12\x18\n" +
+	"\aexclude\x18\x03 \x03(\tR\aexclude\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1f\n" +
+	"\vmax_results\x18\x05 \x01(\x05R\n" +
+	"maxResults\x12 \n" +
+	"\fout_of_scope\x18\x06 \x03(\tR\n" +
+	"outOfScope\x122\n" +
+	"\amax_age\x18\a \x01(\v2\x19.google.protobuf.DurationR\x06maxAge\x12\x1b\n" +
+	"\tmin_score\x18\b \x01(\x01R\bminScore\x12F\n" +
+	"\x11progress_interval\x18\t \x01(\v2\x19.google.protobuf.DurationR\x10progressInterval\"\xf5\x01\n" +
+	"\x11EnumerateResponse\x125\n" +
+	"\astarted\x18\x01 \x01(\v2\x19.xsubfind3r.v1.RunStartedH\x00R\astarted\x12/\n" +
+	"\x06result\x18\x02 \x01(\v2\x15.xsubfind3r.v1.ResultH\x00R\x06result\x125\n" +
+	"\bprogress\x18\x03 \x01(\v2\x17.xsubfind3r.v1.ProgressH\x00R\bprogress\x128\n" +
+	"\bfinished\x18\x04 \x01(\v2\x1a.xsubfind3r.v1.RunFinishedH\x00R\bfinishedB\a\n" +
+	"\x05event\"U\n" +
+	"\n" +
+	"RunStarted\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x18\n" +
+	"\asources\x18\x03 \x03(\tR\asources\"6\n" +
+	"\bProgress\x12*\n" +
+	"\x05stats\x18\x01 \x01(\v2\x14.xsubfind3r.v1.StatsR\x05stats\"h\n" +
+	"\vRunFinished\x12-\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x17.xsubfind3r.v1.RunStateR\x05state\x12*\n" +
+	"\x05stats\x18\x02 \x01(\v2\x14.xsubfind3r.v1.StatsR\x05stats\"\xd1\x03\n" +
+	"\x06Result\x12-\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x19.xsubfind3r.v1.ResultTypeR\x04type\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x16\n" +
+	"\x06origin\x18\x04 \x01(\tR\x06origin\x12?\n" +
+	"\bmetadata\x18\x05 \x03(\v2#.xsubfind3r.v1.Result.MetadataEntryR\bmetadata\x129\n" +
+	"\n" +
+	"first_seen\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tfirstSeen\x127\n" +
+	"\tlast_seen\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12\x14\n" +
+	"\x05score\x18\b \x01(\x01R\x05score\x12\x18\n" +
+	"\asources\x18\t \x03(\tR\asources\x120\n" +
+	"\x05error\x18\n" +
+	" \x01(\v2\x1a.xsubfind3r.v1.SourceErrorR\x05error\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x88\x01\n" +
+	"\vSourceError\x12,\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x18.xsubfind3r.v1.ErrorKindR\x04kind\x12\x1f\n" +
+	"\vstatus_code\x18\x02 \x01(\x05R\n" +
+	"statusCode\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xa5\x02\n" +
+	"\x05Stats\x12\x1e\n" +
+	"\n" +
+	"subdomains\x18\x01 \x01(\x05R\n" +
+	"subdomains\x12\x18\n" +
+	"\aresults\x18\x02 \x01(\x05R\aresults\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x05R\x06errors\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12;\n" +
+	"\asources\x18\x05 \x03(\v2!.xsubfind3r.v1.Stats.SourcesEntryR\asources\x1aV\n" +
+	"\fSourcesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.xsubfind3r.v1.SourceStatsR\x05value:\x028\x01\"_\n" +
+	"\vSourceStats\x12\x1e\n" +
+	"\n" +
+	"subdomains\x18\x01 \x01(\x05R\n" +
+	"subdomains\x12\x18\n" +
+	"\aresults\x18\x02 \x01(\x05R\aresults\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x05R\x06errors\")\n" +
+	"\x10CancelRunRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"B\n" +
+	"\x11CancelRunResponse\x12-\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x17.xsubfind3r.v1.RunStateR\x05state\"(\n" +
+	"\x0fGetStatsRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"\x8a\x02\n" +
+	"\x10GetStatsResponse\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12-\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x17.xsubfind3r.v1.RunStateR\x05state\x124\n" +
+	"\astarted\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\astarted\x126\n" +
+	"\bfinished\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bfinished\x12*\n" +
+	"\x05stats\x18\x06 \x01(\v2\x14.xsubfind3r.v1.StatsR\x05stats*\xb1\x01\n" +
+	"\n" +
+	"ResultType\x12\x1b\n" +
+	"\x17RESULT_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15RESULT_TYPE_SUBDOMAIN\x10\x01\x12\x15\n" +
+	"\x11RESULT_TYPE_ERROR\x10\x02\x12\x12\n" +
+	"\x0eRESULT_TYPE_IP\x10\x03\x12\x13\n" +
+	"\x0fRESULT_TYPE_URL\x10\x04\x12\x16\n" +
+	"\x12RESULT_TYPE_RECORD\x10\x05\x12\x13\n" +
+	"\x0fRESULT_TYPE_ASN\x10\x06*\xb2\x02\n" +
+	"\tErrorKind\x12\x1a\n" +
+	"\x16ERROR_KIND_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16ERROR_KIND_MISSING_KEY\x10\x01\x12\x13\n" +
+	"\x0fERROR_KIND_AUTH\x10\x02\x12\x1e\n" +
+	"\x1aERROR_KIND_QUOTA_EXHAUSTED\x10\x03\x12\x1b\n" +
+	"\x17ERROR_KIND_RATE_LIMITED\x10\x04\x12\x17\n" +
+	"\x13ERROR_KIND_UPSTREAM\x10\x05\x12\x16\n" +
+	"\x12ERROR_KIND_TIMEOUT\x10\x06\x12\x14\n" +
+	"\x10ERROR_KIND_PARSE\x10\a\x12\x1f\n" +
+	"\x1bERROR_KIND_INVALID_RESPONSE\x10\b\x12\x16\n" +
+	"\x12ERROR_KIND_REQUEST\x10\t\x12\x1b\n" +
+	"\x17ERROR_KIND_UNCLASSIFIED\x10\n" +
+	"*n\n" +
+	"\bRunState\x12\x19\n" +
+	"\x15RUN_STATE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11RUN_STATE_RUNNING\x10\x01\x12\x17\n" +
+	"\x13RUN_STATE_COMPLETED\x10\x02\x12\x17\n" +
+	"\x13RUN_STATE_CANCELLED\x10\x032\xd4\x02\n" +
+	"\rFinderService\x12T\n" +
+	"\vListSources\x12!.xsubfind3r.v1.ListSourcesRequest\x1a\".xsubfind3r.v1.ListSourcesResponse\x12P\n" +
+	"\tEnumerate\x12\x1f.xsubfind3r.v1.EnumerateRequest\x1a .xsubfind3r.v1.EnumerateResponse0\x01\x12N\n" +
+	"\tCancelRun\x12\x1f.xsubfind3r.v1.CancelRunRequest\x1a .xsubfind3r.v1.CancelRunResponse\x12K\n" +
+	"\bGetStats\x12\x1e.xsubfind3r.v1.GetStatsRequest\x1a\x1f.xsubfind3r.v1.GetStatsResponseBDZBgithub.com/hueristiq/xsubfind3r/pkg/xsubfind3r/rpc/v1;xsubfind3rv1b\x06proto3"
+
+var (
+	file_xsubfind3r_v1_xsubfind3r_proto_rawDescOnce sync.Once
+	file_xsubfind3r_v1_xsubfind3r_proto_rawDescData []byte
+)
+
+func file_xsubfind3r_v1_xsubfind3r_proto_rawDescGZIP() []byte {
+	file_xsubfind3r_v1_xsubfind3r_proto_rawDescOnce.Do(func() {
+		file_xsubfind3r_v1_xsubfind3r_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_xsubfind3r_v1_xsubfind3r_proto_rawDesc), len(file_xsubfind3r_v1_xsubfind3r_proto_rawDesc)))
+	})
+	return file_xsubfind3r_v1_xsubfind3r_proto_rawDescData
+}
+
+var file_xsubfind3r_v1_xsubfind3r_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_xsubfind3r_v1_xsubfind3r_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_xsubfind3r_v1_xsubfind3r_proto_goTypes = []any{
+	(ResultType)(0),               // 0: xsubfind3r.v1.ResultType
+	(ErrorKind)(0),                // 1: xsubfind3r.v1.ErrorKind
+	(RunState)(0),                 // 2: xsubfind3r.v1.RunState
+	(*ListSourcesRequest)(nil),    // 3: xsubfind3r.v1.ListSourcesRequest
+	(*ListSourcesResponse)(nil),   // 4: xsubfind3r.v1.ListSourcesResponse
+	(*Source)(nil),                // 5: xsubfind3r.v1.Source
+	(*EnumerateRequest)(nil),      // 6: xsubfind3r.v1.EnumerateRequest
+	(*EnumerateResponse)(nil),     // 7: xsubfind3r.v1.EnumerateResponse
+	(*RunStarted)(nil),            // 8: xsubfind3r.v1.RunStarted
+	(*Progress)(nil),              // 9: xsubfind3r.v1.Progress
+	(*RunFinished)(nil),           // 10: xsubfind3r.v1.RunFinished
+	(*Result)(nil),                // 11: xsubfind3r.v1.Result
+	(*SourceError)(nil),           // 12: xsubfind3r.v1.SourceError
+	(*Stats)(nil),                 // 13: xsubfind3r.v1.Stats
+	(*SourceStats)(nil),           // 14: xsubfind3r.v1.SourceStats
+	(*CancelRunRequest)(nil),      // 15: xsubfind3r.v1.CancelRunRequest
+	(*CancelRunResponse)(nil),     // 16: xsubfind3r.v1.CancelRunResponse
+	(*GetStatsRequest)(nil),       // 17: xsubfind3r.v1.GetStatsRequest
+	(*GetStatsResponse)(nil),      // 18: xsubfind3r.v1.GetStatsResponse
+	nil,                           // 19: xsubfind3r.v1.Result.MetadataEntry
+	nil,                           // 20: xsubfind3r.v1.Stats.SourcesEntry
+	(*durationpb.Duration)(nil),   // 21: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+}
+var file_xsubfind3r_v1_xsubfind3r_proto_depIdxs = []int32{
+	5,  // 0: xsubfind3r.v1.ListSourcesResponse.sources:type_name -> xsubfind3r.v1.Source
+	21, // 1: xsubfind3r.v1.EnumerateRequest.timeout:type_name -> google.protobuf.Duration
+	21, // 2: xsubfind3r.v1.EnumerateRequest.max_age:type_name -> google.protobuf.Duration
+	21, // 3: xsubfind3r.v1.EnumerateRequest.progress_interval:type_name -> google.protobuf.Duration
+	8,  // 4: xsubfind3r.v1.EnumerateResponse.started:type_name -> xsubfind3r.v1.RunStarted
+	11, // 5: xsubfind3r.v1.EnumerateResponse.result:type_name -> xsubfind3r.v1.Result
+	9,  // 6: xsubfind3r.v1.EnumerateResponse.progress:type_name -> xsubfind3r.v1.Progress
+	10, // 7: xsubfind3r.v1.EnumerateResponse.finished:type_name -> xsubfind3r.v1.RunFinished
+	13, // 8: xsubfind3r.v1.Progress.stats:type_name -> xsubfind3r.v1.Stats
+	2,  // 9: xsubfind3r.v1.RunFinished.state:type_name -> xsubfind3r.v1.RunState
+	13, // 10: xsubfind3r.v1.RunFinished.stats:type_name -> xsubfind3r.v1.Stats
+	0,  // 11: xsubfind3r.v1.Result.type:type_name -> xsubfind3r.v1.ResultType
+	19, // 12: xsubfind3r.v1.Result.metadata:type_name -> xsubfind3r.v1.Result.MetadataEntry
+	22, // 13: xsubfind3r.v1.Result.first_seen:type_name -> google.protobuf.Timestamp
+	22, // 14: xsubfind3r.v1.Result.last_seen:type_name -> google.protobuf.Timestamp
+	12, // 15: xsubfind3r.v1.Result.error:type_name -> xsubfind3r.v1.SourceError
+	1,  // 16: xsubfind3r.v1.SourceError.kind:type_name -> xsubfind3r.v1.ErrorKind
+	21, // 17: xsubfind3r.v1.Stats.duration:type_name -> google.protobuf.Duration
+	20, // 18: xsubfind3r.v1.Stats.sources:type_name -> xsubfind3r.v1.Stats.SourcesEntry
+	2,  // 19: xsubfind3r.v1.CancelRunResponse.state:type_name -> xsubfind3r.v1.RunState
+	2,  // 20: xsubfind3r.v1.GetStatsResponse.state:type_name -> xsubfind3r.v1.RunState
+	22, // 21: xsubfind3r.v1.GetStatsResponse.started:type_name -> google.protobuf.Timestamp
+	22, // 22: xsubfind3r.v1.GetStatsResponse.finished:type_name -> google.protobuf.Timestamp
+	13, // 23: xsubfind3r.v1.GetStatsResponse.stats:type_name -> xsubfind3r.v1.Stats
+	14, // 24: xsubfind3r.v1.Stats.SourcesEntry.value:type_name -> xsubfind3r.v1.SourceStats
+	3,  // 25: xsubfind3r.v1.FinderService.ListSources:input_type -> xsubfind3r.v1.ListSourcesRequest
+	6,  // 26: xsubfind3r.v1.FinderService.Enumerate:input_type -> xsubfind3r.v1.EnumerateRequest
+	15, // 27: xsubfind3r.v1.FinderService.CancelRun:input_type -> xsubfind3r.v1.CancelRunRequest
+	17, // 28: xsubfind3r.v1.FinderService.GetStats:input_type -> xsubfind3r.v1.GetStatsRequest
+	4,  // 29: xsubfind3r.v1.FinderService.ListSources:output_type -> xsubfind3r.v1.ListSourcesResponse
+	7,  // 30: xsubfind3r.v1.FinderService.Enumerate:output_type -> xsubfind3r.v1.EnumerateResponse
+	16, // 31: xsubfind3r.v1.FinderService.CancelRun:output_type -> xsubfind3r.v1.CancelRunResponse
+	18, // 32: xsubfind3r.v1.FinderService.GetStats:output_type -> xsubfind3r.v1.GetStatsResponse
+	29, // [29:33] is the sub-list for method output_type
+	25, // [25:29] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_xsubfind3r_v1_xsubfind3r_proto_init() }
+func file_xsubfind3r_v1_xsubfind3r_proto_init() {
+	if File_xsubfind3r_v1_xsubfind3r_proto != nil {
+		return
+	}
+	file_xsubfind3r_v1_xsubfind3r_proto_msgTypes[4].OneofWrappers = []any{
+		(*EnumerateResponse_Started)(nil),
+		(*EnumerateResponse_Result)(nil),
+		(*EnumerateResponse_Progress)(nil),
+		(*EnumerateResponse_Finished)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_xsubfind3r_v1_xsubfind3r_proto_rawDesc), len(file_xsubfind3r_v1_xsubfind3r_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_xsubfind3r_v1_xsubfind3r_proto_goTypes,
+		DependencyIndexes: file_xsubfind3r_v1_xsubfind3r_proto_depIdxs,
+		EnumInfos:         file_xsubfind3r_v1_xsubfind3r_proto_enumTypes,
+		MessageInfos:      file_xsubfind3r_v1_xsubfind3r_proto_msgTypes,
+	}.Build()
+	File_xsubfind3r_v1_xsubfind3r_proto = out.File
+	file_xsubfind3r_v1_xsubfind3r_proto_goTypes = nil
+	file_xsubfind3r_v1_xsubfind3r_proto_depIdxs = nil
+}
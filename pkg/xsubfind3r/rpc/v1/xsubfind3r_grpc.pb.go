@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: xsubfind3r/v1/xsubfind3r.proto
+
+package xsubfind3rv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FinderService_ListSources_FullMethodName = "/xsubfind3r.v1.FinderService/ListSources"
+	FinderService_Enumerate_FullMethodName   = "/xsubfind3r.v1.FinderService/Enumerate"
+	FinderService_CancelRun_FullMethodName   = "/xsubfind3r.v1.FinderService/CancelRun"
+	FinderService_GetStats_FullMethodName    = "/xsubfind3r.v1.FinderService/GetStats"
+)
+
+// FinderServiceClient is the client API for FinderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FinderService exposes a Finder: its sources, and enumerations ("runs") of domains with
+// them.
+type FinderServiceClient interface {
+	// ListSources lists the sources runs may use.
+	ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error)
+	// Enumerate runs an enumeration, streaming a RunStarted event, then results and
+	// progress events as they arrive, then a RunFinished event. Cancelling the call, e.g.
+	// by a deadline, cancels the run and the sources it runs.
+	Enumerate(ctx context.Context, in *EnumerateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EnumerateResponse], error)
+	// CancelRun cancels a run in progress, ending its Enumerate call with a RunFinished
+	// event.
+	CancelRun(ctx context.Context, in *CancelRunRequest, opts ...grpc.CallOption) (*CancelRunResponse, error)
+	// GetStats returns the statistics of a run, in progress or recently finished.
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+}
+
+type finderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFinderServiceClient(cc grpc.ClientConnInterface) FinderServiceClient {
+	return &finderServiceClient{cc}
+}
+
+func (c *finderServiceClient) ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSourcesResponse)
+	err := c.cc.Invoke(ctx, FinderService_ListSources_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finderServiceClient) Enumerate(ctx context.Context, in *EnumerateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EnumerateResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FinderService_ServiceDesc.Streams[0], FinderService_Enumerate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EnumerateRequest, EnumerateResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FinderService_EnumerateClient = grpc.ServerStreamingClient[EnumerateResponse]
+
+func (c *finderServiceClient) CancelRun(ctx context.Context, in *CancelRunRequest, opts ...grpc.CallOption) (*CancelRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelRunResponse)
+	err := c.cc.Invoke(ctx, FinderService_CancelRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finderServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, FinderService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FinderServiceServer is the server API for FinderService service.
+// All implementations must embed UnimplementedFinderServiceServer
+// for forward compatibility.
+//
+// FinderService exposes a Finder: its sources, and enumerations ("runs") of domains with
+// them.
+type FinderServiceServer interface {
+	// ListSources lists the sources runs may use.
+	ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error)
+	// Enumerate runs an enumeration, streaming a RunStarted event, then results and
+	// progress events as they arrive, then a RunFinished event. Cancelling the call, e.g.
+	// by a deadline, cancels the run and the sources it runs.
+	Enumerate(*EnumerateRequest, grpc.ServerStreamingServer[EnumerateResponse]) error
+	// CancelRun cancels a run in progress, ending its Enumerate call with a RunFinished
+	// event.
+	CancelRun(context.Context, *CancelRunRequest) (*CancelRunResponse, error)
+	// GetStats returns the statistics of a run, in progress or recently finished.
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	mustEmbedUnimplementedFinderServiceServer()
+}
+
+// UnimplementedFinderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFinderServiceServer struct{}
+
+func (UnimplementedFinderServiceServer) ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSources not implemented")
+}
+func (UnimplementedFinderServiceServer) Enumerate(*EnumerateRequest, grpc.ServerStreamingServer[EnumerateResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Enumerate not implemented")
+}
+func (UnimplementedFinderServiceServer) CancelRun(context.Context, *CancelRunRequest) (*CancelRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelRun not implemented")
+}
+func (UnimplementedFinderServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedFinderServiceServer) mustEmbedUnimplementedFinderServiceServer() {}
+func (UnimplementedFinderServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeFinderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FinderServiceServer will
+// result in compilation errors.
+type UnsafeFinderServiceServer interface {
+	mustEmbedUnimplementedFinderServiceServer()
+}
+
+func RegisterFinderServiceServer(s grpc.ServiceRegistrar, srv FinderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFinderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FinderService_ServiceDesc, srv)
+}
+
+func _FinderService_ListSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinderServiceServer).ListSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinderService_ListSources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinderServiceServer).ListSources(ctx, req.(*ListSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinderService_Enumerate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EnumerateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FinderServiceServer).Enumerate(m, &grpc.GenericServerStream[EnumerateRequest, EnumerateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FinderService_EnumerateServer = grpc.ServerStreamingServer[EnumerateResponse]
+
+func _FinderService_CancelRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinderServiceServer).CancelRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinderService_CancelRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinderServiceServer).CancelRun(ctx, req.(*CancelRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinderService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinderServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FinderService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinderServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FinderService_ServiceDesc is the grpc.ServiceDesc for FinderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FinderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xsubfind3r.v1.FinderService",
+	HandlerType: (*FinderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSources",
+			Handler:    _FinderService_ListSources_Handler,
+		},
+		{
+			MethodName: "CancelRun",
+			Handler:    _FinderService_CancelRun_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _FinderService_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Enumerate",
+			Handler:       _FinderService_Enumerate_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "xsubfind3r/v1/xsubfind3r.proto",
+}
@@ -0,0 +1,176 @@
+package xsubfind3r
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// ScoringConfiguration configures the confidence score the Finder assigns to every
+// subdomain. The score, between 0 and 1, combines:
+//
+//   - The reporting sources: each source reporting the subdomain is independent evidence
+//     weighted by the source's reliability, so the score grows with both the number and
+//     the reliability of the sources.
+//   - Free-text extraction: sources that extract names from free text, such as code or
+//     archived URLs, count for half their weight.
+//   - Resolution: a subdomain seen live, i.e. reported with DNS records or IP addresses or
+//     reached by the tls source, gains ResolvedWeight as further evidence.
+//   - Recency: a subdomain last seen more than 90 days ago loses up to half of its score,
+//     reached at two years. Subdomains no source dated are not penalized.
+//
+// Fields:
+//   - Weights (map[string]float64): The reliability of sources, between 0 and 1, keyed by
+//     source name. Sources not listed use their DefaultWeights entry, or DefaultWeight.
+type ScoringConfiguration struct {
+	Weights map[string]float64 `yaml:"weights"`
+}
+
+// scorer computes confidence scores from the evidence collected for a subdomain.
+//
+// Fields:
+//   - weights (map[string]float64): The reliability of every source.
+type scorer struct {
+	weights map[string]float64
+}
+
+// weight returns the reliability of the named source.
+func (s *scorer) weight(source string) (weight float64) {
+	weight, ok := s.weights[source]
+	if !ok {
+		weight = DefaultWeight
+	}
+
+	weight = math.Max(0, math.Min(1, weight))
+
+	if slices.Contains(extractingSources, source) {
+		weight *= extractedFactor
+	}
+
+	return
+}
+
+// score returns the confidence score of a subdomain.
+//
+// Parameters:
+//   - reporters (map[string]struct{}): The names of the sources that reported it.
+//   - resolved (bool): Whether it was seen live.
+//   - last (time.Time): When it was last seen, or the zero time if unknown.
+//   - now (time.Time): The time the score is computed at.
+//
+// Returns:
+//   - score (float64): The score, between 0 and 1, rounded to three decimals.
+func (s *scorer) score(reporters map[string]struct{}, resolved bool, last, now time.Time) (score float64) {
+	miss := 1.0
+
+	for source := range reporters {
+		miss *= 1 - s.weight(source)
+	}
+
+	if resolved {
+		miss *= 1 - ResolvedWeight
+	}
+
+	score = (1 - miss) * recency(last, now)
+
+	score = math.Round(score*1000) / 1000
+
+	return
+}
+
+// recency returns the factor applied to the score of a subdomain last seen at last: 1 up
+// to 90 days before now, decreasing linearly to 0.5 at two years.
+func recency(last, now time.Time) (factor float64) {
+	factor = 1
+
+	if last.IsZero() {
+		return
+	}
+
+	age := now.Sub(last)
+
+	if age <= freshAge {
+		return
+	}
+
+	if age >= staleAge {
+		factor = staleFactor
+
+		return
+	}
+
+	factor = 1 - (1-staleFactor)*float64(age-freshAge)/float64(staleAge-freshAge)
+
+	return
+}
+
+// newScorer returns a scorer using the weights in cfg, falling back to DefaultWeights.
+func newScorer(cfg ScoringConfiguration) (s *scorer) {
+	s = &scorer{
+		weights: maps.Clone(DefaultWeights),
+	}
+
+	maps.Copy(s.weights, cfg.Weights)
+
+	return
+}
+
+const (
+	// DefaultWeight is the reliability of sources with no configured or default weight.
+	DefaultWeight = 0.5
+	// ResolvedWeight is the weight of the evidence that a subdomain was seen live.
+	ResolvedWeight = 0.8
+
+	extractedFactor = 0.5
+	staleFactor     = 0.5
+	freshAge        = 90 * 24 * time.Hour
+	staleAge        = 2 * 365 * 24 * time.Hour
+)
+
+var (
+	// DefaultWeights is the reliability of each source, between 0 and 1, used unless
+	// overridden by ScoringConfiguration.Weights. Authoritative and passive DNS data rank
+	// highest; certificate data next, as names in certificates need not exist in DNS; then
+	// aggregators of unknown provenance.
+	DefaultWeights = map[string]float64{
+		sources.ANUBIS:             0.5,
+		sources.BEVIGIL:            0.5,
+		sources.BUILTWITH:          0.5,
+		sources.CENSYS:             0.7,
+		sources.CERTIFICATEDETAILS: 0.6,
+		sources.CERTSPOTTER:        0.7,
+		sources.CHAOS:              0.7,
+		sources.COMMONCRAWL:        0.6,
+		sources.CRTSH:              0.7,
+		sources.CTLOGS:             0.7,
+		sources.DNSSEC:             0.95,
+		sources.DRIFTNET:           0.6,
+		sources.FDNSFILE:           0.8,
+		sources.FULLHUNT:           0.6,
+		sources.GITHUB:             0.5,
+		sources.HACKERTARGET:       0.6,
+		sources.HOSTSFILE:          0.5,
+		sources.INTELLIGENCEX:      0.4,
+		sources.JSONLFILE:          0.5,
+		sources.LEAKIX:             0.6,
+		sources.OPENTHREATEXCHANGE: 0.75,
+		sources.SECURITYTRAILS:     0.85,
+		sources.SHODAN:             0.75,
+		sources.SUBDOMAINCENTER:    0.5,
+		sources.TLS:                0.9,
+		sources.URLSCAN:            0.7,
+		sources.VIRUSTOTAL:         0.8,
+		sources.WAYBACK:            0.6,
+		sources.ZONEFILE:           0.95,
+	}
+
+	// extractingSources are the sources that extract names from free text.
+	extractingSources = []string{
+		sources.COMMONCRAWL,
+		sources.GITHUB,
+		sources.WAYBACK,
+	}
+)
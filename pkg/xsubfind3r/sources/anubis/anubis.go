@@ -11,7 +11,6 @@ package anubis
 import (
 	"encoding/json"
 
-	hqgohttp "github.com/hueristiq/hq-go-http"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
 )
 
@@ -24,14 +23,14 @@ type Source struct{}
 //
 // Parameters:
 //   - domain (string): The target domain for which subdomains are to be retrieved.
-//   - _ (*sources.Configuration): The configuration instance containing API keys,
+//   - cfg (*sources.Configuration): The configuration instance containing API keys,
 //     the URL validation function, and any additional settings required by the source.
 //
 // Returns:
 //   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
 //     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
 //     encountered during the operation.
-func (source *Source) Run(domain string, _ *sources.Configuration) <-chan sources.Result {
+func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
 	results := make(chan sources.Result)
 
 	go func() {
@@ -39,8 +38,20 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 
 		getSubdomainsReqURL := "https://jldc.me/anubis/subdomains/" + domain
 
-		getSubdomainsRes, err := hqgohttp.Get(getSubdomainsReqURL)
+		getSubdomainsRes, err := cfg.Client().Get(getSubdomainsReqURL)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getSubdomainsRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getSubdomainsRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -49,6 +60,8 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 
 			results <- result
 
+			getSubdomainsRes.Body.Close()
+
 			return
 		}
 
@@ -58,7 +71,7 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getSubdomainsRes, err),
 			}
 
 			results <- result
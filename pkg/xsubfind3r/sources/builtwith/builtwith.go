@@ -11,7 +11,6 @@ package builtwith
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 
 	hqgohttp "github.com/hueristiq/hq-go-http"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
@@ -66,7 +65,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -88,8 +87,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			},
 		}
 
-		getDomainInfoRes, err := hqgohttp.Get(getDomainInfoReqURL, getDomainInfoReqCFG)
+		getDomainInfoRes, err := cfg.Client().Get(getDomainInfoReqURL, getDomainInfoReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getDomainInfoRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getDomainInfoRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -98,6 +109,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getDomainInfoRes.Body.Close()
+
 			return
 		}
 
@@ -107,7 +120,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getDomainInfoRes, err),
 			}
 
 			results <- result
@@ -124,7 +137,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  fmt.Errorf("%w: %s", errStatic, entry.Message),
+					Error:  sources.NewError(sources.ErrInvalidResponse, source.Name(), errors.New(entry.Message)),
 				}
 
 				results <- result
@@ -157,7 +170,3 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 func (source *Source) Name() (name string) {
 	return sources.BUILTWITH
 }
-
-// errStatic is a sentinel error used to prepend error messages when the BuiltWith API response
-// contains error messages.
-var errStatic = errors.New("something went wrong")
@@ -11,7 +11,6 @@ package censys
 import (
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 
 	hqgohttp "github.com/hueristiq/hq-go-http"
@@ -88,7 +87,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -102,6 +101,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		certSearchReqURL := "https://search.censys.io/api/v2/certificates/search"
 
 		for {
+			if cfg.Stopped() {
+				return
+			}
+
 			certSearchReqCFG := &hqgohttp.RequestConfiguration{
 				Params: map[string]string{
 					"q":        domain,
@@ -116,8 +119,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				certSearchReqCFG.Params["cursor"] = cursor
 			}
 
-			certSearchRes, err := hqgohttp.Get(certSearchReqURL, certSearchReqCFG)
+			certSearchRes, err := cfg.Client().Get(certSearchReqURL, certSearchReqCFG)
 			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewRequestError(source.Name(), certSearchRes, err),
+				}
+
+				results <- result
+
+				return
+			}
+
+			if err = sources.CheckResponse(source.Name(), certSearchRes); err != nil {
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
@@ -126,6 +141,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 				results <- result
 
+				certSearchRes.Body.Close()
+
 				return
 			}
 
@@ -135,7 +152,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewParseError(source.Name(), certSearchRes, err),
 				}
 
 				results <- result
@@ -151,11 +168,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error: fmt.Errorf(
-						"%w: %s, %s",
-						errStatic,
-						certSearchResData.Status,
-						certSearchResData.Error,
+					Error: sources.NewError(
+						sources.ErrInvalidResponse,
+						source.Name(),
+						fmt.Errorf("%s, %s", certSearchResData.Status, certSearchResData.Error),
 					),
 				}
 
@@ -165,11 +181,27 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			}
 
 			for _, hit := range certSearchResData.Result.Hits {
+				expired := sources.Expired(sources.ParseTimestamp(hit.Parsed.ValidityPeriod.NotAfter))
+
+				if expired && cfg.ExcludeExpired {
+					continue
+				}
+
+				firstSeen, lastSeen := sources.Validity(hit.Parsed.ValidityPeriod.NotBefore, hit.Parsed.ValidityPeriod.NotAfter)
+
 				for _, name := range hit.Names {
 					result := sources.Result{
-						Type:   sources.ResultSubdomain,
-						Source: source.Name(),
-						Value:  name,
+						Type:      sources.ResultSubdomain,
+						Source:    source.Name(),
+						Value:     name,
+						FirstSeen: firstSeen,
+						LastSeen:  lastSeen,
+					}
+
+					if expired {
+						result.Metadata = map[string]string{
+							sources.MetadataExpired: "true",
+						}
 					}
 
 					results <- result
@@ -202,7 +234,3 @@ const (
 	maxCensysPages = 10
 	maxPerPage     = 100
 )
-
-// errStatic is a sentinel error used to prepend error messages when the Censys API response
-// contains error details.
-var errStatic = errors.New("something went wrong")
@@ -12,7 +12,6 @@ package certificatedetails
 import (
 	"bufio"
 
-	hqgohttp "github.com/hueristiq/hq-go-http"
 	hqgohttpstatus "github.com/hueristiq/hq-go-http/status"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
 )
@@ -41,8 +40,27 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 		getCertificateDetailsReqURL := "https://certificatedetails.com/" + domain
 
-		getCertificateDetailsRes, err := hqgohttp.Get(getCertificateDetailsReqURL)
-		if err != nil && getCertificateDetailsRes.StatusCode != hqgohttpstatus.NotFound.Int() {
+		getCertificateDetailsRes, err := cfg.Client().Get(getCertificateDetailsReqURL)
+		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getCertificateDetailsRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		// Domains with no known certificates are not found.
+		if getCertificateDetailsRes.StatusCode == hqgohttpstatus.NotFound.Int() {
+			getCertificateDetailsRes.Body.Close()
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getCertificateDetailsRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -51,6 +69,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getCertificateDetailsRes.Body.Close()
+
 			return
 		}
 
@@ -80,7 +100,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewRequestError(source.Name(), getCertificateDetailsRes, err),
 			}
 
 			results <- result
@@ -24,8 +24,10 @@ import (
 //   - ID: A unique identifier for the certificate record.
 //   - DNSNames: A slice of strings representing the DNS names (subdomains) associated with the certificate.
 type getCTLogsSearchResponse struct {
-	ID       string   `json:"id"`
-	DNSNames []string `json:"dns_names"`
+	ID        string   `json:"id"`
+	DNSNames  []string `json:"dns_names"`
+	NotBefore string   `json:"not_before"`
+	NotAfter  string   `json:"not_after"`
 }
 
 // Source represents the Certspotter data source implementation.
@@ -55,7 +57,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -72,8 +74,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			},
 		}
 
-		getCTLogsSearchRes, err := hqgohttp.Get(getCTLogsSearchReqURL, getCTLogsSearchReqCFG)
+		getCTLogsSearchRes, err := cfg.Client().Get(getCTLogsSearchReqURL, getCTLogsSearchReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getCTLogsSearchRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getCTLogsSearchRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -82,6 +96,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getCTLogsSearchRes.Body.Close()
+
 			return
 		}
 
@@ -91,7 +107,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getCTLogsSearchRes, err),
 			}
 
 			results <- result
@@ -108,15 +124,31 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		}
 
 		for _, cert := range getCTLogsSearchResData {
+			expired := sources.Expired(sources.ParseTimestamp(cert.NotAfter))
+
+			if expired && cfg.ExcludeExpired {
+				continue
+			}
+
+			firstSeen, lastSeen := sources.Validity(cert.NotBefore, cert.NotAfter)
+
 			for _, subdomain := range cert.DNSNames {
 				if subdomain != domain && !strings.HasSuffix(subdomain, "."+domain) {
 					continue
 				}
 
 				result := sources.Result{
-					Type:   sources.ResultSubdomain,
-					Source: source.Name(),
-					Value:  subdomain,
+					Type:      sources.ResultSubdomain,
+					Source:    source.Name(),
+					Value:     subdomain,
+					FirstSeen: firstSeen,
+					LastSeen:  lastSeen,
+				}
+
+				if expired {
+					result.Metadata = map[string]string{
+						sources.MetadataExpired: "true",
+					}
 				}
 
 				results <- result
@@ -126,6 +158,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		id := getCTLogsSearchResData[len(getCTLogsSearchResData)-1].ID
 
 		for {
+			if cfg.Stopped() {
+				break
+			}
+
 			getCTLogsSearchReqURL := "https://api.certspotter.com/v1/issuances"
 			getCTLogsSearchReqCFG := &hqgohttp.RequestConfiguration{
 				Params: map[string]string{
@@ -139,8 +175,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				},
 			}
 
-			getCTLogsSearchRes, err := hqgohttp.Get(getCTLogsSearchReqURL, getCTLogsSearchReqCFG)
+			getCTLogsSearchRes, err := cfg.Client().Get(getCTLogsSearchReqURL, getCTLogsSearchReqCFG)
 			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewRequestError(source.Name(), getCTLogsSearchRes, err),
+				}
+
+				results <- result
+
+				break
+			}
+
+			if err = sources.CheckResponse(source.Name(), getCTLogsSearchRes); err != nil {
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
@@ -149,6 +197,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 				results <- result
 
+				getCTLogsSearchRes.Body.Close()
+
 				break
 			}
 
@@ -158,7 +208,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewParseError(source.Name(), getCTLogsSearchRes, err),
 				}
 
 				results <- result
@@ -175,15 +225,31 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			}
 
 			for _, cert := range getCTLogsSearchResData {
+				expired := sources.Expired(sources.ParseTimestamp(cert.NotAfter))
+
+				if expired && cfg.ExcludeExpired {
+					continue
+				}
+
+				firstSeen, lastSeen := sources.Validity(cert.NotBefore, cert.NotAfter)
+
 				for _, subdomain := range cert.DNSNames {
 					if subdomain != domain && !strings.HasSuffix(subdomain, "."+domain) {
 						continue
 					}
 
 					result := sources.Result{
-						Type:   sources.ResultSubdomain,
-						Source: source.Name(),
-						Value:  subdomain,
+						Type:      sources.ResultSubdomain,
+						Source:    source.Name(),
+						Value:     subdomain,
+						FirstSeen: firstSeen,
+						LastSeen:  lastSeen,
+					}
+
+					if expired {
+						result.Metadata = map[string]string{
+							sources.MetadataExpired: "true",
+						}
 					}
 
 					results <- result
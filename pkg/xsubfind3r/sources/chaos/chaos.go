@@ -55,7 +55,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -73,8 +73,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			},
 		}
 
-		getSubdomainsRes, err := hqgohttp.Get(getSubdomainsReqURL, getSubdomainsReqCFG)
+		getSubdomainsRes, err := cfg.Client().Get(getSubdomainsReqURL, getSubdomainsReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getSubdomainsRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getSubdomainsRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -83,6 +95,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getSubdomainsRes.Body.Close()
+
 			return
 		}
 
@@ -92,7 +106,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getSubdomainsRes, err),
 			}
 
 			results <- result
@@ -6,14 +6,13 @@
 // Source type that implements the Run and Name methods as specified by the sources.Source
 // interface. The Run method retrieves index metadata, filters for recent indexes, queries
 // the index for URLs matching the target domain, extracts subdomains using a provided regular
-// expression, and streams discovered subdomains or errors via a channel.
+// expression, and streams discovered subdomains, the archived URLs, or errors via a channel.
 package commoncrawl
 
 import (
 	"bufio"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -91,8 +90,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 		getIndexesReqURL := "https://index.commoncrawl.org/collinfo.json"
 
-		getIndexesRes, err := hqgohttp.Get(getIndexesReqURL)
+		getIndexesRes, err := cfg.Client().Get(getIndexesReqURL)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getIndexesRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getIndexesRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -101,6 +112,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getIndexesRes.Body.Close()
+
 			return
 		}
 
@@ -110,7 +123,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getIndexesRes, err),
 			}
 
 			results <- result
@@ -145,6 +158,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		}
 
 		for _, CCIndexAPI := range searchIndexes {
+			if cfg.Stopped() {
+				return
+			}
+
 			getPaginationReqCFG := &hqgohttp.RequestConfiguration{
 				Headers: []hqgohttp.Header{
 					hqgohttp.NewSetHeader(hqgohttpheader.Host.String(), "index.commoncrawl.org"),
@@ -157,8 +174,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				},
 			}
 
-			getPaginationRes, err := hqgohttp.Get(CCIndexAPI, getPaginationReqCFG)
+			getPaginationRes, err := cfg.Client().Get(CCIndexAPI, getPaginationReqCFG)
 			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewRequestError(source.Name(), getPaginationRes, err),
+				}
+
+				results <- result
+
+				continue
+			}
+
+			if err = sources.CheckResponse(source.Name(), getPaginationRes); err != nil {
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
@@ -167,6 +196,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 				results <- result
 
+				getPaginationRes.Body.Close()
+
 				continue
 			}
 
@@ -176,7 +207,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewParseError(source.Name(), getPaginationRes, err),
 				}
 
 				results <- result
@@ -193,6 +224,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			}
 
 			for page := range getPaginationResData.Pages {
+				if cfg.Stopped() {
+					return
+				}
+
 				getURLsReqCFG := &hqgohttp.RequestConfiguration{
 					Headers: []hqgohttp.Header{
 						hqgohttp.NewSetHeader(hqgohttpheader.Host.String(), "index.commoncrawl.org"),
@@ -205,8 +240,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 					},
 				}
 
-				getURLsRes, err := hqgohttp.Get(CCIndexAPI, getURLsReqCFG)
+				getURLsRes, err := cfg.Client().Get(CCIndexAPI, getURLsReqCFG)
 				if err != nil {
+					result := sources.Result{
+						Type:   sources.ResultError,
+						Source: source.Name(),
+						Error:  sources.NewRequestError(source.Name(), getURLsRes, err),
+					}
+
+					results <- result
+
+					continue
+				}
+
+				if err = sources.CheckResponse(source.Name(), getURLsRes); err != nil {
 					result := sources.Result{
 						Type:   sources.ResultError,
 						Source: source.Name(),
@@ -215,6 +262,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 					results <- result
 
+					getURLsRes.Body.Close()
+
 					continue
 				}
 
@@ -227,7 +276,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 						result := sources.Result{
 							Type:   sources.ResultError,
 							Source: source.Name(),
-							Error:  err,
+							Error:  sources.NewParseError(source.Name(), getURLsRes, err),
 						}
 
 						results <- result
@@ -239,7 +288,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 						result := sources.Result{
 							Type:   sources.ResultError,
 							Source: source.Name(),
-							Error:  fmt.Errorf("%w: %s", errStatic, getURLsResData.Error),
+							Error:  sources.NewError(sources.ErrInvalidResponse, source.Name(), errors.New(getURLsResData.Error)),
 						}
 
 						results <- result
@@ -247,6 +296,16 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 						continue
 					}
 
+					if cfg.Wants(sources.ResultURL) {
+						result := sources.Result{
+							Type:   sources.ResultURL,
+							Source: source.Name(),
+							Value:  getURLsResData.URL,
+						}
+
+						results <- result
+					}
+
 					subdomains := cfg.Extractor.FindAllString(getURLsResData.URL, -1)
 
 					for _, subdomain := range subdomains {
@@ -264,7 +323,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 					result := sources.Result{
 						Type:   sources.ResultError,
 						Source: source.Name(),
-						Error:  err,
+						Error:  sources.NewRequestError(source.Name(), getURLsRes, err),
 					}
 
 					results <- result
@@ -290,7 +349,3 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 func (source *Source) Name() (name string) {
 	return sources.COMMONCRAWL
 }
-
-// errStatic is a sentinel error used to prepend error messages when a
-// record-specific error is encountered in the Common Crawl responses.
-var errStatic = errors.New("something went wrong")
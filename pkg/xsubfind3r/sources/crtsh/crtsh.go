@@ -26,6 +26,8 @@ import (
 type getNameValuesResponse []struct {
 	ID        int    `json:"id"`
 	NameValue string `json:"name_value"`
+	NotBefore string `json:"not_before"`
+	NotAfter  string `json:"not_after"`
 }
 
 // Source represents the CRT.SH data source implementation.
@@ -44,7 +46,7 @@ type Source struct{}
 //   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
 //     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
 //     encountered during the operation.
-func (source *Source) Run(domain string, _ *sources.Configuration) <-chan sources.Result {
+func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
 	results := make(chan sources.Result)
 
 	go func() {
@@ -61,8 +63,24 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 			},
 		}
 
-		getNameValuesRes, err := hqgohttp.Get(getNameValuesReqURL, getNameValuesReqCFG)
+		if cfg.ExcludeExpired {
+			getNameValuesReqCFG.Params["exclude"] = "expired"
+		}
+
+		getNameValuesRes, err := cfg.Client().Get(getNameValuesReqURL, getNameValuesReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getNameValuesRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getNameValuesRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -71,6 +89,8 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 
 			results <- result
 
+			getNameValuesRes.Body.Close()
+
 			return
 		}
 
@@ -80,7 +100,7 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getNameValuesRes, err),
 			}
 
 			results <- result
@@ -95,15 +115,31 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 		for _, record := range getNameValuesResData {
 			subdomains := strings.Split(record.NameValue, "\n")
 
+			expired := sources.Expired(sources.ParseTimestamp(record.NotAfter))
+
+			if expired && cfg.ExcludeExpired {
+				continue
+			}
+
+			firstSeen, lastSeen := sources.Validity(record.NotBefore, record.NotAfter)
+
 			for _, subdomain := range subdomains {
 				if subdomain != domain && !strings.HasSuffix(subdomain, "."+domain) {
 					continue
 				}
 
 				result := sources.Result{
-					Type:   sources.ResultSubdomain,
-					Source: source.Name(),
-					Value:  subdomain,
+					Type:      sources.ResultSubdomain,
+					Source:    source.Name(),
+					Value:     subdomain,
+					FirstSeen: firstSeen,
+					LastSeen:  lastSeen,
+				}
+
+				if expired {
+					result.Metadata = map[string]string{
+						sources.MetadataExpired: "true",
+					}
 				}
 
 				results <- result
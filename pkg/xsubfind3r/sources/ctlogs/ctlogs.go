@@ -0,0 +1,347 @@
+// Package ctlogs provides an implementation of the sources.Source interface
+// that reads Certificate Transparency logs directly through the RFC 6962 API.
+//
+// Unlike the aggregator-backed sources (crtsh, certspotter, censys), this source talks
+// to the logs themselves: it fetches each log's signed tree head with get-sth, pages
+// through the requested range of tree indices with get-entries, parses the X.509 and
+// precertificate leaf entries, and streams the in-scope subject alternative names and
+// common names. Progress can be recorded per domain and log so that repeated runs only
+// fetch entries appended since the previous run.
+package ctlogs
+
+import (
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	hqgohttp "github.com/hueristiq/hq-go-http"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"github.com/spf13/cast"
+)
+
+// getSTHResponse represents the structure of the JSON response returned by a log's get-sth endpoint.
+//
+// It contains the following fields:
+//   - TreeSize: The number of entries in the log.
+//   - Timestamp: The time, in milliseconds since the epoch, at which the tree head was signed.
+//   - SHA256RootHash: The base64-encoded Merkle tree root hash.
+type getSTHResponse struct {
+	TreeSize       int64  `json:"tree_size"`
+	Timestamp      int64  `json:"timestamp"`
+	SHA256RootHash string `json:"sha256_root_hash"`
+}
+
+// getEntriesResponse represents the structure of the JSON response returned by a log's get-entries endpoint.
+//
+// It contains a slice of entries, each holding:
+//   - LeafInput: The MerkleTreeLeaf structure, base64-decoded.
+//   - ExtraData: The certificate chain accompanying the leaf, base64-decoded.
+type getEntriesResponse struct {
+	Entries []struct {
+		LeafInput []byte `json:"leaf_input"`
+		ExtraData []byte `json:"extra_data"`
+	} `json:"entries"`
+}
+
+// Source represents the Certificate Transparency log data source implementation.
+// It implements the sources.Source interface, providing functionality
+// for retrieving subdomains from RFC 6962 logs.
+type Source struct{}
+
+// Run initiates the process of reading the configured Certificate Transparency logs for a given domain.
+//
+// Parameters:
+//   - domain (string): The target domain for which to retrieve subdomains.
+//   - cfg (*sources.Configuration): The configuration instance containing the logs,
+//     index ranges and progress state used by the source.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+//     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
+//     encountered during the operation.
+func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
+	results := make(chan sources.Result)
+
+	go func() {
+		defer close(results)
+
+		if len(cfg.CTLogs.Logs) == 0 {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.Classify(source.Name(), errNoLogs),
+			}
+
+			results <- result
+
+			return
+		}
+
+		for _, log := range cfg.CTLogs.Logs {
+			if cfg.Stopped() {
+				return
+			}
+
+			if err := source.read(domain, log, cfg, results); err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.Classify(source.Name(), fmt.Errorf("%s: %w", log.URL, err)),
+				}
+
+				results <- result
+			}
+		}
+	}()
+
+	return results
+}
+
+// read fetches the configured range of entries from a single log, streaming in-scope
+// names to results and recording progress as batches complete, until the call is stopped.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//   - log (sources.CTLogConfiguration): The log to read.
+//   - cfg (*sources.Configuration): The configuration, holding the source settings.
+//   - results (chan sources.Result): The channel discovered subdomains are sent to.
+//
+// Returns:
+//   - err (error): An error if the log could not be read.
+func (source *Source) read(domain string, log sources.CTLogConfiguration, cfg *sources.Configuration, results chan sources.Result) (err error) {
+	base := strings.TrimSuffix(log.URL, "/")
+
+	var sth getSTHResponse
+
+	if err = get(cfg.Client(), base+"/ct/v1/get-sth", nil, &sth); err != nil {
+		return
+	}
+
+	if sth.TreeSize == 0 {
+		return
+	}
+
+	end := sth.TreeSize - 1
+
+	if log.End > 0 && log.End < end {
+		end = log.End
+	}
+
+	start := log.Start
+
+	resumed := false
+
+	if start == 0 && cfg.CTLogs.State != "" {
+		start, resumed, err = resume(cfg.CTLogs.State, domain, log.URL)
+		if err != nil {
+			return
+		}
+	}
+
+	if cfg.CTLogs.MaxEntries > 0 && end-start+1 > cfg.CTLogs.MaxEntries {
+		if resumed || log.Start > 0 {
+			end = start + cfg.CTLogs.MaxEntries - 1
+		} else {
+			start = end - cfg.CTLogs.MaxEntries + 1
+		}
+	}
+
+	batchSize := int64(cfg.CTLogs.BatchSize)
+
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	next := start
+
+	defer func() {
+		if cfg.CTLogs.State == "" || next == start {
+			return
+		}
+
+		if recordErr := record(cfg.CTLogs.State, domain, log.URL, next); recordErr != nil && err == nil {
+			err = recordErr
+		}
+	}()
+
+	for next <= end && !cfg.Stopped() {
+		last := min(next+batchSize-1, end)
+
+		var entries getEntriesResponse
+
+		err = get(cfg.Client(), base+"/ct/v1/get-entries", map[string]string{
+			"start": cast.ToString(next),
+			"end":   cast.ToString(last),
+		}, &entries)
+		if err != nil {
+			return
+		}
+
+		// Logs may return fewer entries than requested; an empty page means the
+		// log will not serve the rest of the range right now.
+		if len(entries.Entries) == 0 {
+			return
+		}
+
+		for _, entry := range entries.Entries {
+			certificate := parse(entry.LeafInput, entry.ExtraData)
+			if certificate == nil {
+				continue
+			}
+
+			expired := sources.Expired(certificate.NotAfter)
+
+			if expired && cfg.ExcludeExpired {
+				continue
+			}
+
+			lastSeen := certificate.NotAfter.UTC()
+
+			if !expired {
+				lastSeen = time.Now().UTC()
+			}
+
+			names := certificate.DNSNames
+
+			if certificate.Subject.CommonName != "" {
+				names = append(names, certificate.Subject.CommonName)
+			}
+
+			for _, name := range names {
+				name = strings.TrimPrefix(strings.ToLower(name), "*.")
+
+				if name != domain && !strings.HasSuffix(name, "."+domain) {
+					continue
+				}
+
+				result := sources.Result{
+					Type:      sources.ResultSubdomain,
+					Source:    source.Name(),
+					Value:     name,
+					FirstSeen: certificate.NotBefore.UTC(),
+					LastSeen:  lastSeen,
+				}
+
+				if expired {
+					result.Metadata = map[string]string{
+						sources.MetadataExpired: "true",
+					}
+				}
+
+				results <- result
+			}
+		}
+
+		next += int64(len(entries.Entries))
+	}
+
+	return
+}
+
+// parse parses a log entry and returns the logged certificate, or nil if the entry
+// cannot be parsed.
+//
+// A MerkleTreeLeaf starts with a version byte, a leaf type byte, an 8-byte timestamp and
+// a 2-byte entry type. X.509 entries carry the DER certificate in the leaf itself; for
+// precertificate entries the leaf only holds the TBSCertificate, so the full precertificate
+// is taken from the first element of the extra data instead.
+//
+// Parameters:
+//   - leaf ([]byte): The MerkleTreeLeaf bytes.
+//   - extra ([]byte): The extra data bytes.
+//
+// Returns:
+//   - certificate (*x509.Certificate): The logged certificate, or nil.
+func parse(leaf, extra []byte) (certificate *x509.Certificate) {
+	const header = 12
+
+	if len(leaf) < header || leaf[0] != 0 || leaf[1] != 0 {
+		return
+	}
+
+	var der []byte
+
+	switch binary.BigEndian.Uint16(leaf[10:header]) {
+	case entryTypeX509:
+		der = opaque24(leaf[header:])
+	case entryTypePrecert:
+		der = opaque24(extra)
+	}
+
+	if der == nil {
+		return
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		certificate = nil
+	}
+
+	return
+}
+
+// opaque24 returns the contents of a TLS opaque vector with a 24-bit length prefix,
+// or nil if data is too short to hold it.
+func opaque24(data []byte) []byte {
+	if len(data) < 3 {
+		return nil
+	}
+
+	length := int(data[0])<<16 | int(data[1])<<8 | int(data[2])
+
+	if len(data) < 3+length {
+		return nil
+	}
+
+	return data[3 : 3+length]
+}
+
+// get sends a GET request to URL, with client and the given query parameters, and
+// decodes the JSON response body into data.
+func get(client *hqgohttp.Client, URL string, params map[string]string, data interface{}) (err error) {
+	res, err := client.Get(URL, &hqgohttp.RequestConfiguration{
+		Params: params,
+	})
+	if err != nil {
+		err = sources.NewRequestError(sources.CTLOGS, res, err)
+
+		return
+	}
+
+	defer res.Body.Close()
+
+	if err = sources.CheckResponse(sources.CTLOGS, res); err != nil {
+		return
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(data); err != nil {
+		err = sources.NewParseError(sources.CTLOGS, res, err)
+	}
+
+	return
+}
+
+// Name returns the unique identifier for the data source.
+// This identifier is used for logging, debugging, and associating results with the correct data source.
+//
+// Returns:
+//   - name (string): The unique identifier for the data source.
+func (source *Source) Name() (name string) {
+	return sources.CTLOGS
+}
+
+const (
+	entryTypeX509    = 0
+	entryTypePrecert = 1
+
+	// defaultBatchSize is the number of entries requested per get-entries call when
+	// none is configured. Most logs cap responses at 256 or 1000 entries.
+	defaultBatchSize = 256
+)
+
+// errNoLogs is reported when the source is used with no logs configured.
+var errNoLogs = errors.New("no logs configured under ctlogs.logs")
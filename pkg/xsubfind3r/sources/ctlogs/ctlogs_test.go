@@ -0,0 +1,244 @@
+package ctlogs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// testLog is a local stand-in for an RFC 6962 log serving a fixed list of entries.
+type testLog struct {
+	mu       sync.Mutex
+	entries  [][2][]byte
+	requests []string
+}
+
+func (l *testLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+
+	defer l.mu.Unlock()
+
+	l.requests = append(l.requests, r.URL.Path+"?"+r.URL.RawQuery)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.URL.Path {
+	case "/ct/v1/get-sth":
+		_ = json.NewEncoder(w).Encode(getSTHResponse{TreeSize: int64(len(l.entries))})
+	case "/ct/v1/get-entries":
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		end, _ := strconv.Atoi(r.URL.Query().Get("end"))
+
+		var response getEntriesResponse
+
+		for i := start; i <= end && i < len(l.entries); i++ {
+			response.Entries = append(response.Entries, struct {
+				LeafInput []byte `json:"leaf_input"`
+				ExtraData []byte `json:"extra_data"`
+			}{LeafInput: l.entries[i][0], ExtraData: l.entries[i][1]})
+		}
+
+		_ = json.NewEncoder(w).Encode(response)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// certificate returns a self-signed DER certificate for names, valid until notAfter.
+func certificate(t *testing.T, cn string, names []string, notAfter time.Time) (der []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     names,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err = x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// leaf returns a MerkleTreeLeaf of the given entry type, followed by body.
+func leaf(entryType uint16, body []byte) (data []byte) {
+	data = make([]byte, 12)
+
+	binary.BigEndian.PutUint64(data[2:10], uint64(time.Now().UnixMilli()))
+	binary.BigEndian.PutUint16(data[10:12], entryType)
+
+	data = append(data, body...)
+
+	return
+}
+
+// opaque returns data prefixed with its 24-bit length.
+func opaque(data []byte) []byte {
+	return append([]byte{byte(len(data) >> 16), byte(len(data) >> 8), byte(len(data))}, data...)
+}
+
+func collect(domain string, cfg *sources.Configuration) (subdomains map[string]sources.Result, errs []error) {
+	subdomains = map[string]sources.Result{}
+
+	for result := range (&Source{}).Run(domain, cfg) {
+		switch result.Type {
+		case sources.ResultSubdomain:
+			subdomains[result.Value] = result
+		case sources.ResultError:
+			errs = append(errs, result.Error)
+		}
+	}
+
+	return
+}
+
+func TestRun(t *testing.T) {
+	valid := time.Now().Add(30 * 24 * time.Hour)
+	expired := time.Now().Add(-30 * 24 * time.Hour)
+
+	log := &testLog{
+		entries: [][2][]byte{
+			{leaf(entryTypeX509, opaque(certificate(t, "example.com", []string{"a.example.com", "*.b.example.com", "example.org"}, valid))), nil},
+			{leaf(entryTypePrecert, []byte("tbs")), opaque(certificate(t, "", []string{"c.example.com"}, expired))},
+			{[]byte("garbage"), nil},
+			{leaf(entryTypeX509, opaque(certificate(t, "", []string{"d.example.com"}, valid))), nil},
+		},
+	}
+
+	server := httptest.NewServer(log)
+
+	defer server.Close()
+
+	cfg := &sources.Configuration{
+		CTLogs: sources.CTLogsConfiguration{
+			Logs:      []sources.CTLogConfiguration{{URL: server.URL + "/"}},
+			State:     filepath.Join(t.TempDir(), "ctlogs.json"),
+			BatchSize: 2,
+		},
+	}
+
+	subdomains, errs := collect("example.com", cfg)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	names := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com", "example.com"}
+
+	for _, name := range names {
+		if _, ok := subdomains[name]; !ok {
+			t.Errorf("missing %s, got %v", name, subdomains)
+		}
+	}
+
+	if len(subdomains) != len(names) {
+		t.Errorf("got %d subdomains, want %d: %v", len(subdomains), len(names), subdomains)
+	}
+
+	if subdomains["c.example.com"].Metadata[sources.MetadataExpired] != "true" {
+		t.Errorf("c.example.com not flagged as expired")
+	}
+
+	if subdomains["a.example.com"].Metadata[sources.MetadataExpired] != "" {
+		t.Errorf("a.example.com flagged as expired")
+	}
+
+	if got := subdomains["c.example.com"].LastSeen; !got.Equal(expired.UTC().Truncate(time.Second)) {
+		t.Errorf("c.example.com last seen %v, want the end of validity %v", got, expired)
+	}
+
+	if !slices.Contains(log.requests, "/ct/v1/get-entries?end=3&start=2") {
+		t.Errorf("entries not fetched in batches: %v", log.requests)
+	}
+
+	// Progress is recorded, so a second run only asks for the tree head.
+	log.requests = nil
+
+	subdomains, _ = collect("example.com", cfg)
+	if len(subdomains) != 0 || len(log.requests) != 1 {
+		t.Errorf("resumed run fetched %v, found %v", log.requests, subdomains)
+	}
+
+	// Expired certificates are skipped when asked to.
+	cfg.CTLogs.State = ""
+	cfg.ExcludeExpired = true
+
+	subdomains, _ = collect("example.com", cfg)
+	if _, ok := subdomains["c.example.com"]; ok || len(subdomains) != len(names)-1 {
+		t.Errorf("expired certificate not excluded: %v", subdomains)
+	}
+}
+
+func TestRunMaxEntries(t *testing.T) {
+	valid := time.Now().Add(30 * 24 * time.Hour)
+
+	log := &testLog{}
+
+	for _, name := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		log.entries = append(log.entries, [2][]byte{leaf(entryTypeX509, opaque(certificate(t, "", []string{name}, valid))), nil})
+	}
+
+	server := httptest.NewServer(log)
+
+	defer server.Close()
+
+	cfg := &sources.Configuration{
+		CTLogs: sources.CTLogsConfiguration{
+			Logs:       []sources.CTLogConfiguration{{URL: server.URL}},
+			MaxEntries: 2,
+		},
+	}
+
+	// Without recorded progress, the most recent entries are fetched.
+	subdomains, _ := collect("example.com", cfg)
+	if _, ok := subdomains["a.example.com"]; ok || len(subdomains) != 2 {
+		t.Errorf("got %v, want the 2 most recent entries", subdomains)
+	}
+}
+
+func TestRunUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+
+	defer server.Close()
+
+	cfg := &sources.Configuration{
+		CTLogs: sources.CTLogsConfiguration{
+			Logs: []sources.CTLogConfiguration{{URL: server.URL}},
+		},
+	}
+
+	_, errs := collect("example.com", cfg)
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+}
+
+func TestRunNoLogs(t *testing.T) {
+	_, errs := collect("example.com", &sources.Configuration{})
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+}
@@ -0,0 +1,138 @@
+package ctlogs
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// progress maps a domain to the next tree index to fetch from each log, keyed by log URL.
+// Progress is tracked per domain because a log scanned for one domain has not been
+// scanned for any other.
+type progress map[string]map[string]int64
+
+// mutex serializes access to state files across concurrent runs.
+var mutex = &sync.Mutex{}
+
+// resume returns the next tree index to fetch from log for domain, as recorded in the
+// state file at path.
+//
+// Parameters:
+//   - path (string): The state file path.
+//   - domain (string): The target domain.
+//   - log (string): The log URL.
+//
+// Returns:
+//   - next (int64): The recorded next index, or zero if none is recorded.
+//   - ok (bool): Whether progress was recorded.
+//   - err (error): An error if the state file exists but could not be read.
+func resume(path, domain, log string) (next int64, ok bool, err error) {
+	mutex.Lock()
+
+	defer mutex.Unlock()
+
+	var p progress
+
+	p, err = load(path)
+	if err != nil {
+		return
+	}
+
+	next, ok = p[domain][log]
+
+	return
+}
+
+// record stores next as the next tree index to fetch from log for domain in the state
+// file at path.
+//
+// Parameters:
+//   - path (string): The state file path.
+//   - domain (string): The target domain.
+//   - log (string): The log URL.
+//   - next (int64): The next index to fetch.
+//
+// Returns:
+//   - err (error): An error if the state file could not be read or written.
+func record(path, domain, log string, next int64) (err error) {
+	mutex.Lock()
+
+	defer mutex.Unlock()
+
+	var p progress
+
+	p, err = load(path)
+	if err != nil {
+		return
+	}
+
+	if p[domain] == nil {
+		p[domain] = map[string]int64{}
+	}
+
+	p[domain][log] = next
+
+	err = save(path, p)
+
+	return
+}
+
+// load reads the state file at path. A missing file yields empty progress.
+func load(path string) (p progress, err error) {
+	p = progress{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+
+		return
+	}
+
+	err = json.Unmarshal(data, &p)
+
+	return
+}
+
+// save writes p to the state file at path, replacing it atomically so that an interrupted
+// run never leaves a truncated file behind.
+func save(path string, p progress) (err error) {
+	directory := filepath.Dir(path)
+
+	if err = os.MkdirAll(directory, 0o750); err != nil {
+		return
+	}
+
+	var data []byte
+
+	data, err = json.MarshalIndent(p, "", "    ")
+	if err != nil {
+		return
+	}
+
+	var file *os.File
+
+	file, err = os.CreateTemp(directory, filepath.Base(path)+".*")
+	if err != nil {
+		return
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+
+		return
+	}
+
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(file.Name(), path)
+
+	return
+}
@@ -0,0 +1,542 @@
+// Package dnssec provides an implementation of the sources.Source interface
+// that enumerates subdomains by walking the authenticated denial of existence
+// records of DNSSEC-signed zones.
+//
+// Zones signed with NSEC link every owner name to the next one in canonical order,
+// so the whole zone can be enumerated by following the chain from the apex back to
+// itself. Zones signed with NSEC3 only expose hashed owner names; this package collects
+// those hashes while probing candidate names and cracks them offline against a wordlist.
+// This package defines a Source type that implements the Run and Name methods as specified
+// by the sources.Source interface. The Run method detects the zone's DNSSEC mode, performs
+// the matching enumeration, and streams discovered subdomains or errors via a channel.
+package dnssec
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"github.com/miekg/dns"
+)
+
+// mode represents the DNSSEC denial of existence mechanism used by a zone.
+type mode int
+
+const (
+	modeUnsigned mode = iota
+	modeNSEC
+	modeNSEC3
+)
+
+// walker holds the per-run state shared by the zone-walking routines.
+//
+// Fields:
+//   - client (*dns.Client): The DNS client used to send queries.
+//   - servers ([]string): The name servers ("host:port") queried in round-robin order.
+//   - queries (int): The number of queries issued so far.
+//   - maxQueries (int): The upper bound on the number of queries.
+//   - ctx (context.Context): Abandons the walk once done.
+type walker struct {
+	client     *dns.Client
+	servers    []string
+	queries    int
+	maxQueries int
+	ctx        context.Context
+}
+
+// Source represents the DNSSEC zone-walking data source implementation.
+// It implements the sources.Source interface, providing functionality
+// for retrieving subdomains from NSEC and NSEC3 signed zones.
+type Source struct{}
+
+// Run initiates the process of walking the DNSSEC chain of a given domain.
+//
+// Parameters:
+//   - domain (string): The target domain for which to retrieve subdomains.
+//   - cfg (*sources.Configuration): The configuration instance containing the resolvers,
+//     wordlist and query budget used by the source.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+//     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
+//     encountered during the operation.
+func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
+	results := make(chan sources.Result)
+
+	go func() {
+		defer close(results)
+
+		servers, err := nameservers(domain, cfg.DNSSEC.Resolvers)
+		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.Classify(source.Name(), err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		w := &walker{
+			client: &dns.Client{
+				Timeout: 5 * time.Second,
+			},
+			servers:    servers,
+			maxQueries: cfg.DNSSEC.MaxQueries,
+			ctx:        cfg.Context,
+		}
+
+		if w.ctx == nil {
+			w.ctx = context.Background()
+		}
+
+		if w.maxQueries <= 0 {
+			w.maxQueries = defaultMaxQueries
+		}
+
+		apex := dns.Fqdn(strings.ToLower(domain))
+
+		m, param, err := w.detect(apex)
+		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.Classify(source.Name(), err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		emit := func(name string) {
+			name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+			if name == "" || strings.HasPrefix(name, "*") || strings.Contains(name, "\\") {
+				return
+			}
+
+			result := sources.Result{
+				Type:   sources.ResultSubdomain,
+				Source: source.Name(),
+				Value:  name,
+			}
+
+			results <- result
+		}
+
+		switch m {
+		case modeUnsigned:
+			return
+		case modeNSEC:
+			err = w.walkNSEC(apex, emit)
+		case modeNSEC3:
+			var candidates []string
+
+			candidates, err = loadCandidates(cfg.DNSSEC.Wordlist)
+			if err != nil {
+				break
+			}
+
+			err = w.walkNSEC3(apex, param, candidates, emit)
+		}
+
+		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.Classify(source.Name(), err),
+			}
+
+			results <- result
+		}
+	}()
+
+	return results
+}
+
+// detect determines the DNSSEC mode of the zone at apex.
+//
+// A zone publishing an NSEC3PARAM record at its apex is treated as NSEC3 signed; a zone
+// publishing DNSKEY records without NSEC3PARAM is treated as NSEC signed; any other zone
+// is treated as unsigned.
+//
+// Parameters:
+//   - apex (string): The fully qualified zone apex.
+//
+// Returns:
+//   - m (mode): The detected DNSSEC mode.
+//   - param (*dns.NSEC3PARAM): The NSEC3 parameters, set only when m is modeNSEC3.
+//   - err (error): An error if the zone could not be queried.
+func (w *walker) detect(apex string) (m mode, param *dns.NSEC3PARAM, err error) {
+	var res *dns.Msg
+
+	res, err = w.query(apex, dns.TypeNSEC3PARAM)
+	if err != nil {
+		return
+	}
+
+	for _, rr := range res.Answer {
+		if p, ok := rr.(*dns.NSEC3PARAM); ok {
+			m = modeNSEC3
+			param = p
+
+			return
+		}
+	}
+
+	res, err = w.query(apex, dns.TypeDNSKEY)
+	if err != nil {
+		return
+	}
+
+	for _, rr := range res.Answer {
+		if _, ok := rr.(*dns.DNSKEY); ok {
+			m = modeNSEC
+
+			return
+		}
+	}
+
+	m = modeUnsigned
+
+	return
+}
+
+// walkNSEC follows the NSEC chain starting at apex until it loops back to the apex,
+// reports a name that has already been visited, or the query budget is exhausted.
+//
+// Parameters:
+//   - apex (string): The fully qualified zone apex.
+//   - emit (func(string)): A callback invoked for every in-zone name found on the chain.
+//
+// Returns:
+//   - err (error): An error if a query fails.
+func (w *walker) walkNSEC(apex string, emit func(name string)) (err error) {
+	visited := map[string]struct{}{
+		apex: {},
+	}
+
+	current := apex
+
+	for w.queries < w.maxQueries {
+		var res *dns.Msg
+
+		res, err = w.query(current, dns.TypeNSEC)
+		if err != nil {
+			return
+		}
+
+		next := ""
+
+		for _, rr := range append(res.Answer, res.Ns...) {
+			nsec, ok := rr.(*dns.NSEC)
+			if !ok || !strings.EqualFold(nsec.Hdr.Name, current) {
+				continue
+			}
+
+			next = strings.ToLower(nsec.NextDomain)
+
+			break
+		}
+
+		if next == "" || !dns.IsSubDomain(apex, next) {
+			return
+		}
+
+		if _, ok := visited[next]; ok {
+			return
+		}
+
+		visited[next] = struct{}{}
+
+		emit(next)
+
+		current = next
+	}
+
+	return
+}
+
+// walkNSEC3 probes candidate names built from the wordlist, collecting the NSEC3 records
+// returned in denial of existence responses, and emits every candidate whose hash matches
+// a collected owner hash or that the zone answers for itself. Answers synthesized from a
+// wildcard do not count: in a wildcard zone every candidate is answered.
+//
+// Candidates whose hash is already covered by a collected NSEC3 record are known not to
+// exist and are not queried, so the number of queries shrinks as the chain is filled in.
+//
+// Parameters:
+//   - apex (string): The fully qualified zone apex.
+//   - param (*dns.NSEC3PARAM): The NSEC3 hashing parameters of the zone.
+//   - candidates ([]string): The labels to try.
+//   - emit (func(string)): A callback invoked for every recovered name.
+//
+// Returns:
+//   - err (error): An error if a query fails.
+func (w *walker) walkNSEC3(apex string, param *dns.NSEC3PARAM, candidates []string, emit func(name string)) (err error) {
+	hashes := map[string]struct{}{}
+	records := []*dns.NSEC3{}
+	found := map[string]struct{}{}
+
+	collect := func(res *dns.Msg) {
+		for _, rr := range res.Ns {
+			nsec3, ok := rr.(*dns.NSEC3)
+			if !ok {
+				continue
+			}
+
+			owner := strings.ToUpper(strings.SplitN(nsec3.Hdr.Name, ".", 2)[0])
+
+			if _, ok := hashes[owner]; ok {
+				continue
+			}
+
+			hashes[owner] = struct{}{}
+			records = append(records, nsec3)
+		}
+	}
+
+	report := func(name string) {
+		if _, ok := found[name]; ok {
+			return
+		}
+
+		found[name] = struct{}{}
+
+		emit(name)
+	}
+
+	for _, label := range candidates {
+		name := label + "." + apex
+
+		hash := dns.HashName(name, param.Hash, param.Iterations, param.Salt)
+		if hash == "" {
+			err = fmt.Errorf("%w: %d", errUnsupportedHash, param.Hash)
+
+			return
+		}
+
+		if _, ok := hashes[hash]; ok {
+			report(name)
+
+			continue
+		}
+
+		if covered(records, name) || w.queries >= w.maxQueries {
+			continue
+		}
+
+		var res *dns.Msg
+
+		res, err = w.query(name, dns.TypeA)
+		if err != nil {
+			return
+		}
+
+		if res.Rcode == dns.RcodeSuccess && owned(res, name) {
+			report(name)
+		}
+
+		collect(res)
+	}
+
+	// Candidates skipped once the query budget ran out can still be cracked offline
+	// against every hash collected along the way.
+	for _, label := range candidates {
+		name := label + "." + apex
+
+		if _, ok := hashes[dns.HashName(name, param.Hash, param.Iterations, param.Salt)]; ok {
+			report(name)
+		}
+	}
+
+	return
+}
+
+// query sends a DNSSEC-enabled query for name and qtype, retrying over TCP when the
+// UDP response is truncated and rotating through the configured name servers, until the
+// walk is abandoned.
+//
+// Parameters:
+//   - name (string): The fully qualified name to query.
+//   - qtype (uint16): The record type to query.
+//
+// Returns:
+//   - res (*dns.Msg): The response message.
+//   - err (error): An error if every name server failed to respond.
+func (w *walker) query(name string, qtype uint16) (res *dns.Msg, err error) {
+	req := &dns.Msg{}
+
+	req.SetQuestion(name, qtype)
+	req.SetEdns0(4096, true)
+
+	for range w.servers {
+		if err = w.ctx.Err(); err != nil {
+			return
+		}
+
+		server := w.servers[w.queries%len(w.servers)]
+
+		w.queries++
+
+		res, _, err = w.client.ExchangeContext(w.ctx, req, server)
+		if err == nil && res.Truncated {
+			tcp := &dns.Client{Net: "tcp", Timeout: w.client.Timeout}
+
+			res, _, err = tcp.ExchangeContext(w.ctx, req, server)
+		}
+
+		if err == nil {
+			return
+		}
+	}
+
+	return
+}
+
+// owned reports whether res answers name with records owned by name itself rather than
+// expanded from a wildcard: the RRSIG of such records counts as many labels as name,
+// while that of expanded records counts the labels of the wildcard, less the "*".
+func owned(res *dns.Msg, name string) bool {
+	labels := dns.CountLabel(name)
+
+	for _, rr := range res.Answer {
+		sig, ok := rr.(*dns.RRSIG)
+		if ok && strings.EqualFold(sig.Hdr.Name, name) && int(sig.Labels) == labels {
+			return true
+		}
+	}
+
+	return false
+}
+
+// covered reports whether any of the given NSEC3 records proves that name does not exist.
+func covered(records []*dns.NSEC3, name string) bool {
+	for _, record := range records {
+		if record.Cover(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nameservers returns the name servers to query for domain. Configured resolvers take
+// precedence; otherwise the authoritative name servers of domain are looked up.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//   - resolvers ([]string): The configured resolvers, "host" or "host:port".
+//
+// Returns:
+//   - servers ([]string): The name servers as "host:port".
+//   - err (error): An error if no name server could be determined.
+func nameservers(domain string, resolvers []string) (servers []string, err error) {
+	if len(resolvers) == 0 {
+		var records []*net.NS
+
+		records, err = net.LookupNS(domain)
+		if err != nil {
+			return
+		}
+
+		for _, record := range records {
+			resolvers = append(resolvers, strings.TrimSuffix(record.Host, "."))
+		}
+	}
+
+	for _, resolver := range resolvers {
+		if _, _, splitErr := net.SplitHostPort(resolver); splitErr != nil {
+			resolver = net.JoinHostPort(resolver, "53")
+		}
+
+		servers = append(servers, resolver)
+	}
+
+	if len(servers) == 0 {
+		err = errNoNameservers
+	}
+
+	return
+}
+
+// loadCandidates reads candidate labels from the wordlist at path, skipping blank lines
+// and comments. When path is empty, the built-in list of common labels is returned.
+//
+// Parameters:
+//   - path (string): The wordlist file path.
+//
+// Returns:
+//   - candidates ([]string): The lowercased candidate labels.
+//   - err (error): An error if the wordlist could not be read.
+func loadCandidates(path string) (candidates []string, err error) {
+	if path == "" {
+		candidates = defaultCandidates
+
+		return
+	}
+
+	var file *os.File
+
+	file, err = os.Open(path)
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		label := strings.ToLower(strings.TrimSpace(scanner.Text()))
+
+		if label == "" || strings.HasPrefix(label, "#") {
+			continue
+		}
+
+		candidates = append(candidates, strings.Trim(label, "."))
+	}
+
+	err = scanner.Err()
+
+	return
+}
+
+// Name returns the unique identifier for the data source.
+// This identifier is used for logging, debugging, and associating results with the correct data source.
+//
+// Returns:
+//   - name (string): The unique identifier for the data source.
+func (source *Source) Name() (name string) {
+	return sources.DNSSEC
+}
+
+// defaultMaxQueries is the query budget used when none is configured.
+const defaultMaxQueries = 5000
+
+var (
+	errNoNameservers   = errors.New("no name servers to query")
+	errUnsupportedHash = errors.New("unsupported NSEC3 hash algorithm")
+
+	// defaultCandidates is the built-in list of common labels used to crack NSEC3 hashes
+	// when no wordlist is configured.
+	defaultCandidates = []string{
+		"www", "mail", "webmail", "smtp", "pop", "imap", "mx", "ns", "ns1", "ns2", "dns",
+		"api", "app", "apps", "admin", "portal", "login", "auth", "sso", "id", "account",
+		"dev", "development", "staging", "stage", "test", "qa", "uat", "demo", "sandbox",
+		"beta", "prod", "production", "internal", "intranet", "corp", "vpn", "remote",
+		"git", "gitlab", "jenkins", "ci", "jira", "confluence", "wiki", "docs", "support",
+		"help", "status", "monitor", "grafana", "kibana", "cdn", "static", "assets", "img",
+		"images", "media", "files", "download", "uploads", "blog", "shop", "store", "m",
+		"mobile", "ftp", "db", "mysql", "sql", "backup", "old", "new", "secure", "cloud",
+	}
+)
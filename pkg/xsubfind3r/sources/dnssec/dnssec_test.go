@@ -0,0 +1,228 @@
+package dnssec
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"github.com/miekg/dns"
+)
+
+// zone is a signed zone answering from its owner names, in canonical order from the
+// apex, signed with NSEC unless nsec3 is set. Names matching no owner are answered from
+// the wildcard owner, if the zone has one, or denied.
+type zone struct {
+	apex   string
+	owners []string
+	nsec3  bool
+}
+
+// sig returns an RRSIG of the covered records of owner, signed with labels labels.
+func sig(owner string, covered uint16, labels int) (rr *dns.RRSIG) {
+	rr = &dns.RRSIG{
+		Hdr:         dns.RR_Header{Name: owner, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 300},
+		TypeCovered: covered,
+		Algorithm:   dns.ECDSAP256SHA256,
+		Labels:      uint8(labels),
+		SignerName:  "example.com.",
+	}
+
+	return
+}
+
+// chain returns the NSEC3 records of the zone, in hash order.
+func (z *zone) chain() (records []*dns.NSEC3) {
+	hashes := []string{}
+
+	for _, owner := range z.owners {
+		hashes = append(hashes, dns.HashName(owner, dns.SHA1, 0, ""))
+	}
+
+	sort.Strings(hashes)
+
+	for i, hash := range hashes {
+		records = append(records, &dns.NSEC3{
+			Hdr:        dns.RR_Header{Name: hash + "." + z.apex, Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: 300},
+			Hash:       dns.SHA1,
+			HashLength: 20,
+			NextDomain: hashes[(i+1)%len(hashes)],
+			TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG},
+		})
+	}
+
+	return
+}
+
+// next returns the owner following name, or the apex after the last.
+func (z *zone) next(name string) (next string) {
+	for i, owner := range z.owners {
+		if owner == name {
+			next = z.owners[(i+1)%len(z.owners)]
+		}
+	}
+
+	return
+}
+
+func (z *zone) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	res := &dns.Msg{}
+
+	res.SetReply(req)
+
+	question := req.Question[0]
+	name := strings.ToLower(question.Name)
+
+	switch question.Qtype {
+	case dns.TypeNSEC3PARAM:
+		if z.nsec3 {
+			res.Answer = append(res.Answer, &dns.NSEC3PARAM{
+				Hdr:  dns.RR_Header{Name: z.apex, Rrtype: dns.TypeNSEC3PARAM, Class: dns.ClassINET, Ttl: 300},
+				Hash: dns.SHA1,
+			})
+		}
+	case dns.TypeDNSKEY:
+		res.Answer = append(res.Answer, &dns.DNSKEY{
+			Hdr:       dns.RR_Header{Name: z.apex, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 300},
+			Flags:     257,
+			Protocol:  3,
+			Algorithm: dns.ECDSAP256SHA256,
+			PublicKey: "AAAA",
+		})
+	case dns.TypeNSEC:
+		res.Answer = append(res.Answer, &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+			NextDomain: z.next(name),
+			TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG, dns.TypeNSEC},
+		})
+	case dns.TypeA:
+		a := &dns.A{
+			Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+			A:   net.IPv4(192, 0, 2, 1),
+		}
+
+		switch {
+		case slices.Contains(z.owners, name):
+			res.Answer = append(res.Answer, a, sig(name, dns.TypeA, dns.CountLabel(name)))
+		case slices.Contains(z.owners, "*."+z.apex):
+			res.Answer = append(res.Answer, a, sig(name, dns.TypeA, dns.CountLabel(z.apex)))
+
+			// The expansion comes with the proof that name itself does not exist.
+			for _, record := range z.chain() {
+				if record.Cover(name) {
+					res.Ns = append(res.Ns, record)
+				}
+			}
+		default:
+			res.Rcode = dns.RcodeNameError
+
+			for _, record := range z.chain() {
+				if record.Cover(name) {
+					res.Ns = append(res.Ns, record)
+				}
+			}
+		}
+	}
+
+	_ = w.WriteMsg(res)
+}
+
+// serve serves z on a local UDP port, and returns its address.
+func serve(t *testing.T, z *zone) (address string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+
+	server := &dns.Server{PacketConn: conn, Handler: z, NotifyStartedFunc: func() { close(started) }}
+
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+
+	<-started
+
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+
+	address = conn.LocalAddr().String()
+
+	return
+}
+
+// find runs the source against the zone served at address, with candidates as the
+// wordlist, and returns the subdomains found.
+func find(t *testing.T, address string, candidates ...string) (found []string) {
+	t.Helper()
+
+	wordlist := filepath.Join(t.TempDir(), "wordlist")
+
+	if err := os.WriteFile(wordlist, []byte(strings.Join(candidates, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &sources.Configuration{
+		DNSSEC: sources.DNSSECConfiguration{
+			Resolvers: []string{address},
+			Wordlist:  wordlist,
+		},
+	}
+
+	for result := range (&Source{}).Run("example.com", cfg) {
+		if result.Type == sources.ResultError {
+			t.Fatal(result.Error)
+		}
+
+		found = append(found, result.Value)
+	}
+
+	slices.Sort(found)
+
+	return
+}
+
+func TestWalkNSEC(t *testing.T) {
+	address := serve(t, &zone{
+		apex:   "example.com.",
+		owners: []string{"example.com.", "*.example.com.", "a.example.com.", "b.example.com."},
+	})
+
+	if found := find(t, address); !slices.Equal(found, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("got %v, want the names of the chain but the wildcard", found)
+	}
+}
+
+func TestWalkNSEC3(t *testing.T) {
+	address := serve(t, &zone{
+		apex:   "example.com.",
+		owners: []string{"example.com.", "www.example.com.", "mail.example.com."},
+		nsec3:  true,
+	})
+
+	if found := find(t, address, "www", "dev", "mail"); !slices.Equal(found, []string{"mail.example.com", "www.example.com"}) {
+		t.Errorf("got %v, want the candidates of the zone", found)
+	}
+}
+
+// TestWalkNSEC3Wildcard checks that candidates answered from the wildcard of a zone are
+// not reported.
+func TestWalkNSEC3Wildcard(t *testing.T) {
+	address := serve(t, &zone{
+		apex:   "example.com.",
+		owners: []string{"example.com.", "*.example.com.", "www.example.com.", "mail.example.com."},
+		nsec3:  true,
+	})
+
+	if found := find(t, address, "www", "dev", "staging", "mail"); !slices.Equal(found, []string{"mail.example.com", "www.example.com"}) {
+		t.Errorf("got %v, want the candidates of the zone only", found)
+	}
+}
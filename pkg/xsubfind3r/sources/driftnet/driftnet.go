@@ -5,7 +5,8 @@
 // information, from which subdomains can be extracted. This package defines a Source type that
 // implements the Run and Name methods as specified by the sources.Source interface. The Run method
 // sends a query to the Driftnet API, processes the JSON response, extracts subdomains from observations
-// (from host data and subject certificate data), and streams discovered subdomains or errors via a channel.
+// (from host data and subject certificate data), and streams discovered subdomains, observed IP addresses
+// and ASNs, or errors via a channel.
 package driftnet
 
 import (
@@ -150,8 +151,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			},
 		}
 
-		getResultsRes, err := hqgohttp.Get(getResultsReqURL, getResultsReqCFG)
+		getResultsRes, err := cfg.Client().Get(getResultsReqURL, getResultsReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getResultsRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getResultsRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -160,6 +173,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getResultsRes.Body.Close()
+
 			return
 		}
 
@@ -169,7 +184,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getResultsRes, err),
 			}
 
 			results <- result
@@ -218,6 +233,38 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				}
 			}
 		}
+
+		if cfg.Wants(sources.ResultIP) {
+			for _, value := range getResultsResData.Observations.IP.Values {
+				for ip := range value {
+					result := sources.Result{
+						Type:   sources.ResultIP,
+						Source: source.Name(),
+						Value:  ip,
+					}
+
+					results <- result
+				}
+			}
+		}
+
+		if cfg.Wants(sources.ResultASN) {
+			for _, value := range getResultsResData.Observations.ASN.Values {
+				for asn := range value {
+					if !strings.HasPrefix(strings.ToUpper(asn), "AS") {
+						asn = "AS" + asn
+					}
+
+					result := sources.Result{
+						Type:   sources.ResultASN,
+						Source: source.Name(),
+						Value:  asn,
+					}
+
+					results <- result
+				}
+			}
+		}
 	}()
 
 	return results
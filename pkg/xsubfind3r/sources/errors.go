@@ -0,0 +1,381 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Error is the error carried by every ResultError result. It classifies the failure by
+// Kind, one of the Err* sentinels, and records where it happened, so that callers can
+// make decisions with errors.Is and errors.As:
+//
+//	if errors.Is(result.Error, sources.ErrRateLimited) {
+//		// back off
+//	}
+//
+//	var sourceErr *sources.Error
+//
+//	if errors.As(result.Error, &sourceErr) {
+//		fmt.Println(sourceErr.StatusCode, sourceErr.URL)
+//	}
+//
+// API keys are never exposed: URL holds the request URL with credentials redacted, and
+// the message returned by Error is redacted the same way.
+//
+// Fields:
+//   - Kind (error): The category of the failure, one of the Err* sentinels.
+//   - Source (string): The name of the source that failed.
+//   - StatusCode (int): The HTTP status code of the response, or 0 if there was none.
+//   - URL (string): The redacted request URL, or "" if unknown.
+//   - Err (error): The underlying error, if any.
+type Error struct {
+	Kind       error
+	Source     string
+	StatusCode int
+	URL        string
+	Err        error
+
+	secrets []string
+}
+
+// Error returns a description of the failure with credentials redacted.
+func (e *Error) Error() (message string) {
+	message = e.Source + ": " + e.Kind.Error()
+
+	if e.StatusCode != 0 {
+		message += fmt.Sprintf(": status %d", e.StatusCode)
+	}
+
+	if e.URL != "" {
+		message += ": " + e.URL
+	}
+
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+
+	for _, secret := range e.secrets {
+		message = strings.ReplaceAll(message, secret, redacted)
+	}
+
+	return
+}
+
+// Unwrap returns the Kind and the underlying error, so that errors.Is and errors.As see
+// both.
+func (e *Error) Unwrap() (errs []error) {
+	errs = []error{e.Kind}
+
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+
+	return
+}
+
+// NewError returns an error of the given kind for source.
+//
+// Parameters:
+//   - kind (error): The category of the failure, one of the Err* sentinels.
+//   - source (string): The name of the source.
+//   - err (error): The underlying error, or nil.
+//
+// Returns:
+//   - e (*Error): The error.
+func NewError(kind error, source string, err error) (e *Error) {
+	e = &Error{
+		Kind:   kind,
+		Source: source,
+		Err:    err,
+	}
+
+	return
+}
+
+// NewMissingKeyError returns an ErrMissingKey error for source.
+//
+// Parameters:
+//   - source (string): The name of the source.
+//   - err (error): The error returned when picking a key, or nil.
+//
+// Returns:
+//   - e (*Error): The error.
+func NewMissingKeyError(source string, err error) (e *Error) {
+	e = NewError(ErrMissingKey, source, err)
+
+	return
+}
+
+// NewRequestError classifies an error returned while sending a request. Timeouts are
+// reported as ErrTimeout; if the request failed after a response was received, e.g. when
+// retries were exhausted, the response status decides the kind; anything else is
+// reported as ErrRequest.
+//
+// Parameters:
+//   - source (string): The name of the source.
+//   - res (*http.Response): The response, or nil.
+//   - err (error): The error returned by the request.
+//
+// Returns:
+//   - e (*Error): The error.
+func NewRequestError(source string, res *http.Response, err error) (e *Error) {
+	kind := ErrRequest
+
+	switch {
+	case isTimeout(err):
+		kind = ErrTimeout
+	case res != nil && !isSuccess(res.StatusCode):
+		kind = kindOf(res.StatusCode)
+	}
+
+	e = NewError(kind, source, err)
+
+	e.withResponse(res)
+
+	if e.URL == "" {
+		var URLError *url.Error
+
+		if errors.As(err, &URLError) {
+			e.withURL(URLError.URL)
+		}
+	}
+
+	return
+}
+
+// CheckResponse reports an error if res does not have a 2xx status. The kind of the
+// error is derived from the status:
+//
+//   - 401 and 403 are reported as ErrAuth.
+//   - 402 is reported as ErrQuotaExhausted.
+//   - 429 is reported as ErrRateLimited.
+//   - 5xx are reported as ErrUpstream.
+//   - Any other status is reported as ErrInvalidResponse.
+//
+// Parameters:
+//   - source (string): The name of the source.
+//   - res (*http.Response): The response to check.
+//
+// Returns:
+//   - err (error): The error, or nil if the status is 2xx.
+func CheckResponse(source string, res *http.Response) (err error) {
+	if isSuccess(res.StatusCode) {
+		return
+	}
+
+	e := NewError(kindOf(res.StatusCode), source, nil)
+
+	e.withResponse(res)
+
+	err = e
+
+	return
+}
+
+// NewParseError returns an ErrParse error for a response body that could not be decoded.
+//
+// Parameters:
+//   - source (string): The name of the source.
+//   - res (*http.Response): The response whose body failed to decode, or nil.
+//   - err (error): The decoding error.
+//
+// Returns:
+//   - e (*Error): The error.
+func NewParseError(source string, res *http.Response, err error) (e *Error) {
+	e = NewError(ErrParse, source, err)
+
+	e.withResponse(res)
+
+	return
+}
+
+// Classify returns err as an *Error attributed to source. Errors that already are an
+// *Error are returned unchanged; timeouts become ErrTimeout, and any other error, as
+// well as a nil error, becomes ErrUnclassified.
+//
+// Parameters:
+//   - source (string): The name of the source.
+//   - err (error): The error to classify.
+//
+// Returns:
+//   - classified (error): The classified error.
+func Classify(source string, err error) (classified error) {
+	var e *Error
+
+	if errors.As(err, &e) {
+		classified = err
+
+		return
+	}
+
+	kind := ErrUnclassified
+
+	if isTimeout(err) {
+		kind = ErrTimeout
+	}
+
+	classified = NewError(kind, source, err)
+
+	return
+}
+
+// KindOf returns the category of err: the Kind of the *Error it wraps, or
+// ErrUnclassified if it wraps none.
+//
+// Parameters:
+//   - err (error): The error.
+//
+// Returns:
+//   - kind (error): One of the Err* sentinels.
+func KindOf(err error) (kind error) {
+	var e *Error
+
+	if errors.As(err, &e) && e.Kind != nil {
+		kind = e.Kind
+
+		return
+	}
+
+	kind = ErrUnclassified
+
+	return
+}
+
+// withResponse records the status code and redacted URL of res, if any.
+func (e *Error) withResponse(res *http.Response) {
+	if res == nil {
+		return
+	}
+
+	e.StatusCode = res.StatusCode
+
+	if res.Request != nil && res.Request.URL != nil {
+		e.withURL(res.Request.URL.String())
+	}
+}
+
+// withURL records the redacted form of rawURL and remembers the credentials it contains,
+// so they can be redacted from the underlying error's message too.
+func (e *Error) withURL(rawURL string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+
+	if parsed.User != nil {
+		if password, ok := parsed.User.Password(); ok {
+			e.secrets = append(e.secrets, password)
+		}
+
+		parsed.User = nil
+	}
+
+	query := parsed.Query()
+
+	for name, values := range query {
+		if !isSecretParameter(name) {
+			continue
+		}
+
+		for i, value := range values {
+			if value != "" {
+				e.secrets = append(e.secrets, value, url.QueryEscape(value))
+			}
+
+			values[i] = redacted
+		}
+	}
+
+	parsed.RawQuery = query.Encode()
+
+	e.URL = parsed.String()
+}
+
+// isSecretParameter reports whether the query parameter name carries credentials.
+func isSecretParameter(name string) (ok bool) {
+	switch strings.ToLower(name) {
+	case "k", "key", "apikey", "api_key", "api-key", "token", "access_token", "secret", "password":
+		ok = true
+	}
+
+	return
+}
+
+// isTimeout reports whether err is a timeout.
+func isTimeout(err error) (ok bool) {
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		ok = true
+
+		return
+	}
+
+	var netErr net.Error
+
+	ok = errors.As(err, &netErr) && netErr.Timeout()
+
+	return
+}
+
+// isSuccess reports whether status is a 2xx status code.
+func isSuccess(status int) (ok bool) {
+	ok = status >= http.StatusOK && status < http.StatusMultipleChoices
+
+	return
+}
+
+// kindOf returns the category of a non-2xx status code.
+func kindOf(status int) (kind error) {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		kind = ErrAuth
+	case status == http.StatusPaymentRequired:
+		kind = ErrQuotaExhausted
+	case status == http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	case status >= http.StatusInternalServerError:
+		kind = ErrUpstream
+	default:
+		kind = ErrInvalidResponse
+	}
+
+	return
+}
+
+// redacted replaces credentials in URLs and error messages.
+const redacted = "REDACTED"
+
+// Sentinel errors classifying the failures reported by sources. Every ResultError
+// result carries an *Error whose Kind is one of them.
+var (
+	// ErrMissingKey is reported when a source requires an API key and none is configured.
+	ErrMissingKey = errors.New("missing API key")
+	// ErrAuth is reported when the API rejects the key (HTTP 401 or 403).
+	ErrAuth = errors.New("authentication failed")
+	// ErrQuotaExhausted is reported when the key's quota or credits are used up.
+	ErrQuotaExhausted = errors.New("quota exhausted")
+	// ErrRateLimited is reported when the API throttles requests (HTTP 429).
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstream is reported when the API fails on its side (HTTP 5xx).
+	ErrUpstream = errors.New("upstream error")
+	// ErrTimeout is reported when a request or query times out.
+	ErrTimeout = errors.New("timeout")
+	// ErrParse is reported when a response cannot be decoded.
+	ErrParse = errors.New("parse failure")
+	// ErrInvalidResponse is reported when a response is well-formed but unusable, e.g. an
+	// unexpected status or an error message in the payload.
+	ErrInvalidResponse = errors.New("invalid response")
+	// ErrRequest is reported when a request cannot be sent, e.g. on connection failures.
+	ErrRequest = errors.New("request failed")
+	// ErrUnclassified is reported for failures that fit no other category.
+	ErrUnclassified = errors.New("unclassified error")
+)
@@ -67,7 +67,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			return
 		}
 
-		tokens := NewTokenManager(cfg.Keys.GitHub)
+		tokens := SharedTokenManager(cfg.Keys.GitHub)
 
 		searchReqURL := fmt.Sprintf(
 			"https://api.github.com/search/code?per_page=100&q=%q&sort=created&order=asc",
@@ -89,13 +89,18 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 //   - cfg (*sources.Configuration): The configuration settings used for authentication and regex extraction.
 //   - results (chan sources.Result): A channel to stream discovered subdomains or errors.
 func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *sources.Configuration, results chan sources.Result) {
+	if cfg.Stopped() {
+		return
+	}
+
 	token := tokens.Get()
 
+	// Every token is exceeded: wait for the first to reset.
 	if token.RetryAfter > 0 {
-		if len(tokens.pool) == 1 {
-			time.Sleep(time.Duration(token.RetryAfter) * time.Second)
-		} else {
-			token = tokens.Get()
+		time.Sleep(time.Duration(token.RetryAfter) * time.Second)
+
+		if cfg.Stopped() {
+			return
 		}
 	}
 
@@ -106,7 +111,7 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 		},
 	}
 
-	codeSearchRes, err := hqgohttp.Get(searchReqURL, codeSearchResCFG)
+	codeSearchRes, err := cfg.Client().Get(searchReqURL, codeSearchResCFG)
 
 	isForbidden := codeSearchRes != nil && codeSearchRes.StatusCode == hqgohttpstatus.Forbidden.Int()
 
@@ -114,7 +119,7 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 		result := sources.Result{
 			Type:   sources.ResultError,
 			Source: source.Name(),
-			Error:  err,
+			Error:  sources.NewRequestError(source.Name(), codeSearchRes, err),
 		}
 
 		results <- result
@@ -128,9 +133,27 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 	if isForbidden && ratelimitRemaining == 0 {
 		retryAfterSeconds := cast.ToInt64(codeSearchRes.Header.Get(hqgohttpheader.RetryAfter.String()))
 
-		tokens.setCurrentTokenExceeded(retryAfterSeconds)
+		tokens.setExceeded(token.Hash, retryAfterSeconds)
+
+		codeSearchRes.Body.Close()
 
 		source.Enumerate(searchReqURL, tokens, cfg, results)
+
+		return
+	}
+
+	if err = sources.CheckResponse(source.Name(), codeSearchRes); err != nil {
+		result := sources.Result{
+			Type:   sources.ResultError,
+			Source: source.Name(),
+			Error:  err,
+		}
+
+		results <- result
+
+		codeSearchRes.Body.Close()
+
+		return
 	}
 
 	var codeSearchResData codeSearchResponse
@@ -139,7 +162,7 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 		result := sources.Result{
 			Type:   sources.ResultError,
 			Source: source.Name(),
-			Error:  err,
+			Error:  sources.NewParseError(source.Name(), codeSearchRes, err),
 		}
 
 		results <- result
@@ -152,6 +175,10 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 	codeSearchRes.Body.Close()
 
 	for _, item := range codeSearchResData.Items {
+		if cfg.Stopped() {
+			return
+		}
+
 		getRawContentReqURL := strings.ReplaceAll(
 			item.HTMLURL,
 			"https://github.com/",
@@ -161,12 +188,12 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 
 		var getRawContentRes *http.Response
 
-		getRawContentRes, err = hqgohttp.Get(getRawContentReqURL)
+		getRawContentRes, err = cfg.Client().Get(getRawContentReqURL)
 		if err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewRequestError(source.Name(), getRawContentRes, err),
 			}
 
 			results <- result
@@ -175,6 +202,8 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 		}
 
 		if getRawContentRes.StatusCode != hqgohttpstatus.OK.Int() {
+			getRawContentRes.Body.Close()
+
 			continue
 		}
 
@@ -203,7 +232,7 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewRequestError(source.Name(), getRawContentRes, err),
 			}
 
 			results <- result
@@ -239,7 +268,7 @@ func (source *Source) Enumerate(searchReqURL string, tokens *Tokens, cfg *source
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewError(sources.ErrInvalidResponse, source.Name(), err),
 				}
 
 				results <- result
@@ -1,6 +1,10 @@
 package github
 
-import "time"
+import (
+	"strings"
+	"sync"
+	"time"
+)
 
 type Token struct {
 	Hash         string
@@ -9,6 +13,7 @@ type Token struct {
 }
 
 type Tokens struct {
+	mutex   sync.Mutex
 	current int
 	pool    []Token
 }
@@ -27,28 +32,59 @@ func NewTokenManager(keys []string) *Tokens {
 	}
 }
 
-func (r *Tokens) setCurrentTokenExceeded(retryAfter int64) {
-	if r.current >= len(r.pool) {
-		r.current %= len(r.pool)
+// setExceeded marks the token hash as exceeded for retryAfter seconds. Tokens are marked
+// by value, not position: other runs sharing the manager may have moved on since the
+// token was handed out.
+func (r *Tokens) setExceeded(hash string, retryAfter int64) {
+	r.mutex.Lock()
+
+	defer r.mutex.Unlock()
+
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
 	}
 
-	if r.pool[r.current].RetryAfter == 0 {
-		r.pool[r.current].ExceededTime = time.Now()
-		r.pool[r.current].RetryAfter = retryAfter
+	for i := range r.pool {
+		if r.pool[i].Hash == hash && r.pool[i].RetryAfter == 0 {
+			r.pool[i].ExceededTime = time.Now()
+			r.pool[i].RetryAfter = retryAfter
+		}
 	}
 }
 
+// Get returns the next token that is not exceeded, in turn. If every token is exceeded,
+// the one resetting soonest is returned, with RetryAfter set to the seconds left until
+// it resets.
 func (r *Tokens) Get() *Token {
+	r.mutex.Lock()
+
+	defer r.mutex.Unlock()
+
 	resetExceededTokens(r)
 
-	if r.current >= len(r.pool) {
+	for range r.pool {
 		r.current %= len(r.pool)
+
+		token := r.pool[r.current]
+
+		r.current++
+
+		if token.RetryAfter == 0 {
+			return &token
+		}
 	}
 
-	result := &r.pool[r.current]
-	r.current++
+	var soonest *Token
 
-	return result
+	for _, token := range r.pool {
+		left := token.RetryAfter - int64(time.Since(token.ExceededTime)/time.Second)
+
+		if soonest == nil || left < soonest.RetryAfter {
+			soonest = &Token{Hash: token.Hash, ExceededTime: token.ExceededTime, RetryAfter: max(left, 1)}
+		}
+	}
+
+	return soonest
 }
 
 func resetExceededTokens(r *Tokens) {
@@ -61,3 +97,33 @@ func resetExceededTokens(r *Tokens) {
 		}
 	}
 }
+
+// SharedTokenManager returns the token manager for keys, creating it on first use.
+// Concurrent runs, e.g. for several domains at once, share one manager per key set,
+// so a token marked as exceeded by one run is skipped by all of them.
+func SharedTokenManager(keys []string) *Tokens {
+	managersMutex.Lock()
+
+	defer managersMutex.Unlock()
+
+	id := strings.Join(keys, "\x00")
+
+	if manager, ok := managers[id]; ok {
+		return manager
+	}
+
+	manager := NewTokenManager(keys)
+
+	managers[id] = manager
+
+	return manager
+}
+
+// defaultRetryAfter is how long, in seconds, a token is skipped once exceeded when the
+// response does not say.
+const defaultRetryAfter = 60
+
+var (
+	managers      = map[string]*Tokens{}
+	managersMutex = &sync.Mutex{}
+)
@@ -0,0 +1,41 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestTokensSkipExceeded(t *testing.T) {
+	tokens := NewTokenManager([]string{"a", "b", "c"})
+
+	first := tokens.Get()
+	second := tokens.Get()
+
+	// The token in use is marked, not the one the manager would hand out next.
+	tokens.setExceeded(first.Hash, 60)
+
+	for range 4 {
+		if token := tokens.Get(); token.Hash == first.Hash || token.RetryAfter != 0 {
+			t.Fatalf("got %+v, want a token other than the exceeded %q", token, first.Hash)
+		}
+	}
+
+	tokens.setExceeded(second.Hash, 60)
+
+	for range 2 {
+		if token := tokens.Get(); token.Hash != "c" {
+			t.Fatalf("got %q, want the only token left", token.Hash)
+		}
+	}
+}
+
+func TestTokensAllExceeded(t *testing.T) {
+	tokens := NewTokenManager([]string{"a", "b"})
+
+	tokens.setExceeded("a", 120)
+	tokens.setExceeded("b", 30)
+
+	token := tokens.Get()
+	if token.Hash != "b" || token.RetryAfter <= 0 || token.RetryAfter > 30 {
+		t.Fatalf("got %+v, want b, the token resetting soonest, with its wait", token)
+	}
+}
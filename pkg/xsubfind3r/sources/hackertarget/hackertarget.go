@@ -44,8 +44,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			},
 		}
 
-		hostSearchRes, err := hqgohttp.Get(hostSearchReqURL, hostSearchReqCFG)
+		hostSearchRes, err := cfg.Client().Get(hostSearchReqURL, hostSearchReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), hostSearchRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), hostSearchRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -54,6 +66,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			hostSearchRes.Body.Close()
+
 			return
 		}
 
@@ -83,7 +97,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewRequestError(source.Name(), hostSearchRes, err),
 			}
 
 			results <- result
@@ -0,0 +1,72 @@
+package imports
+
+import (
+	"encoding/json"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// fdnsRecord represents a single line of a Project Sonar FDNS dataset.
+//
+// It contains the following fields:
+//   - Timestamp: The collection time, in seconds since the epoch, as a string.
+//   - Name: The queried name.
+//   - Type: The record type (e.g., "a", "cname").
+//   - Value: The record data; a name for CNAME, NS, MX and PTR records.
+type fdnsRecord struct {
+	Timestamp string `json:"timestamp"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+}
+
+// FDNSSource represents the forward DNS dataset import source.
+// It implements the sources.Source interface, providing functionality
+// for reading subdomains from Project Sonar FDNS-style datasets, which are
+// usually distributed gzip-compressed.
+type FDNSSource struct{}
+
+// Run reads the configured FDNS datasets and streams the names in scope for a given domain.
+//
+// Parameters:
+//   - domain (string): The target domain for which to retrieve subdomains.
+//   - cfg (*sources.Configuration): The configuration instance containing the files to read
+//     and the Extractor used to select names in scope.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+//     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
+//     encountered during the operation.
+func (source *FDNSSource) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
+	results := make(chan sources.Result)
+
+	go func() {
+		defer close(results)
+
+		scan(source.Name(), domain, cfg.Imports.FDNS, cfg, results, func(path, line string) {
+			var record fdnsRecord
+
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				return
+			}
+
+			extract(source.Name(), path, record.Name, cfg, results)
+
+			switch record.Type {
+			case "cname", "ns", "mx", "ptr":
+				extract(source.Name(), path, record.Value, cfg, results)
+			}
+		})
+	}()
+
+	return results
+}
+
+// Name returns the unique identifier for the data source.
+// This identifier is used for logging, debugging, and associating results with the correct data source.
+//
+// Returns:
+//   - name (string): The unique identifier for the data source.
+func (source *FDNSSource) Name() (name string) {
+	return sources.FDNSFILE
+}
@@ -0,0 +1,44 @@
+package imports
+
+import (
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// HostsSource represents the plain host list import source.
+// It implements the sources.Source interface, providing functionality
+// for reading subdomains from files listing one name per line.
+type HostsSource struct{}
+
+// Run reads the configured host lists and streams the names in scope for a given domain.
+//
+// Parameters:
+//   - domain (string): The target domain for which to retrieve subdomains.
+//   - cfg (*sources.Configuration): The configuration instance containing the files to read
+//     and the Extractor used to select names in scope.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+//     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
+//     encountered during the operation.
+func (source *HostsSource) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
+	results := make(chan sources.Result)
+
+	go func() {
+		defer close(results)
+
+		scan(source.Name(), domain, cfg.Imports.Hosts, cfg, results, func(path, line string) {
+			extract(source.Name(), path, line, cfg, results)
+		})
+	}()
+
+	return results
+}
+
+// Name returns the unique identifier for the data source.
+// This identifier is used for logging, debugging, and associating results with the correct data source.
+//
+// Returns:
+//   - name (string): The unique identifier for the data source.
+func (source *HostsSource) Name() (name string) {
+	return sources.HOSTSFILE
+}
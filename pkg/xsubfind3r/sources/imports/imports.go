@@ -0,0 +1,189 @@
+// Package imports provides implementations of the sources.Source interface
+// that read subdomains from files produced outside of xsubfind3r.
+//
+// Teams often run other enumerators and keep passive DNS dumps or zone files around.
+// This package defines one Source type per supported file format:
+//
+//   - HostsSource reads plain host lists, one name per line.
+//   - JSONLSource reads JSON Lines output of other subdomain enumeration tools.
+//   - ZoneSource reads BIND zone files.
+//   - FDNSSource reads forward DNS datasets in the Project Sonar FDNS JSON Lines format.
+//
+// Every file may be plain or gzip-compressed. Names are extracted with the configuration's
+// Extractor, so only names in scope for the target domain are emitted, and every result
+// records the file it was read from as its origin.
+package imports
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// file is an opened import file, transparently decompressed when gzip-compressed.
+type file struct {
+	io.Reader
+
+	closers []io.Closer
+}
+
+// Close closes the decompressor, if any, and the underlying file.
+func (f *file) Close() (err error) {
+	for i := len(f.closers) - 1; i >= 0; i-- {
+		if closeErr := f.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return
+}
+
+// open opens the file at path, detecting gzip compression from its magic bytes rather
+// than its extension.
+//
+// Parameters:
+//   - path (string): The file path.
+//
+// Returns:
+//   - f (*file): The opened file.
+//   - err (error): An error if the file could not be opened or its gzip header is invalid.
+func open(path string) (f *file, err error) {
+	var raw *os.File
+
+	raw, err = os.Open(path)
+	if err != nil {
+		return
+	}
+
+	buffered := bufio.NewReader(raw)
+
+	f = &file{
+		Reader:  buffered,
+		closers: []io.Closer{raw},
+	}
+
+	magic, _ := buffered.Peek(2)
+
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		var decompressor *gzip.Reader
+
+		decompressor, err = gzip.NewReader(buffered)
+		if err != nil {
+			raw.Close()
+
+			f = nil
+
+			return
+		}
+
+		f.Reader = decompressor
+		f.closers = append(f.closers, decompressor)
+	}
+
+	return
+}
+
+// scan opens every file in paths and calls handle with each line that mentions domain,
+// sending an error result for every file that cannot be read, until the call is stopped.
+// Lines that do not contain the domain are skipped before any parsing, which keeps large
+// datasets cheap to scan.
+//
+// Parameters:
+//   - name (string): The name of the source, used for error results.
+//   - domain (string): The target domain.
+//   - paths ([]string): The files to read.
+//   - cfg (*sources.Configuration): The configuration of the call.
+//   - results (chan sources.Result): The channel error results are sent to.
+//   - handle (func(path, line string)): The callback invoked for every candidate line.
+func scan(name, domain string, paths []string, cfg *sources.Configuration, results chan sources.Result, handle func(path, line string)) {
+	domain = strings.ToLower(domain)
+
+	for _, path := range paths {
+		if cfg.Stopped() {
+			return
+		}
+
+		f, err := open(path)
+		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: name,
+				Error:  sources.Classify(name, err),
+			}
+
+			results <- result
+
+			continue
+		}
+
+		scanner := bufio.NewScanner(f)
+
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+		stopped := false
+
+		for lines := 0; scanner.Scan(); lines++ {
+			line := scanner.Text()
+
+			candidate := strings.Contains(strings.ToLower(line), domain)
+
+			// Whether the call is stopped is checked before every candidate line, and
+			// every stopCheckInterval lines among the others.
+			if (candidate || lines%stopCheckInterval == 0) && cfg.Stopped() {
+				stopped = true
+
+				break
+			}
+
+			if candidate {
+				handle(path, line)
+			}
+		}
+
+		if stopped {
+			f.Close()
+
+			return
+		}
+
+		if err = scanner.Err(); err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: name,
+				Error:  sources.Classify(name, err),
+			}
+
+			results <- result
+		}
+
+		f.Close()
+	}
+}
+
+// extract applies the configuration's Extractor to text and sends every match as a
+// subdomain result attributed to name, with path as its origin.
+func extract(name, path, text string, cfg *sources.Configuration, results chan sources.Result) {
+	for _, subdomain := range cfg.Extractor.FindAllString(text, -1) {
+		result := sources.Result{
+			Type:   sources.ResultSubdomain,
+			Source: name,
+			Value:  subdomain,
+			Origin: path,
+		}
+
+		results <- result
+	}
+}
+
+const (
+	// maxLineSize is the longest line accepted from an import file.
+	maxLineSize = 1024 * 1024
+
+	// stopCheckInterval is the number of lines scanned between checks of whether the call
+	// is stopped.
+	stopCheckInterval = 4096
+)
@@ -0,0 +1,77 @@
+package imports
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// stopped runs source over a file of lines, each naming another subdomain, stops the
+// call once the first result arrives, and returns the number of results sent in all.
+func stopped(t *testing.T, source sources.Source, line func(i int) string, imports func(path string) sources.ImportsConfiguration) (sent int) {
+	t.Helper()
+
+	data := &strings.Builder{}
+
+	for i := range 100000 {
+		fmt.Fprintln(data, line(i))
+	}
+
+	path := filepath.Join(t.TempDir(), "import")
+
+	if err := os.WriteFile(path, []byte(data.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	cfg := &sources.Configuration{
+		Imports:   imports(path),
+		Extractor: regexp.MustCompile(`(?i)(?:((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+))?(example\.com)`),
+		Context:   ctx,
+	}
+
+	for result := range source.Run("example.com", cfg) {
+		if result.Type == sources.ResultError {
+			t.Fatal(result.Error)
+		}
+
+		sent++
+
+		cancel()
+	}
+
+	return
+}
+
+func TestHostsStopped(t *testing.T) {
+	sent := stopped(t, &HostsSource{}, func(i int) string {
+		return fmt.Sprintf("127.0.0.1 h%d.example.com", i)
+	}, func(path string) sources.ImportsConfiguration {
+		return sources.ImportsConfiguration{Hosts: []string{path}}
+	})
+
+	if sent > 2 {
+		t.Errorf("got %d results once the call stopped, want the file abandoned", sent)
+	}
+}
+
+func TestZoneStopped(t *testing.T) {
+	sent := stopped(t, &ZoneSource{}, func(i int) string {
+		return fmt.Sprintf("h%d.example.com. 300 IN A 127.0.0.1", i)
+	}, func(path string) sources.ImportsConfiguration {
+		return sources.ImportsConfiguration{Zones: []string{path}}
+	})
+
+	if sent > 2 {
+		t.Errorf("got %d results once the call stopped, want the file abandoned", sent)
+	}
+}
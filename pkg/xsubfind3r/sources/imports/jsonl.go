@@ -0,0 +1,78 @@
+package imports
+
+import (
+	"encoding/json"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// JSONLSource represents the JSON Lines import source.
+// It implements the sources.Source interface, providing functionality
+// for reading subdomains from the JSON Lines output of other enumeration tools.
+//
+// Tools disagree on field names ("host", "name", "subdomain", ...), so every string value
+// of every object, at any depth, is passed through the Extractor. Lines that are not valid
+// JSON are treated as plain text.
+type JSONLSource struct{}
+
+// Run reads the configured JSON Lines files and streams the names in scope for a given domain.
+//
+// Parameters:
+//   - domain (string): The target domain for which to retrieve subdomains.
+//   - cfg (*sources.Configuration): The configuration instance containing the files to read
+//     and the Extractor used to select names in scope.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+//     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
+//     encountered during the operation.
+func (source *JSONLSource) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
+	results := make(chan sources.Result)
+
+	go func() {
+		defer close(results)
+
+		scan(source.Name(), domain, cfg.Imports.JSONL, cfg, results, func(path, line string) {
+			var record interface{}
+
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				extract(source.Name(), path, line, cfg, results)
+
+				return
+			}
+
+			for _, value := range stringValues(record) {
+				extract(source.Name(), path, value, cfg, results)
+			}
+		})
+	}()
+
+	return results
+}
+
+// stringValues returns every string value found in a decoded JSON value, at any depth.
+func stringValues(value interface{}) (values []string) {
+	switch v := value.(type) {
+	case string:
+		values = append(values, v)
+	case []interface{}:
+		for _, element := range v {
+			values = append(values, stringValues(element)...)
+		}
+	case map[string]interface{}:
+		for _, element := range v {
+			values = append(values, stringValues(element)...)
+		}
+	}
+
+	return
+}
+
+// Name returns the unique identifier for the data source.
+// This identifier is used for logging, debugging, and associating results with the correct data source.
+//
+// Returns:
+//   - name (string): The unique identifier for the data source.
+func (source *JSONLSource) Name() (name string) {
+	return sources.JSONLFILE
+}
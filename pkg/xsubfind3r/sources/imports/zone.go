@@ -0,0 +1,96 @@
+package imports
+
+import (
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+	"github.com/miekg/dns"
+)
+
+// ZoneSource represents the BIND zone file import source.
+// It implements the sources.Source interface, providing functionality
+// for reading subdomains from zone files.
+//
+// Zone files are parsed rather than scanned line by line, so relative owner names,
+// $ORIGIN and $TTL directives and multi-line records are resolved before extraction.
+// Both owner names and names in record data (CNAME, MX, NS and SRV targets) are emitted.
+type ZoneSource struct{}
+
+// Run reads the configured zone files and streams the names in scope for a given domain.
+// Files without an $ORIGIN directive are parsed with the target domain as their origin.
+//
+// Parameters:
+//   - domain (string): The target domain for which to retrieve subdomains.
+//   - cfg (*sources.Configuration): The configuration instance containing the files to read
+//     and the Extractor used to select names in scope.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+//     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
+//     encountered during the operation.
+func (source *ZoneSource) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
+	results := make(chan sources.Result)
+
+	go func() {
+		defer close(results)
+
+		for _, path := range cfg.Imports.Zones {
+			if cfg.Stopped() {
+				return
+			}
+
+			f, err := open(path)
+			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.Classify(source.Name(), err),
+				}
+
+				results <- result
+
+				continue
+			}
+
+			parser := dns.NewZoneParser(f, dns.Fqdn(domain), path)
+
+			for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+				if cfg.Stopped() {
+					f.Close()
+
+					return
+				}
+
+				// The SOA RNAME field is a mailbox, not a host.
+				if soa, isSOA := rr.(*dns.SOA); isSOA {
+					extract(source.Name(), path, soa.Hdr.Name+" "+soa.Ns, cfg, results)
+
+					continue
+				}
+
+				extract(source.Name(), path, rr.String(), cfg, results)
+			}
+
+			if err = parser.Err(); err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewParseError(source.Name(), nil, err),
+				}
+
+				results <- result
+			}
+
+			f.Close()
+		}
+	}()
+
+	return results
+}
+
+// Name returns the unique identifier for the data source.
+// This identifier is used for logging, debugging, and associating results with the correct data source.
+//
+// Returns:
+//   - name (string): The unique identifier for the data source.
+func (source *ZoneSource) Name() (name string) {
+	return sources.ZONEFILE
+}
@@ -12,6 +12,7 @@ package intelx
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -99,7 +100,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -108,17 +109,21 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		}
 
 		parts := strings.Split(key, ":")
-		if len(parts) != 2 {
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewMissingKeyError(source.Name(), errInvalidKey),
+			}
+
+			results <- result
+
 			return
 		}
 
 		intelXHost := parts[0]
 		intelXKey := parts[1]
 
-		if intelXKey == "" || intelXHost == "" {
-			return
-		}
-
 		searchReqURL := fmt.Sprintf("https://%s/phonebook/search?k=%s", intelXHost, intelXKey)
 		searchReqBody := searchRequestBody{
 			Term:       domain,
@@ -135,7 +140,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewError(sources.ErrUnclassified, source.Name(), err),
 			}
 
 			results <- result
@@ -153,8 +158,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 		var searchRes *http.Response
 
-		searchRes, err = hqgohttp.Post(searchReqURL, searchReqBodyReader, searchReqCFG)
+		searchRes, err = cfg.Client().Post(searchReqURL, searchReqBodyReader, searchReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), searchRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), searchRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -163,6 +180,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			searchRes.Body.Close()
+
 			return
 		}
 
@@ -172,7 +191,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), searchRes, err),
 			}
 
 			results <- result
@@ -195,10 +214,26 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		status := 0
 
 		for status == 0 || status == 3 {
+			if cfg.Stopped() {
+				return
+			}
+
 			var getResultsRes *http.Response
 
-			getResultsRes, err = hqgohttp.Get(getResultsReqURL, getResultsReqCFG)
+			getResultsRes, err = cfg.Client().Get(getResultsReqURL, getResultsReqCFG)
 			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewRequestError(source.Name(), getResultsRes, err),
+				}
+
+				results <- result
+
+				return
+			}
+
+			if err = sources.CheckResponse(source.Name(), getResultsRes); err != nil {
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
@@ -207,6 +242,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 				results <- result
 
+				getResultsRes.Body.Close()
+
 				return
 			}
 
@@ -216,7 +253,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewParseError(source.Name(), getResultsRes, err),
 				}
 
 				results <- result
@@ -253,3 +290,6 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 func (source *Source) Name() (name string) {
 	return sources.INTELLIGENCEX
 }
+
+// errInvalidKey is reported when a configured IntelX key is not in the "host:key" form.
+var errInvalidKey = errors.New("key must be in the form host:key")
@@ -10,11 +10,9 @@ package otx
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 
-	hqgohttp "github.com/hueristiq/hq-go-http"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
 )
 
@@ -29,6 +27,8 @@ type getPassiveDNSResponse struct {
 	Error      string `json:"error"`
 	PassiveDNS []struct {
 		Hostname string `json:"hostname"`
+		First    string `json:"first"`
+		Last     string `json:"last"`
 	} `json:"passive_dns"`
 }
 
@@ -41,14 +41,14 @@ type Source struct{}
 //
 // Parameters:
 //   - domain (string): The target domain for which to retrieve subdomains.
-//   - _ (*sources.Configuration): The configuration instance containing API keys,
+//   - cfg (*sources.Configuration): The configuration instance containing API keys,
 //     the URL validation function, and any additional settings required by the source.
 //
 // Returns:
 //   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
 //     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
 //     encountered during the operation.
-func (source *Source) Run(domain string, _ *sources.Configuration) <-chan sources.Result {
+func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
 	results := make(chan sources.Result)
 
 	go func() {
@@ -56,8 +56,20 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 
 		getPassiveDNSReqURL := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/passive_dns", domain)
 
-		getPassiveDNSRes, err := hqgohttp.Get(getPassiveDNSReqURL)
+		getPassiveDNSRes, err := cfg.Client().Get(getPassiveDNSReqURL)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getPassiveDNSRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getPassiveDNSRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -66,6 +78,8 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 
 			results <- result
 
+			getPassiveDNSRes.Body.Close()
+
 			return
 		}
 
@@ -75,7 +89,7 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getPassiveDNSRes, err),
 			}
 
 			results <- result
@@ -91,11 +105,10 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error: fmt.Errorf(
-					"%w: %s, %s",
-					errStatic,
-					getPassiveDNSResData.Detail,
-					getPassiveDNSResData.Error,
+				Error: sources.NewError(
+					sources.ErrInvalidResponse,
+					source.Name(),
+					fmt.Errorf("%s, %s", getPassiveDNSResData.Detail, getPassiveDNSResData.Error),
 				),
 			}
 
@@ -112,9 +125,11 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 			}
 
 			result := sources.Result{
-				Type:   sources.ResultSubdomain,
-				Source: source.Name(),
-				Value:  subdomain,
+				Type:      sources.ResultSubdomain,
+				Source:    source.Name(),
+				Value:     subdomain,
+				FirstSeen: sources.ParseTimestamp(record.First),
+				LastSeen:  sources.ParseTimestamp(record.Last),
 			}
 
 			results <- result
@@ -132,7 +147,3 @@ func (source *Source) Run(domain string, _ *sources.Configuration) <-chan source
 func (source *Source) Name() (name string) {
 	return sources.OPENTHREATEXCHANGE
 }
-
-// errStatic is a sentinel error used to prepend error messages when the OTX API response
-// contains error details.
-var errStatic = errors.New("something went wrong")
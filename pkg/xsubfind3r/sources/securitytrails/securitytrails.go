@@ -5,7 +5,8 @@
 // This package defines a Source type that implements the Run and Name methods as specified
 // by the sources.Source interface. The Run method sends a query to the SecurityTrails API,
 // processes the JSON response, extracts subdomains, and streams discovered subdomains or errors
-// via a channel.
+// via a channel. When DNS records or IP addresses are requested, it also streams the target
+// domain's current DNS records.
 package securitytrails
 
 import (
@@ -41,6 +42,24 @@ type getSubdomainsResponse struct {
 	Subdomains     []string `json:"subdomains"`
 }
 
+// getDomainResponse represents the structure of the JSON response returned by the SecurityTrails
+// API for a domain.
+//
+// It contains the following fields:
+//   - CurrentDNS: The domain's current DNS records, grouped by type. Each value carries
+//     the record data in the field matching its type.
+type getDomainResponse struct {
+	CurrentDNS map[string]struct {
+		Values []struct {
+			IP         string `json:"ip"`
+			IPv6       string `json:"ipv6"`
+			Hostname   string `json:"hostname"`
+			Nameserver string `json:"nameserver"`
+			Value      string `json:"value"`
+		} `json:"values"`
+	} `json:"current_dns"`
+}
+
 // Source represents the SecurityTrails data source implementation.
 // It implements the sources.Source interface, providing functionality
 // for retrieving subdomains from the SecurityTrails API.
@@ -68,7 +87,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -88,8 +107,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			},
 		}
 
-		getSubdomainsRes, err := hqgohttp.Get(getSubdomainsReqURL, getSubdomainsReqCFG)
+		getSubdomainsRes, err := cfg.Client().Get(getSubdomainsReqURL, getSubdomainsReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getSubdomainsRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getSubdomainsRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -98,6 +129,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getSubdomainsRes.Body.Close()
+
 			return
 		}
 
@@ -107,7 +140,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getSubdomainsRes, err),
 			}
 
 			results <- result
@@ -132,11 +165,125 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 		}
+
+		if cfg.Wants(sources.ResultDNSRecord) || cfg.Wants(sources.ResultIP) {
+			source.records(domain, key, cfg, results)
+		}
 	}()
 
 	return results
 }
 
+// records retrieves the current DNS records of domain and streams them as DNS record and
+// IP address results, as requested by the configuration.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//   - key (string): The API key to authenticate with.
+//   - cfg (*sources.Configuration): The configuration, consulted for the requested result types.
+//   - results (chan sources.Result): The channel results are sent to.
+func (source *Source) records(domain, key string, cfg *sources.Configuration, results chan sources.Result) {
+	getDomainReqURL := fmt.Sprintf("https://api.securitytrails.com/v1/domain/%s", domain)
+	getDomainReqCFG := &hqgohttp.RequestConfiguration{
+		Headers: []hqgohttp.Header{
+			hqgohttp.NewSetHeader(hqgohttpheader.Accept.String(), hqgohttpmime.JSON.String()),
+			hqgohttp.NewSetHeader("APIKEY", key),
+		},
+	}
+
+	getDomainRes, err := cfg.Client().Get(getDomainReqURL, getDomainReqCFG)
+	if err != nil {
+		result := sources.Result{
+			Type:   sources.ResultError,
+			Source: source.Name(),
+			Error:  sources.NewRequestError(source.Name(), getDomainRes, err),
+		}
+
+		results <- result
+
+		return
+	}
+
+	if err = sources.CheckResponse(source.Name(), getDomainRes); err != nil {
+		result := sources.Result{
+			Type:   sources.ResultError,
+			Source: source.Name(),
+			Error:  err,
+		}
+
+		results <- result
+
+		getDomainRes.Body.Close()
+
+		return
+	}
+
+	var getDomainResData getDomainResponse
+
+	if err = json.NewDecoder(getDomainRes.Body).Decode(&getDomainResData); err != nil {
+		result := sources.Result{
+			Type:   sources.ResultError,
+			Source: source.Name(),
+			Error:  sources.NewParseError(source.Name(), getDomainRes, err),
+		}
+
+		results <- result
+
+		getDomainRes.Body.Close()
+
+		return
+	}
+
+	getDomainRes.Body.Close()
+
+	for recordType, records := range getDomainResData.CurrentDNS {
+		recordType = strings.ToUpper(recordType)
+
+		for _, record := range records.Values {
+			var value string
+
+			for _, candidate := range []string{record.IP, record.IPv6, record.Hostname, record.Nameserver, record.Value} {
+				if candidate != "" {
+					value = candidate
+
+					break
+				}
+			}
+
+			if value == "" {
+				continue
+			}
+
+			if cfg.Wants(sources.ResultDNSRecord) {
+				result := sources.Result{
+					Type:   sources.ResultDNSRecord,
+					Source: source.Name(),
+					Value:  value,
+					Metadata: map[string]string{
+						sources.MetadataHost:       domain,
+						sources.MetadataRecordType: recordType,
+					},
+				}
+
+				results <- result
+			}
+
+			if cfg.Wants(sources.ResultIP) && (recordType == "A" || recordType == "AAAA") {
+				result := sources.Result{
+					Type:   sources.ResultIP,
+					Source: source.Name(),
+					Value:  value,
+					Metadata: map[string]string{
+						sources.MetadataHost: domain,
+					},
+				}
+
+				results <- result
+			}
+		}
+	}
+}
+
 // Name returns the unique identifier for the data source.
 // This identifier is used for logging, debugging, and associating results with the correct data source.
 //
@@ -0,0 +1,73 @@
+package sources
+
+import (
+	"time"
+)
+
+// ParseTimestamp parses a timestamp as reported by the sources' APIs. It accepts RFC 3339
+// timestamps, timestamps without a time zone (taken as UTC) in the "T"- or
+// space-separated form, plain dates and the 14-digit form used by the Wayback Machine.
+//
+// Parameters:
+//   - value (string): The timestamp to parse.
+//
+// Returns:
+//   - t (time.Time): The parsed time, or the zero time if value matches no known layout.
+func ParseTimestamp(value string) (t time.Time) {
+	for _, layout := range timestampLayouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			t = parsed.UTC()
+
+			return
+		}
+	}
+
+	return
+}
+
+// Validity converts a certificate's validity period into first-seen and last-seen times.
+// A name is taken to be seen from the start of the validity period until it ends or
+// until now, whichever is earlier, since a certificate that is still valid attests the
+// name today but not in the future.
+//
+// Parameters:
+//   - notBefore (string): The start of the validity period.
+//   - notAfter (string): The end of the validity period.
+//
+// Returns:
+//   - first (time.Time): The first-seen time, or the zero time if unknown.
+//   - last (time.Time): The last-seen time, or the zero time if unknown.
+func Validity(notBefore, notAfter string) (first, last time.Time) {
+	first = ParseTimestamp(notBefore)
+	last = ParseTimestamp(notAfter)
+
+	if now := time.Now().UTC(); last.After(now) {
+		last = now
+	}
+
+	return
+}
+
+// Expired reports whether a certificate valid until notAfter has expired. An unknown
+// (zero) notAfter is not considered expired.
+//
+// Parameters:
+//   - notAfter (time.Time): The end of the certificate's validity period.
+//
+// Returns:
+//   - expired (bool): Whether the certificate has expired.
+func Expired(notAfter time.Time) (expired bool) {
+	expired = !notAfter.IsZero() && notAfter.Before(time.Now())
+
+	return
+}
+
+// timestampLayouts are the layouts tried by ParseTimestamp, in order.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"20060102150405",
+}
@@ -2,9 +2,10 @@
 // for interacting with the Shodan API.
 //
 // The Shodan API offers DNS information for a given domain, including discovered
-// subdomains. This package defines a Source type that implements the Run and Name methods as specified
-// by the sources.Source interface. The Run method sends a query to the Shodan API, processes the JSON response,
-// and streams discovered subdomains or errors via a channel.
+// subdomains and their DNS records. This package defines a Source type that implements the Run and Name
+// methods as specified by the sources.Source interface. The Run method sends a query to the Shodan API,
+// processes the JSON response, and streams discovered subdomains, DNS records, IP addresses or errors
+// via a channel.
 package shodan
 
 import (
@@ -20,13 +21,20 @@ import (
 // It contains the following fields:
 //   - Domain: A string representing the target domain for which the DNS query was performed.
 //   - Subdomains: A slice of strings representing the discovered subdomains.
+//   - Data: The DNS records observed for the domain, each with the subdomain label it
+//     belongs to (empty for the domain itself), its type and its value.
 //   - Result: An integer indicating the status of the DNS query.
 //   - Error: A string containing error information if the request encountered an issue.
 type getDNSResponse struct {
 	Domain     string   `json:"domain"`
 	Subdomains []string `json:"subdomains"`
-	Result     int      `json:"result"`
-	Error      string   `json:"error"`
+	Data       []struct {
+		Subdomain string `json:"subdomain"`
+		Type      string `json:"type"`
+		Value     string `json:"value"`
+	} `json:"data"`
+	Result int    `json:"result"`
+	Error  string `json:"error"`
 }
 
 // Source represents the Shodan data source implementation.
@@ -56,7 +64,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -71,8 +79,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			},
 		}
 
-		getDNSRes, err := hqgohttp.Get(getDNSReqURL, getDNSReqCFG)
+		getDNSRes, err := cfg.Client().Get(getDNSReqURL, getDNSReqCFG)
 		if err != nil {
+			result := sources.Result{
+				Type:   sources.ResultError,
+				Source: source.Name(),
+				Error:  sources.NewRequestError(source.Name(), getDNSRes, err),
+			}
+
+			results <- result
+
+			return
+		}
+
+		if err = sources.CheckResponse(source.Name(), getDNSRes); err != nil {
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
@@ -81,6 +101,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			results <- result
 
+			getDNSRes.Body.Close()
+
 			return
 		}
 
@@ -90,7 +112,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewParseError(source.Name(), getDNSRes, err),
 			}
 
 			results <- result
@@ -102,6 +124,41 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 		getDNSRes.Body.Close()
 
+		// Records are sent before the subdomains they resolve, whether or not they are
+		// requested, so that they count towards their score.
+		for _, record := range getDNSResData.Data {
+			host := domain
+
+			if record.Subdomain != "" {
+				host = fmt.Sprintf("%s.%s", record.Subdomain, domain)
+			}
+
+			result := sources.Result{
+				Type:   sources.ResultDNSRecord,
+				Source: source.Name(),
+				Value:  record.Value,
+				Metadata: map[string]string{
+					sources.MetadataHost:       host,
+					sources.MetadataRecordType: record.Type,
+				},
+			}
+
+			results <- result
+
+			if record.Type == "A" || record.Type == "AAAA" {
+				result := sources.Result{
+					Type:   sources.ResultIP,
+					Source: source.Name(),
+					Value:  record.Value,
+					Metadata: map[string]string{
+						sources.MetadataHost: host,
+					},
+				}
+
+				results <- result
+			}
+		}
+
 		for _, subdomain := range getDNSResData.Subdomains {
 			result := sources.Result{
 				Type:   sources.ResultSubdomain,
@@ -13,11 +13,18 @@
 package sources
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
 	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	hqgohttp "github.com/hueristiq/hq-go-http"
 )
 
 // Source is the interface that every data source implementation must satisfy.
@@ -52,14 +59,101 @@ type Source interface {
 	Name() (name string)
 }
 
+// Enricher is implemented by sources that derive further results from hosts discovered
+// by other sources, rather than (only) from the target domain itself.
+//
+// When a source implements Enricher, the Finder calls Enrich instead of Run and feeds it
+// every unique subdomain as it is discovered, starting with the target domain. The hosts
+// channel is closed once every other source has finished.
+type Enricher interface {
+	Source
+
+	// Enrich consumes hosts until the channel is closed and streams the results derived
+	// from them. The returned channel must be closed once hosts is drained and all
+	// derived results have been sent.
+	//
+	// Parameters:
+	//   - domain (string): The target domain; derived names outside it must be dropped.
+	//   - hosts (<-chan string): The discovered hosts to derive results from.
+	//   - cfg (*Configuration): A pointer to a Configuration struct containing the settings
+	//          needed by the source.
+	//
+	// Returns:
+	//   - (<-chan Result): A read-only channel that asynchronously emits Result values.
+	Enrich(domain string, hosts <-chan string, cfg *Configuration) <-chan Result
+}
+
 // Configuration holds settings and parameters passed to each data source.
 //
 // Fields:
 //   - Keys (Keys): API credentials for different data sources.
 //   - Extractor (*regexp.Regexp): A compiled regular expression used to extract subdomains.
+//   - DNSSEC (DNSSECConfiguration): Settings for the DNSSEC zone-walking source.
+//   - CTLogs (CTLogsConfiguration): Settings for the Certificate Transparency log source.
+//   - TLS (TLSConfiguration): Settings for the TLS certificate harvesting source.
+//   - Imports (ImportsConfiguration): Files read by the import sources.
+//   - ResultTypes ([]ResultType): The result types requested in addition to subdomains and
+//     errors. Sources may skip work whose only product is a type not requested; see Wants.
+//   - ExcludeExpired (bool): Certificate-based sources skip certificates that have expired,
+//     instead of flagging their names with MetadataExpired.
+//   - Context (context.Context): Done once the call the source runs for is stopped, or nil
+//     if it is never stopped. Sources stop issuing requests once it is done; see Stopped.
+//   - HTTPClient (*hqgohttp.Client): The HTTP client sources send requests with, owned by
+//     the Finder; see Client.
 type Configuration struct {
-	Keys      Keys
-	Extractor *regexp.Regexp
+	Keys           Keys
+	Extractor      *regexp.Regexp
+	DNSSEC         DNSSECConfiguration
+	CTLogs         CTLogsConfiguration
+	TLS            TLSConfiguration
+	Imports        ImportsConfiguration
+	ResultTypes    []ResultType
+	ExcludeExpired bool
+	Context        context.Context
+	HTTPClient     *hqgohttp.Client
+}
+
+// Client returns the HTTP client sources send requests with: HTTPClient, or
+// hqgohttp.DefaultClient if it is not set, e.g. when a source is run on its own.
+//
+// Returns:
+//   - client (*hqgohttp.Client): The HTTP client.
+func (cfg *Configuration) Client() (client *hqgohttp.Client) {
+	client = cfg.HTTPClient
+
+	if client == nil {
+		client = hqgohttp.DefaultClient
+	}
+
+	return
+}
+
+// Stopped reports whether the call the source runs for has been stopped, in which case its
+// results are discarded and it should return rather than issue further requests.
+//
+// Returns:
+//   - stopped (bool): Whether the call has been stopped.
+func (cfg *Configuration) Stopped() (stopped bool) {
+	stopped = cfg.Context != nil && cfg.Context.Err() != nil
+
+	return
+}
+
+// Wants reports whether results of type t are requested. Subdomains and errors are always
+// requested. Sources skip the results that are not requested, except IP addresses and DNS
+// records of a host: they are evidence that the host resolves, which counts towards its
+// score, so they are always sent, before the subdomain they resolve, and the Finder
+// discards them once recorded.
+//
+// Parameters:
+//   - t (ResultType): The result type.
+//
+// Returns:
+//   - wanted (bool): Whether results of type t are requested.
+func (cfg *Configuration) Wants(t ResultType) (wanted bool) {
+	wanted = t == ResultSubdomain || t == ResultError || slices.Contains(cfg.ResultTypes, t)
+
+	return
 }
 
 // Keys stores API keys for different data sources. Each field represents a collection of API keys
@@ -81,6 +175,102 @@ type Keys struct {
 	VirusTotal     SourceKeys `yaml:"virustotal"`
 }
 
+// For returns the API keys of the named source.
+//
+// Parameters:
+//   - name (string): The name of the source, e.g. "shodan".
+//
+// Returns:
+//   - keys (SourceKeys): The API keys of the source.
+//   - ok (bool): Whether the source takes API keys at all.
+func (k *Keys) For(name string) (keys SourceKeys, ok bool) {
+	v := reflect.ValueOf(k).Elem()
+
+	for i := range v.NumField() {
+		if v.Type().Field(i).Tag.Get("yaml") != name {
+			continue
+		}
+
+		keys, ok = v.Field(i).Interface().(SourceKeys)
+
+		return
+	}
+
+	return
+}
+
+// DNSSECConfiguration holds settings for the DNSSEC zone-walking source.
+//
+// Fields:
+//   - Resolvers ([]string): Name servers ("host" or "host:port") to query. When empty, the
+//     authoritative name servers of the target domain are looked up and queried directly.
+//   - Wordlist (string): Path to a file of candidate labels, one per line, used to crack
+//     NSEC3 hashes. When empty, a small built-in list of common labels is used.
+//   - MaxQueries (int): Upper bound on the number of DNS queries issued per domain.
+type DNSSECConfiguration struct {
+	Resolvers  []string `yaml:"resolvers"`
+	Wordlist   string   `yaml:"wordlist"`
+	MaxQueries int      `yaml:"maxqueries"`
+}
+
+// CTLogsConfiguration holds settings for the Certificate Transparency log source.
+//
+// Fields:
+//   - Logs ([]CTLogConfiguration): The logs to read entries from.
+//   - State (string): Path to a file recording, per domain and log, the next entry index
+//     to fetch, so that repeated runs only fetch entries appended since the previous run.
+//     When empty, no progress is recorded.
+//   - BatchSize (int): The number of entries requested per get-entries call.
+//   - MaxEntries (int64): Upper bound on the number of entries fetched per log and run.
+//     When no progress is recorded and no start index is configured, the most recent
+//     MaxEntries entries are fetched.
+type CTLogsConfiguration struct {
+	Logs       []CTLogConfiguration `yaml:"logs"`
+	State      string               `yaml:"state"`
+	BatchSize  int                  `yaml:"batchsize"`
+	MaxEntries int64                `yaml:"maxentries"`
+}
+
+// CTLogConfiguration identifies a single RFC 6962 log and, optionally, the range of tree
+// indices to read from it.
+//
+// Fields:
+//   - URL (string): The log's base URL, e.g. "https://ct.googleapis.com/logs/us1/argon2026h1/".
+//   - Start (int64): The first tree index to fetch. Zero means "resume from the recorded progress".
+//   - End (int64): The last tree index to fetch. Zero means "up to the current tree size".
+type CTLogConfiguration struct {
+	URL   string `yaml:"url"`
+	Start int64  `yaml:"start"`
+	End   int64  `yaml:"end"`
+}
+
+// TLSConfiguration holds settings for the TLS certificate harvesting source.
+//
+// Fields:
+//   - Ports ([]int): The ports to connect to on every discovered host.
+//   - Timeout (int): The connection and handshake timeout, in seconds.
+//   - Concurrency (int): The maximum number of simultaneous connections.
+type TLSConfiguration struct {
+	Ports       []int `yaml:"ports"`
+	Timeout     int   `yaml:"timeout"`
+	Concurrency int   `yaml:"concurrency"`
+}
+
+// ImportsConfiguration lists the files read by the import sources. Each file may be plain
+// or gzip-compressed.
+//
+// Fields:
+//   - Hosts ([]string): Plain host lists, one name per line.
+//   - JSONL ([]string): JSON Lines output of other subdomain enumeration tools.
+//   - Zones ([]string): BIND zone files.
+//   - FDNS ([]string): Forward DNS datasets in the Project Sonar FDNS JSON Lines format.
+type ImportsConfiguration struct {
+	Hosts []string `yaml:"hosts"`
+	JSONL []string `yaml:"jsonl"`
+	Zones []string `yaml:"zones"`
+	FDNS  []string `yaml:"fdns"`
+}
+
 // SourceKeys is a slice of strings where each element represents an API key for a specific source.
 // This structure supports maintaining multiple keys for a single source, which is useful for key
 // rotation or providing fallback options if one key becomes invalid.
@@ -131,13 +321,31 @@ func (k SourceKeys) PickRandom() (key string, err error) {
 //   - Source (string): Identifies the source that produced this result (e.g., "crtsh", "shodan").
 //   - Value (string): Contains the actual subdomain retrieved from the source.
 //     This field is empty if the result is an error.
+//   - Origin (string): For results derived from an already discovered host (see Enricher),
+//     the host the result was derived from. Empty otherwise.
 //   - Error (error): Holds the error encountered during the operation, if any. If no error
-//     occurred, this field is nil.
+//     occurred, this field is nil. Errors wrap an *Error classifying the failure; see Error.
+//   - Metadata (map[string]string): Additional attributes of the result, keyed by the
+//     Metadata* constants or source-specific keys. Nil when there are none.
+//   - FirstSeen (time.Time): When the source first observed the value, e.g. the start of a
+//     certificate's validity period. The zero time if unknown.
+//   - LastSeen (time.Time): When the source last observed the value. The zero time if unknown.
+//   - Score (float64): The confidence score of a subdomain, between 0 and 1, set by the
+//     Finder from the evidence known when the subdomain is emitted. It is a snapshot:
+//     sources reporting the subdomain later do not raise it. Sources leave it zero.
+//   - Sources ([]string): The names of every source that had reported a subdomain when the
+//     Finder emitted it, sorted; a snapshot, like Score. Sources leave it nil.
 type Result struct {
-	Type   ResultType
-	Source string
-	Value  string
-	Error  error
+	Type      ResultType
+	Source    string
+	Value     string
+	Origin    string
+	Error     error
+	Metadata  map[string]string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Score     float64
+	Sources   []string
 }
 
 // ResultType defines the category of a Result using an integer enumeration.
@@ -146,6 +354,11 @@ type Result struct {
 // Enumeration Values:
 //   - ResultSubdomain: Indicates a successful result containing a subdomain retrieved from the source.
 //   - ResultError: Represents a result indicating that an error occurred during the operation.
+//   - ResultIP: Indicates an IP address associated with the target domain.
+//   - ResultURL: Indicates a URL on the target domain or one of its subdomains.
+//   - ResultDNSRecord: Indicates a DNS record of the target domain or one of its subdomains.
+//   - ResultASN: Indicates an autonomous system announcing addresses of the target domain.
+//   - ResultReport: Indicates that a further source reported a subdomain already emitted.
 type ResultType int
 
 // Constants representing the types of results that can be produced by a data source.
@@ -154,9 +367,81 @@ type ResultType int
 //   - ResultSubdomain: Represents a successful result containing subdomain.
 //   - ResultError: Indicates an error encountered during the operation, with details
 //     provided in the `Error` field of the `Result`.
+//   - ResultIP: Value holds an IPv4 or IPv6 address; MetadataHost, if set, the host it
+//     belongs to.
+//   - ResultURL: Value holds an absolute URL; MetadataHost, if set, its host.
+//   - ResultDNSRecord: Value holds the record data, e.g. an address or a CNAME target;
+//     MetadataHost holds the owner name and MetadataRecordType the record type, e.g. "A".
+//   - ResultASN: Value holds the AS number in the "AS<number>" form; MetadataASName, if
+//     set, the AS name and MetadataHost the host it was observed for.
+//   - ResultReport: Value holds a subdomain emitted earlier in the call and Source a source
+//     that reported it afterwards, for the first time, with the times it reported. Only
+//     sent by the Finder when requested, never by sources.
 const (
 	ResultSubdomain ResultType = iota
 	ResultError
+	ResultIP
+	ResultURL
+	ResultDNSRecord
+	ResultASN
+	ResultReport
+)
+
+// String returns the name of the result type, as accepted by ParseResultType.
+func (t ResultType) String() (name string) {
+	switch t {
+	case ResultSubdomain:
+		name = "subdomain"
+	case ResultError:
+		name = "error"
+	case ResultIP:
+		name = "ip"
+	case ResultURL:
+		name = "url"
+	case ResultDNSRecord:
+		name = "record"
+	case ResultASN:
+		name = "asn"
+	case ResultReport:
+		name = "report"
+	default:
+		name = fmt.Sprintf("ResultType(%d)", int(t))
+	}
+
+	return
+}
+
+// ParseResultType returns the result type with the given name, as returned by String.
+//
+// Parameters:
+//   - name (string): The name of the result type, e.g. "ip".
+//
+// Returns:
+//   - t (ResultType): The result type.
+//   - err (error): ErrUnknownResultType if no result type has that name.
+func ParseResultType(name string) (t ResultType, err error) {
+	for t = ResultSubdomain; t <= ResultReport; t++ {
+		if t.String() == strings.ToLower(name) {
+			return
+		}
+	}
+
+	err = fmt.Errorf("%w: %q", ErrUnknownResultType, name)
+
+	return
+}
+
+// Metadata keys set by the sources on Result.Metadata.
+const (
+	// MetadataHost is the host a result belongs to, e.g. the owner name of a DNS record.
+	MetadataHost = "host"
+	// MetadataRecordType is the type of a DNS record, e.g. "A" or "CNAME".
+	MetadataRecordType = "type"
+	// MetadataASName is the name of an autonomous system.
+	MetadataASName = "asname"
+	// MetadataExpired is set to "true" on results taken from a certificate that has expired.
+	// It describes that certificate only: the same name may also appear in valid certificates.
+	MetadataExpired = "expired"
 )
 
 // Supported data source constants.
@@ -173,19 +458,26 @@ const (
 	CHAOS              = "chaos"
 	COMMONCRAWL        = "commoncrawl"
 	CRTSH              = "crtsh"
+	CTLOGS             = "ctlogs"
+	DNSSEC             = "dnssec"
 	DRIFTNET           = "driftnet"
+	FDNSFILE           = "fdnsfile"
 	FULLHUNT           = "fullhunt"
 	GITHUB             = "github"
 	HACKERTARGET       = "hackertarget"
+	HOSTSFILE          = "hostsfile"
 	INTELLIGENCEX      = "intelx"
+	JSONLFILE          = "jsonlfile"
 	LEAKIX             = "leakix"
 	OPENTHREATEXCHANGE = "otx"
 	SECURITYTRAILS     = "securitytrails"
 	SHODAN             = "shodan"
 	SUBDOMAINCENTER    = "subdomaincenter"
+	TLS                = "tls"
 	URLSCAN            = "urlscan"
 	VIRUSTOTAL         = "virustotal"
 	WAYBACK            = "wayback"
+	ZONEFILE           = "zonefile"
 )
 
 // ErrNoKeys is a sentinel error returned when a SourceKeys slice contains no API keys.
@@ -193,7 +485,11 @@ const (
 // because no keys are available.
 var ErrNoKeys = errors.New("no keys available for the source")
 
-// List is a collection of all supported source names.
+// ErrUnknownResultType is returned by ParseResultType for names that match no result type.
+var ErrUnknownResultType = errors.New("unknown result type")
+
+// List is a collection of all supported passive source names, the sources used unless
+// others are named.
 //
 // This slice provides a convenient way to iterate over, validate, or dynamically configure
 // the data sources available in the application.
@@ -208,10 +504,13 @@ var List = []string{
 	COMMONCRAWL,
 	CRTSH,
 	DRIFTNET,
+	FDNSFILE,
 	FULLHUNT,
 	GITHUB,
 	HACKERTARGET,
+	HOSTSFILE,
 	INTELLIGENCEX,
+	JSONLFILE,
 	LEAKIX,
 	OPENTHREATEXCHANGE,
 	SECURITYTRAILS,
@@ -220,4 +519,35 @@ var List = []string{
 	URLSCAN,
 	VIRUSTOTAL,
 	WAYBACK,
+	ZONEFILE,
+}
+
+// Active is a collection of the supported source names that interact directly with the
+// target, e.g. by querying its name servers or connecting to its hosts. They are never
+// used by default, only when named, e.g. with --sources-to-use.
+var Active = []string{
+	DNSSEC,
+	TLS,
+}
+
+// OptIn is a collection of the supported passive source names that are costly to run,
+// e.g. because they read whole log ranges rather than query an index, and need to be
+// configured for it. Like the active sources, they are never used by default, only when
+// named.
+var OptIn = []string{
+	CTLOGS,
+}
+
+// Supported reports whether name is the name of a supported source, passive, opt-in or
+// active.
+//
+// Parameters:
+//   - name (string): The name of the source.
+//
+// Returns:
+//   - supported (bool): Whether the source is supported.
+func Supported(name string) (supported bool) {
+	supported = slices.Contains(List, name) || slices.Contains(OptIn, name) || slices.Contains(Active, name)
+
+	return
 }
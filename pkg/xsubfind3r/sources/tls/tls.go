@@ -0,0 +1,212 @@
+// Package tls provides an implementation of the sources.Enricher interface
+// that harvests names from the TLS certificates served by discovered hosts.
+//
+// Internal names frequently appear only in the subject alternative name lists of
+// certificates served by hosts that are already known. This package defines a Source
+// type that connects over TLS, with SNI set to the host name, to every discovered host
+// on each configured port, parses the presented certificate chain, and streams the
+// in-scope subject alternative names and common names with the originating host
+// recorded as the result's origin.
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// Source represents the TLS certificate harvesting data source implementation.
+// It implements the sources.Enricher interface, providing functionality
+// for retrieving subdomains from certificates served by live hosts.
+type Source struct{}
+
+// Run harvests names from the certificates served by the target domain itself.
+//
+// Parameters:
+//   - domain (string): The target domain for which to retrieve subdomains.
+//   - cfg (*sources.Configuration): The configuration instance containing the ports,
+//     timeout and concurrency used by the source.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+//     Each result is either a discovered subdomain (ResultSubdomain) or an error (ResultError)
+//     encountered during the operation.
+func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sources.Result {
+	hosts := make(chan string, 1)
+
+	hosts <- domain
+
+	close(hosts)
+
+	return source.Enrich(domain, hosts, cfg)
+}
+
+// Enrich harvests names from the certificates served by every host received on hosts.
+// Each host is probed once on each configured port; connection failures are expected
+// for most host and port pairs and are not reported.
+//
+// Parameters:
+//   - domain (string): The target domain; names outside it are dropped.
+//   - hosts (<-chan string): The discovered hosts to connect to.
+//   - cfg (*sources.Configuration): The configuration instance containing the ports,
+//     timeout and concurrency used by the source.
+//
+// Returns:
+//   - (<-chan sources.Result): A channel that asynchronously emits sources.Result values.
+func (source *Source) Enrich(domain string, hosts <-chan string, cfg *sources.Configuration) <-chan sources.Result {
+	results := make(chan sources.Result)
+
+	ports := cfg.TLS.Ports
+
+	if len(ports) == 0 {
+		ports = defaultPorts
+	}
+
+	timeout := time.Duration(cfg.TLS.Timeout) * time.Second
+
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	concurrency := cfg.TLS.Concurrency
+
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	ctx := cfg.Context
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	go func() {
+		defer close(results)
+
+		probed := map[string]struct{}{}
+
+		semaphore := make(chan struct{}, concurrency)
+
+		wg := &sync.WaitGroup{}
+
+		for host := range hosts {
+			// Hosts are still drained once the call is stopped, but no longer probed.
+			if cfg.Stopped() {
+				continue
+			}
+
+			host = strings.ToLower(host)
+
+			if _, ok := probed[host]; ok {
+				continue
+			}
+
+			probed[host] = struct{}{}
+
+			for _, port := range ports {
+				semaphore <- struct{}{}
+
+				wg.Add(1)
+
+				go func(host string, port int) {
+					defer wg.Done()
+
+					defer func() { <-semaphore }()
+
+					for _, name := range harvest(ctx, host, port, timeout) {
+						name = strings.TrimPrefix(strings.ToLower(name), "*.")
+
+						if name != domain && !strings.HasSuffix(name, "."+domain) {
+							continue
+						}
+
+						// The certificate is being served, so the name is current.
+						result := sources.Result{
+							Type:     sources.ResultSubdomain,
+							Source:   source.Name(),
+							Value:    name,
+							Origin:   host,
+							LastSeen: time.Now().UTC(),
+						}
+
+						results <- result
+					}
+				}(host, port)
+			}
+		}
+
+		wg.Wait()
+	}()
+
+	return results
+}
+
+// harvest connects to host on port, completes a TLS handshake with SNI set to host, and
+// returns the DNS names and common names of every certificate in the presented chain.
+// The chain is not verified: self-signed and internal certificates are exactly the ones
+// most likely to name hosts that appear nowhere else.
+//
+// Parameters:
+//   - ctx (context.Context): Abandons the connection once done.
+//   - host (string): The host to connect to and to send as SNI.
+//   - port (int): The port to connect to.
+//   - timeout (time.Duration): The connection and handshake timeout.
+//
+// Returns:
+//   - names ([]string): The names found in the presented certificates.
+func harvest(ctx context.Context, host string, port int, timeout time.Duration) (names []string) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{
+			Timeout: timeout,
+		},
+		Config: &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true, //nolint:gosec // Certificates are harvested, not trusted.
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return
+	}
+
+	for _, certificate := range tlsConn.ConnectionState().PeerCertificates {
+		names = append(names, certificate.DNSNames...)
+
+		if certificate.Subject.CommonName != "" {
+			names = append(names, certificate.Subject.CommonName)
+		}
+	}
+
+	return
+}
+
+// Name returns the unique identifier for the data source.
+// This identifier is used for logging, debugging, and associating results with the correct data source.
+//
+// Returns:
+//   - name (string): The unique identifier for the data source.
+func (source *Source) Name() (name string) {
+	return sources.TLS
+}
+
+const (
+	defaultTimeout     = 5 * time.Second
+	defaultConcurrency = 20
+)
+
+// defaultPorts are the ports probed when none are configured.
+var defaultPorts = []int{443}
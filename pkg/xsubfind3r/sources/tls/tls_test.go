@@ -0,0 +1,193 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// listener starts a local TLS server presenting a self-signed certificate for names,
+// and returns its port and the number of handshakes it served.
+func listener(t *testing.T, cn string, names []string) (port int, handshakes *atomic.Int32) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handshakes = &atomic.Int32{}
+
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			handshakes.Add(1)
+
+			return nil, nil //nolint:nilnil // The server configuration is kept.
+		},
+		MinVersion: tls.VersionTLS12,
+	}
+
+	server.StartTLS()
+
+	t.Cleanup(server.Close)
+
+	port = server.Listener.Addr().(*net.TCPAddr).Port
+
+	return
+}
+
+// closedPort returns a local port nothing listens on.
+func closedPort(t *testing.T) (port int) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port = l.Addr().(*net.TCPAddr).Port
+
+	l.Close()
+
+	return
+}
+
+func TestEnrich(t *testing.T) {
+	first, firstHandshakes := listener(t, "example.com", []string{"internal.example.com", "*.wild.example.com", "example.org"})
+	second, _ := listener(t, "", []string{"admin.example.com"})
+
+	cfg := &sources.Configuration{
+		TLS: sources.TLSConfiguration{
+			Ports:       []int{first, second, closedPort(t)},
+			Timeout:     2,
+			Concurrency: 2,
+		},
+	}
+
+	hosts := make(chan string, 2)
+
+	// Hosts are probed once, however often they are discovered.
+	hosts <- "127.0.0.1"
+	hosts <- "127.0.0.1"
+
+	close(hosts)
+
+	found := map[string]sources.Result{}
+
+	for result := range (&Source{}).Enrich("example.com", hosts, cfg) {
+		if result.Type != sources.ResultSubdomain {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+
+		found[result.Value] = result
+	}
+
+	for _, name := range []string{"example.com", "internal.example.com", "wild.example.com", "admin.example.com"} {
+		result, ok := found[name]
+		if !ok {
+			t.Errorf("missing %s, got %v", name, found)
+
+			continue
+		}
+
+		if result.Origin != "127.0.0.1" || result.LastSeen.IsZero() {
+			t.Errorf("%s: origin %q, last seen %v", name, result.Origin, result.LastSeen)
+		}
+	}
+
+	if len(found) != 4 {
+		t.Errorf("got %d names, want 4: %v", len(found), found)
+	}
+
+	if n := firstHandshakes.Load(); n != 1 {
+		t.Errorf("host probed %d times on one port, want 1", n)
+	}
+}
+
+func TestRun(t *testing.T) {
+	port, _ := listener(t, "", []string{"localhost", "www.localhost"})
+
+	cfg := &sources.Configuration{
+		TLS: sources.TLSConfiguration{
+			Ports: []int{port},
+		},
+	}
+
+	found := []string{}
+
+	for result := range (&Source{}).Run("localhost", cfg) {
+		found = append(found, result.Value)
+	}
+
+	if len(found) != 2 {
+		t.Errorf("got %v, want the names of the certificate served by the domain", found)
+	}
+}
+
+func TestHarvestTimeout(t *testing.T) {
+	// A listener that accepts connections but never completes a handshake.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l.Close()
+
+	go func() {
+		conns := []net.Conn{}
+
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			conns = append(conns, conn)
+		}
+	}()
+
+	start := time.Now()
+
+	names := harvest(t.Context(), "127.0.0.1", l.Addr().(*net.TCPAddr).Port, 200*time.Millisecond)
+	if len(names) != 0 {
+		t.Errorf("got %v from a silent listener", names)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("handshake not abandoned after the timeout, took %v", elapsed)
+	}
+}
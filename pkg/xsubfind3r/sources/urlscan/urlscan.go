@@ -4,7 +4,8 @@
 // The urlscan.io API offers subdomain discovery and website scanning capabilities.
 // This package defines a Source type that implements the Run and Name methods as specified
 // by the sources.Source interface. The Run method sends queries to the urlscan.io API,
-// processes the JSON response, and streams discovered subdomains or errors via a channel.
+// processes the JSON response, and streams discovered subdomains, the URLs, IP addresses and
+// ASNs of the scanned pages, or errors via a channel.
 package urlscan
 
 import (
@@ -24,7 +25,8 @@ import (
 //
 // It contains the following fields:
 //   - Results: A slice of result objects, each containing details about a scanned page.
-//     Each result includes a Page field with domain-related data and a Sort field used for pagination.
+//     Each result includes a Page field with domain-related data, such as the page's URL, the IP
+//     address it was served from and that address' ASN, and a Sort field used for pagination.
 //   - Status: An integer representing the status code of the API response.
 //   - Total: An integer representing the total number of results.
 //   - Took: An integer representing the time taken for the search (in milliseconds).
@@ -36,6 +38,9 @@ type searchResponse struct {
 			MimeType string `json:"mimeType"`
 			URL      string `json:"url"`
 			Status   string `json:"status"`
+			IP       string `json:"ip"`
+			ASN      string `json:"asn"`
+			ASNName  string `json:"asnname"`
 		} `json:"page"`
 		Sort []interface{} `json:"sort"`
 	} `json:"results"`
@@ -72,7 +77,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewError(sources.ErrUnclassified, source.Name(), err),
 			}
 
 			results <- result
@@ -83,6 +88,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		var after string
 
 		for {
+			if cfg.Stopped() {
+				break
+			}
+
 			searchReqURL := "https://urlscan.io/api/v1/search"
 			searchReqCFG := &hqgohttp.RequestConfiguration{
 				Params: map[string]string{
@@ -99,8 +108,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				searchReqCFG.Params["search_after"] = after
 			}
 
-			searchRes, err := hqgohttp.Get(searchReqURL, searchReqCFG)
+			searchRes, err := cfg.Client().Get(searchReqURL, searchReqCFG)
 			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewRequestError(source.Name(), searchRes, err),
+				}
+
+				results <- result
+
+				break
+			}
+
+			if err = sources.CheckResponse(source.Name(), searchRes); err != nil {
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
@@ -109,6 +130,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 				results <- result
 
+				searchRes.Body.Close()
+
 				break
 			}
 
@@ -118,7 +141,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewParseError(source.Name(), searchRes, err),
 				}
 
 				results <- result
@@ -141,6 +164,21 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 					continue
 				}
 
+				// The address is sent before the subdomain it resolves, whether or not it is
+				// requested, so that it counts towards its score.
+				if record.Page.IP != "" {
+					result := sources.Result{
+						Type:   sources.ResultIP,
+						Source: source.Name(),
+						Value:  record.Page.IP,
+						Metadata: map[string]string{
+							sources.MetadataHost: subdomain,
+						},
+					}
+
+					results <- result
+				}
+
 				result := sources.Result{
 					Type:   sources.ResultSubdomain,
 					Source: source.Name(),
@@ -148,6 +186,30 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				}
 
 				results <- result
+
+				if cfg.Wants(sources.ResultURL) && record.Page.URL != "" {
+					result := sources.Result{
+						Type:   sources.ResultURL,
+						Source: source.Name(),
+						Value:  record.Page.URL,
+					}
+
+					results <- result
+				}
+
+				if cfg.Wants(sources.ResultASN) && record.Page.ASN != "" {
+					result := sources.Result{
+						Type:   sources.ResultASN,
+						Source: source.Name(),
+						Value:  record.Page.ASN,
+						Metadata: map[string]string{
+							sources.MetadataHost:   subdomain,
+							sources.MetadataASName: record.Page.ASNName,
+						},
+					}
+
+					results <- result
+				}
 			}
 
 			if !searchResData.HasMore {
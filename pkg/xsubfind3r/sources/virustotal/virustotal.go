@@ -4,13 +4,12 @@
 // The VirusTotal API offers subdomain discovery for a given domain by returning
 // subdomain data and pagination details. This package defines a Source type that implements
 // the Run and Name methods as specified by the sources.Source interface. The Run method sends
-// queries to the VirusTotal API, processes the JSON response, and streams discovered subdomains
-// or errors via a channel.
+// queries to the VirusTotal API, processes the JSON response, and streams discovered subdomains,
+// their last observed DNS records and IP addresses, or errors via a channel.
 package virustotal
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 
 	hqgohttp "github.com/hueristiq/hq-go-http"
@@ -22,7 +21,8 @@ import (
 // It contains the following fields:
 //   - Error: An object containing error details if the API encountered an error.
 //   - Data: A slice of objects where each object represents a discovered subdomain.
-//     Each object contains an ID (the subdomain), a Type, and associated Links.
+//     Each object contains an ID (the subdomain), a Type, associated Links and the
+//     subdomain's Attributes, including the DNS records last observed for it.
 //   - Meta: A metadata object containing a Cursor field used for pagination.
 type getSubdomainsResponse struct {
 	Error struct {
@@ -35,6 +35,12 @@ type getSubdomainsResponse struct {
 		Links struct {
 			Self string `json:"self"`
 		} `json:"links"`
+		Attributes struct {
+			LastDNSRecords []struct {
+				Type  string `json:"type"`
+				Value string `json:"value"`
+			} `json:"last_dns_records"`
+		} `json:"attributes"`
 	} `json:"data"`
 	Meta struct {
 		Cursor string `json:"cursor"`
@@ -68,7 +74,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			result := sources.Result{
 				Type:   sources.ResultError,
 				Source: source.Name(),
-				Error:  err,
+				Error:  sources.NewMissingKeyError(source.Name(), err),
 			}
 
 			results <- result
@@ -79,6 +85,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		var cursor string
 
 		for {
+			if cfg.Stopped() {
+				break
+			}
+
 			getSubdomainsReqURL := fmt.Sprintf("https://www.virustotal.com/api/v3/domains/%s/subdomains", domain)
 			getSubdomainsReqCFG := &hqgohttp.RequestConfiguration{
 				Params: map[string]string{
@@ -93,8 +103,20 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				getSubdomainsReqCFG.Params["cursor"] = cursor
 			}
 
-			getSubdomainsRes, err := hqgohttp.Get(getSubdomainsReqURL, getSubdomainsReqCFG)
+			getSubdomainsRes, err := cfg.Client().Get(getSubdomainsReqURL, getSubdomainsReqCFG)
 			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewRequestError(source.Name(), getSubdomainsRes, err),
+				}
+
+				results <- result
+
+				break
+			}
+
+			if err = sources.CheckResponse(source.Name(), getSubdomainsRes); err != nil {
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
@@ -103,6 +125,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 				results <- result
 
+				getSubdomainsRes.Body.Close()
+
 				break
 			}
 
@@ -112,7 +136,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewParseError(source.Name(), getSubdomainsRes, err),
 				}
 
 				results <- result
@@ -128,11 +152,10 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error: fmt.Errorf(
-						"%w: %s, %s",
-						errStatic,
-						getSubdomainsResData.Error.Code,
-						getSubdomainsResData.Error.Message,
+					Error: sources.NewError(
+						sources.ErrInvalidResponse,
+						source.Name(),
+						fmt.Errorf("%s, %s", getSubdomainsResData.Error.Code, getSubdomainsResData.Error.Message),
 					),
 				}
 
@@ -144,6 +167,35 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 			for _, record := range getSubdomainsResData.Data {
 				subdomain := record.ID
 
+				// Records are sent before the subdomain they resolve, whether or not they
+				// are requested, so that they count towards its score.
+				for _, dnsRecord := range record.Attributes.LastDNSRecords {
+					result := sources.Result{
+						Type:   sources.ResultDNSRecord,
+						Source: source.Name(),
+						Value:  dnsRecord.Value,
+						Metadata: map[string]string{
+							sources.MetadataHost:       subdomain,
+							sources.MetadataRecordType: dnsRecord.Type,
+						},
+					}
+
+					results <- result
+
+					if dnsRecord.Type == "A" || dnsRecord.Type == "AAAA" {
+						result := sources.Result{
+							Type:   sources.ResultIP,
+							Source: source.Name(),
+							Value:  dnsRecord.Value,
+							Metadata: map[string]string{
+								sources.MetadataHost: subdomain,
+							},
+						}
+
+						results <- result
+					}
+				}
+
 				result := sources.Result{
 					Type:   sources.ResultSubdomain,
 					Source: source.Name(),
@@ -172,7 +224,3 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 func (source *Source) Name() (name string) {
 	return sources.VIRUSTOTAL
 }
-
-// errStatic is a sentinel error used to prepend error messages when the VirusTotal API response
-// contains error details.
-var errStatic = errors.New("something went wrong")
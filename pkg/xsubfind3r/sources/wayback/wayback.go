@@ -6,14 +6,15 @@
 // from archived pages. This package defines a Source type that implements the Run
 // and Name methods as specified by the sources.Source interface. The Run method sends
 // paginated queries to the Wayback Machine API, processes the JSON response, extracts
-// subdomains using a provided regular expression, and streams discovered subdomains or
-// errors via a channel.
+// subdomains using a provided regular expression, and streams discovered subdomains, the
+// archived URLs, or errors via a channel.
 //
 // Additionally, a rate limiter is configured to control the number of requests per minute.
 package wayback
 
 import (
 	"encoding/json"
+	"time"
 
 	hqgohttp "github.com/hueristiq/hq-go-http"
 	hqgolimiter "github.com/hueristiq/hq-go-limiter"
@@ -52,20 +53,36 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 		for page := uint(0); ; page++ {
 			limiter.Wait()
 
+			if cfg.Stopped() {
+				return
+			}
+
 			getURLsReqURL := "https://web.archive.org/cdx/search/cdx"
 			getURLsReqCFG := &hqgohttp.RequestConfiguration{
 				Params: map[string]string{
 					"url":      "*." + domain + "/*",
 					"output":   "json",
 					"collapse": "urlkey",
-					"fl":       "original",
+					"fl":       "original,timestamp",
 					"pageSize": "100",
 					"page":     cast.ToString(page),
 				},
 			}
 
-			getURLsRes, err := hqgohttp.Get(getURLsReqURL, getURLsReqCFG)
+			getURLsRes, err := cfg.Client().Get(getURLsReqURL, getURLsReqCFG)
 			if err != nil {
+				result := sources.Result{
+					Type:   sources.ResultError,
+					Source: source.Name(),
+					Error:  sources.NewRequestError(source.Name(), getURLsRes, err),
+				}
+
+				results <- result
+
+				return
+			}
+
+			if err = sources.CheckResponse(source.Name(), getURLsRes); err != nil {
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
@@ -74,6 +91,8 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 				results <- result
 
+				getURLsRes.Body.Close()
+
 				return
 			}
 
@@ -83,7 +102,7 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 				result := sources.Result{
 					Type:   sources.ResultError,
 					Source: source.Name(),
-					Error:  err,
+					Error:  sources.NewParseError(source.Name(), getURLsRes, err),
 				}
 
 				results <- result
@@ -103,13 +122,33 @@ func (source *Source) Run(domain string, cfg *sources.Configuration) <-chan sour
 
 			// Slicing as [1:] to skip first result by default
 			for _, entry := range getURLsResData[1:] {
+				// Results are collapsed by URL, so the timestamp is that of the URL's first
+				// capture and says nothing about when it was last seen.
+				var firstSeen time.Time
+
+				if len(entry) > 1 {
+					firstSeen = sources.ParseTimestamp(entry[1])
+				}
+
+				if cfg.Wants(sources.ResultURL) {
+					result := sources.Result{
+						Type:      sources.ResultURL,
+						Source:    source.Name(),
+						Value:     entry[0],
+						FirstSeen: firstSeen,
+					}
+
+					results <- result
+				}
+
 				match := cfg.Extractor.FindAllString(entry[0], -1)
 
 				for _, subdomain := range match {
 					result := sources.Result{
-						Type:   sources.ResultSubdomain,
-						Source: source.Name(),
-						Value:  subdomain,
+						Type:      sources.ResultSubdomain,
+						Source:    source.Name(),
+						Value:     subdomain,
+						FirstSeen: firstSeen,
 					}
 
 					results <- result
@@ -10,14 +10,17 @@
 package xsubfind3r
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	hqgohttp "github.com/hueristiq/hq-go-http"
-	hqgohttpheader "github.com/hueristiq/hq-go-http/header"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/anubis"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/bevigil"
@@ -28,16 +31,20 @@ import (
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/chaos"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/commoncrawl"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/crtsh"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/ctlogs"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/dnssec"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/driftnet"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/fullhunt"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/github"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/hackertarget"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/imports"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/intelx"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/leakix"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/otx"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/securitytrails"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/shodan"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/subdomaincenter"
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/tls"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/urlscan"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/virustotal"
 	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources/wayback"
@@ -46,59 +53,732 @@ import (
 // Finder is the primary structure for performing subdomain discovery.
 // It manages data sources and configuration settings.
 //
+// A Finder is safe for concurrent use: its fields are only written by New, and all
+// state belonging to a single enumeration lives in that call's run.
+//
 // Fields:
 //   - sources (map[string]sources.Source): A map of string keys to sources.Source interfaces representing the enabled enumeration sources.
 //   - configuration (*sources.Configuration): A pointer to the sources.Configuration struct containing API keys and other settings.
+//   - client (*client): Builds the HTTP client of each call.
+//   - scorer (*scorer): Computes the confidence score of every subdomain.
 type Finder struct {
 	sources       map[string]sources.Source
 	configuration *sources.Configuration
+	client        *client
+	scorer        *scorer
 }
 
 // Find initiates the subdomain discovery process for a specific domain.
 // It normalizes the domain name, applies source-specific logic, and streams results via a channel.
-// The method uses all enabled sources concurrently and aggregates their results.
+// The method uses all enabled sources concurrently and aggregates their results. Sources that
+// implement sources.Enricher are fed every unique subdomain discovered by the other sources.
+//
+// Options override the Finder's defaults for this call only, e.g. to use a subset of its
+// sources or to stop after a timeout or a number of results. Once a call is stopped, the
+// results channel is closed and the context of the call (sources.Configuration.Context)
+// is cancelled: sources still running stop issuing requests, their requests in flight
+// are aborted, and their remaining results are discarded.
 //
 // Parameters:
 //   - domain (string): The target domain for subdomain discovery.
+//   - options (...FindOption): Per-call overrides.
 //
 // Returns:
 //   - results (chan sources.Result): A channel that streams subdomain enumeration results.
-func (finder *Finder) Find(domain string) (results chan sources.Result) {
-	results = make(chan sources.Result)
+func (finder *Finder) Find(domain string, options ...FindOption) (results chan sources.Result) {
+	results = finder.find(newRun(domain, finder.configuration, finder.client, finder.scorer, options))
 
-	pattern := fmt.Sprintf(`(?i)(?:((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+))?(%s)`, regexp.QuoteMeta(domain))
+	return
+}
+
+// Sources returns the names of the sources the Finder was created with, sorted.
+//
+// Returns:
+//   - names ([]string): The names of the enabled sources.
+func (finder *Finder) Sources() (names []string) {
+	names = slices.Sorted(maps.Keys(finder.sources))
+
+	return
+}
+
+// Describe describes the sources the Finder was created with, sorted by name.
+//
+// Returns:
+//   - infos ([]SourceInfo): The descriptions of the enabled sources.
+func (finder *Finder) Describe() (infos []SourceInfo) {
+	for _, name := range finder.Sources() {
+		_, enricher := finder.sources[name].(sources.Enricher)
 
-	finder.configuration.Extractor = regexp.MustCompile(pattern)
+		keys, keyed := finder.configuration.Keys.For(name)
+
+		infos = append(infos, SourceInfo{
+			Name:     name,
+			Keyed:    keyed,
+			Keys:     len(keys),
+			Enricher: enricher,
+		})
+	}
+
+	return
+}
+
+// find runs the enabled sources for r and streams their accepted results. The results
+// channel is closed once every source has finished or r is stopped, whichever comes first.
+//
+// Parameters:
+//   - r (*run): The prepared run.
+//
+// Returns:
+//   - results (chan sources.Result): A channel that streams subdomain enumeration results.
+func (finder *Finder) find(r *run) (results chan sources.Result) {
+	results = make(chan sources.Result)
 
 	go func() {
 		defer close(results)
 
-		seen := &sync.Map{}
+		defer r.stop()
+
+		if at := r.options.stopAt(time.Now()); !at.IsZero() {
+			timer := time.AfterFunc(time.Until(at), r.stop)
+
+			defer timer.Stop()
+		}
+
+		if ctx := r.options.ctx; ctx != nil {
+			go func() {
+				select {
+				case <-ctx.Done():
+					r.stop()
+				case <-r.done:
+				}
+			}()
+		}
+
+		hosts := []chan string{}
+
+		ewg := &sync.WaitGroup{}
+
+		for name, source := range finder.sources {
+			enricher, ok := source.(sources.Enricher)
+			if !ok || !r.options.uses(name) {
+				continue
+			}
+
+			h := make(chan string)
+
+			hosts = append(hosts, h)
+
+			ewg.Add(1)
+
+			go func(enricher sources.Enricher) {
+				defer ewg.Done()
+
+				for eResult := range enricher.Enrich(r.domain, h, r.configuration) {
+					if !r.accept(&eResult) {
+						continue
+					}
+
+					r.emit(results, eResult)
+				}
+			}(enricher)
+		}
 
 		wg := &sync.WaitGroup{}
 
-		for _, source := range finder.sources {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for _, h := range hosts {
+				h <- r.domain
+			}
+		}()
+
+		for name, source := range finder.sources {
+			if _, ok := source.(sources.Enricher); ok || !r.options.uses(name) {
+				continue
+			}
+
 			wg.Add(1)
 
 			go func(source sources.Source) {
 				defer wg.Done()
 
-				sResults := source.Run(domain, finder.configuration)
+				sResults := source.Run(r.domain, r.configuration)
 
 				for sResult := range sResults {
+					if !r.accept(&sResult) {
+						continue
+					}
+
+					if !r.emit(results, sResult) {
+						continue
+					}
+
 					if sResult.Type == sources.ResultSubdomain {
-						sResult.Value = strings.ToLower(sResult.Value)
-						sResult.Value = strings.ReplaceAll(sResult.Value, "*.", "")
+						for _, h := range hosts {
+							h <- sResult.Value
+						}
+					}
+				}
+			}(source)
+		}
+
+		// Once the run is stopped, results are no longer consumed, so wait for the sources
+		// in the background and close the channel right away.
+		finished := make(chan struct{})
+
+		go func() {
+			wg.Wait()
+
+			// Every source has reported: the names only found in expired certificates
+			// are known to have no valid one.
+			for _, result := range r.settle() {
+				if !r.emit(results, result) {
+					continue
+				}
+
+				for _, h := range hosts {
+					h <- result.Value
+				}
+			}
+
+			for _, h := range hosts {
+				close(h)
+			}
+
+			ewg.Wait()
+
+			close(finished)
+		}()
+
+		select {
+		case <-finished:
+		case <-r.done:
+		}
+	}()
+
+	return
+}
+
+// run holds the state of a single Find call. Keeping it out of the Finder is what makes
+// concurrent Find calls on one Finder safe.
+//
+// Fields:
+//   - domain (string): The normalized target domain.
+//   - configuration (*sources.Configuration): A copy of the Finder's configuration with the
+//     extractor for domain set.
+//   - options (*findOptions): The per-call overrides.
+//   - scorer (*scorer): Computes the confidence score of every subdomain.
+//   - cutoff (time.Time): Results last seen before this time are dropped. Zero means none are.
+//   - seen (*sync.Map): The subdomains reported to this run, mapped to their *sighting.
+//   - records (*sync.Map): The keys of the IP, URL, DNS record and ASN results already emitted.
+//   - count (atomic.Int64): The number of subdomains accepted by this run.
+//   - settled (atomic.Bool): Whether every source but the enrichers has finished, after
+//     which names only found in expired certificates are no longer held back.
+//   - done (chan struct{}): Closed when the run is stopped.
+//   - cancel (context.CancelFunc): Cancels the context of configuration when the run is
+//     stopped, so that sources stop issuing requests.
+//   - once (sync.Once): Guards closing done.
+type run struct {
+	domain        string
+	configuration *sources.Configuration
+	options       *findOptions
+	scorer        *scorer
+	cutoff        time.Time
+	seen          *sync.Map
+	records       *sync.Map
+	count         atomic.Int64
+	settled       atomic.Bool
+	done          chan struct{}
+	cancel        context.CancelFunc
+	once          sync.Once
+}
+
+// sighting merges the first-seen and last-seen times reported for a subdomain by every
+// source, and records the evidence it is scored on, whether any source reported it
+// without a date, whether any reported it other than from an expired certificate, the
+// first report from an expired certificate, and whether it has been emitted.
+type sighting struct {
+	mu        sync.Mutex
+	first     time.Time
+	last      time.Time
+	undated   bool
+	current   bool
+	held      *sources.Result
+	reporters map[string]struct{}
+	resolved  bool
+	emitted   bool
+}
+
+// merge widens the sighting to include the given times. Zero times are ignored.
+func (s *sighting) merge(first, last time.Time) {
+	if !first.IsZero() && (s.first.IsZero() || first.Before(s.first)) {
+		s.first = first
+	}
+
+	if !last.IsZero() && last.After(s.last) {
+		s.last = last
+	}
+
+	// One source may have seen the subdomain more recently than another last saw it.
+	if !s.last.IsZero() && s.first.After(s.last) {
+		s.last = s.first
+	}
+}
+
+// accept normalizes a result in place and reports whether it should be emitted:
+// subdomains outside the run's scope, subdomains already emitted, subdomains last seen
+// before the run's cutoff, subdomains scoring below the run's minimum and subdomains
+// beyond the result limit are rejected.
+//
+// The first-seen and last-seen times and the scoring evidence reported for a subdomain
+// are merged across sources, and an emitted subdomain carries the merged times, the
+// score and the reporting sources known when it is emitted. Evidence only ever adds up,
+// so a subdomain rejected for being too old or scoring too low is emitted later if other
+// sources report it as more recent, or without a date, or corroborate it. Subdomains only
+// found in expired certificates are held back until every source but the enrichers has
+// reported, and only flagged as expired if none reported them otherwise; revocation is
+// not checked. With WithReports, the first report of an emitted subdomain by each further
+// source is accepted as a sources.ResultReport result. Error results are always
+// accepted; their errors are classified, so they always carry a *sources.Error. Results
+// of the other types are handled by acceptRecord.
+//
+// Parameters:
+//   - result (*sources.Result): The result to check.
+//
+// Returns:
+//   - ok (bool): Whether the result should be emitted.
+func (r *run) accept(result *sources.Result) (ok bool) {
+	switch result.Type {
+	case sources.ResultSubdomain:
+	case sources.ResultError:
+		result.Error = sources.Classify(result.Source, result.Error)
+
+		ok = true
+
+		return
+	default:
+		ok = r.acceptRecord(result)
+
+		return
+	}
+
+	result.Value = strings.ToLower(result.Value)
+	result.Value = strings.ReplaceAll(result.Value, "*.", "")
+
+	if result.Value != r.domain && !strings.HasSuffix(result.Value, "."+r.domain) {
+		return
+	}
+
+	if !r.options.inScope(result.Value) {
+		return
+	}
+
+	if result.Source == sources.TLS && result.Origin != "" {
+		r.resolve(result.Origin)
+	}
+
+	s := r.sighting(result.Value)
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.merge(result.FirstSeen, result.LastSeen)
+
+	if result.FirstSeen.IsZero() && result.LastSeen.IsZero() {
+		s.undated = true
+	}
+
+	_, reported := s.reporters[result.Source]
+
+	s.reporters[result.Source] = struct{}{}
+
+	if s.emitted {
+		if !reported && r.options.reports {
+			result.Type = sources.ResultReport
+
+			ok = true
+		}
+
+		return
+	}
+
+	if result.Metadata[sources.MetadataExpired] != "true" {
+		s.current = true
+	} else if s.held == nil {
+		held := *result
+
+		s.held = &held
+	}
+
+	// Until every source has reported, a name only found in expired certificates may
+	// still turn up in a valid one, so it is held back; see settle.
+	if !s.current && !r.settled.Load() {
+		return
+	}
+
+	ok = r.admit(s, result)
+
+	return
+}
+
+// admit reports whether the subdomain result, whose sighting s is locked, should be
+// emitted, and if so marks it emitted and sets its merged times, score and sources. It
+// is flagged as expired only if no source reported it other than from an expired
+// certificate.
+func (r *run) admit(s *sighting, result *sources.Result) (ok bool) {
+	// A subdomain any source reports without a date is not known to be old, so it passes
+	// the cutoff whatever the other sources report, and in whatever order they do.
+	if s.emitted || (!s.undated && !r.recent(s.last)) {
+		return
+	}
+
+	score := r.scorer.score(s.reporters, s.resolved, s.last, time.Now())
+
+	if score < r.options.minScore {
+		return
+	}
+
+	if r.options.maxResults > 0 && r.count.Add(1) > int64(r.options.maxResults) {
+		return
+	}
+
+	s.emitted = true
+
+	result.FirstSeen = s.first
+	result.LastSeen = s.last
+	result.Score = score
+	result.Sources = slices.Sorted(maps.Keys(s.reporters))
+
+	if s.current && result.Metadata[sources.MetadataExpired] != "" {
+		result.Metadata = maps.Clone(result.Metadata)
+
+		delete(result.Metadata, sources.MetadataExpired)
+	}
+
+	ok = true
+
+	return
+}
+
+// settle marks the run settled, once every source but the enrichers has finished, and
+// returns the held back subdomains, only found in expired certificates, that should be
+// emitted, sorted.
+//
+// Returns:
+//   - accepted ([]sources.Result): The subdomains to emit.
+func (r *run) settle() (accepted []sources.Result) {
+	r.settled.Store(true)
 
-						_, loaded := seen.LoadOrStore(sResult.Value, struct{}{})
-						if loaded {
-							continue
+	r.seen.Range(func(_, value any) bool {
+		s, _ := value.(*sighting)
+
+		s.mu.Lock()
+
+		defer s.mu.Unlock()
+
+		if s.held == nil {
+			return true
+		}
+
+		result := *s.held
+
+		if r.admit(s, &result) {
+			accepted = append(accepted, result)
+		}
+
+		return true
+	})
+
+	slices.SortFunc(accepted, func(a, b sources.Result) int {
+		return strings.Compare(a.Value, b.Value)
+	})
+
+	return
+}
+
+// sighting returns the sighting of subdomain, creating it if needed.
+func (r *run) sighting(subdomain string) (s *sighting) {
+	value, _ := r.seen.LoadOrStore(subdomain, &sighting{
+		reporters: map[string]struct{}{},
+	})
+
+	s = value.(*sighting)
+
+	return
+}
+
+// resolve records that host was seen live. It counts towards the score of host the next
+// time a source reports it, unless host has already been emitted.
+func (r *run) resolve(host string) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if host != r.domain && !strings.HasSuffix(host, "."+r.domain) {
+		return
+	}
+
+	s := r.sighting(host)
+
+	s.mu.Lock()
+
+	s.resolved = true
+
+	s.mu.Unlock()
+}
+
+// recent reports whether a result last seen at last passes the run's cutoff. Results
+// whose last-seen time is unknown always pass.
+func (r *run) recent(last time.Time) (ok bool) {
+	ok = r.cutoff.IsZero() || last.IsZero() || !last.Before(r.cutoff)
+
+	return
+}
+
+// acceptRecord normalizes an IP, URL, DNS record or ASN result in place and reports
+// whether it should be emitted: results of types not requested, results belonging to a
+// host outside the run's scope, results last seen before the run's cutoff and results
+// already emitted are rejected. The host of URL results is derived from the URL when the
+// source did not set it. IP addresses and DNS records are recorded as evidence that their
+// host resolves, whether or not they are requested: sources send them whatever the
+// requested types, before the subdomain they resolve; see sources.Configuration.Wants.
+//
+// Parameters:
+//   - result (*sources.Result): The result to check.
+//
+// Returns:
+//   - ok (bool): Whether the result should be emitted.
+func (r *run) acceptRecord(result *sources.Result) (ok bool) {
+	if host := result.Metadata[sources.MetadataHost]; host != "" && (result.Type == sources.ResultIP || result.Type == sources.ResultDNSRecord) {
+		r.resolve(host)
+	}
+
+	if !r.configuration.Wants(result.Type) {
+		return
+	}
+
+	host := result.Metadata[sources.MetadataHost]
+
+	if host == "" && result.Type == sources.ResultURL {
+		parsed, err := url.Parse(result.Value)
+		if err != nil || parsed.Hostname() == "" {
+			return
+		}
+
+		host = parsed.Hostname()
+	}
+
+	if host != "" {
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+		if host != r.domain && !strings.HasSuffix(host, "."+r.domain) {
+			return
+		}
+
+		if !r.options.inScope(host) {
+			return
+		}
+
+		metadata := maps.Clone(result.Metadata)
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+
+		metadata[sources.MetadataHost] = host
+
+		result.Metadata = metadata
+	}
+
+	if !r.recent(result.LastSeen) {
+		return
+	}
+
+	key := fmt.Sprintf("%s|%s|%s|%s", result.Type, host, result.Metadata[sources.MetadataRecordType], result.Value)
+
+	if _, loaded := r.records.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	ok = true
+
+	return
+}
+
+// emit sends result unless the run has been stopped, and stops the run once the result
+// limit has been reached.
+//
+// Parameters:
+//   - results (chan sources.Result): The channel to send to.
+//   - result (sources.Result): The result to send.
+//
+// Returns:
+//   - sent (bool): Whether the result was sent.
+func (r *run) emit(results chan sources.Result, result sources.Result) (sent bool) {
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+
+	select {
+	case <-r.done:
+		return
+	case results <- result:
+		sent = true
+	}
+
+	if r.options.maxResults > 0 && r.count.Load() >= int64(r.options.maxResults) {
+		r.stop()
+	}
+
+	return
+}
+
+// stop stops the run. It is safe to call more than once.
+func (r *run) stop() {
+	r.once.Do(func() {
+		close(r.done)
+
+		r.cancel()
+	})
+}
+
+// newRun prepares the state of a Find call for domain.
+//
+// Parameters:
+//   - domain (string): The target domain.
+//   - configuration (*sources.Configuration): The Finder's configuration; it is copied,
+//     never modified.
+//   - client (*client): The Finder's client, bound to the context of the call for its
+//     sources.
+//   - scorer (*scorer): The Finder's scorer.
+//   - options ([]FindOption): The per-call overrides.
+//
+// Returns:
+//   - r (*run): The prepared run.
+func newRun(domain string, configuration *sources.Configuration, client *client, scorer *scorer, options []FindOption) (r *run) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	cfg := *configuration
+
+	pattern := fmt.Sprintf(`(?i)(?:((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+))?(%s)`, regexp.QuoteMeta(domain))
+
+	cfg.Extractor = regexp.MustCompile(pattern)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg.Context = ctx
+
+	// Should binding fail, the Finder's unbound client is kept: requests in flight then
+	// complete in the background once the call is stopped.
+	if bound, err := client.bind(ctx); err == nil {
+		cfg.HTTPClient = bound
+	}
+
+	r = &run{
+		domain:        domain,
+		configuration: &cfg,
+		options:       &findOptions{},
+		scorer:        scorer,
+		seen:          &sync.Map{},
+		records:       &sync.Map{},
+		done:          make(chan struct{}),
+		cancel:        cancel,
+	}
+
+	for _, option := range options {
+		option(r.options)
+	}
+
+	r.cutoff = r.options.cutoff(time.Now())
+
+	return
+}
+
+// FindMany enumerates the subdomains of several domains at once, running at most
+// concurrency enumerations concurrently. Results of every domain are deduplicated
+// independently and tagged with the domain they belong to.
+//
+// Parameters:
+//   - domains ([]string): The target domains for subdomain discovery.
+//   - concurrency (int): The maximum number of domains enumerated concurrently.
+//   - options (...FindOption): Per-call overrides, applied to every domain.
+//
+// Returns:
+//   - results (chan DomainResult): A channel that streams the results of every domain.
+func (finder *Finder) FindMany(domains []string, concurrency int, options ...FindOption) (results chan DomainResult) {
+	queue := make(chan string)
+
+	go func() {
+		defer close(queue)
+
+		for _, domain := range domains {
+			queue <- domain
+		}
+	}()
+
+	results = finder.FindFrom(queue, concurrency, options...)
+
+	return
+}
+
+// FindFrom enumerates the subdomains of every domain received on domains, running at
+// most concurrency enumerations concurrently. A domain is received only once a worker is
+// free to enumerate it, so the channel can be fed from a live, unbounded input. The
+// results channel is closed once domains is closed and every enumeration has finished.
+//
+// Sources share the Finder's configuration, so package-level rate limiters and key
+// rotation state apply across all domains rather than per domain.
+//
+// Parameters:
+//   - domains (<-chan string): The target domains for subdomain discovery.
+//   - concurrency (int): The maximum number of domains enumerated concurrently.
+//   - options (...FindOption): Per-call overrides, applied to every domain.
+//
+// Returns:
+//   - results (chan DomainResult): A channel that streams the results of every domain.
+func (finder *Finder) FindFrom(domains <-chan string, concurrency int, options ...FindOption) (results chan DomainResult) {
+	results = make(chan DomainResult)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	o := &findOptions{}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	go func() {
+		defer close(results)
+
+		wg := &sync.WaitGroup{}
+
+		for range concurrency {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for domain := range domains {
+					for result := range finder.Find(domain, options...) {
+						results <- DomainResult{
+							Domain: domain,
+							Result: result,
 						}
 					}
 
-					results <- sResult
+					if o.ends {
+						results <- DomainResult{
+							Domain: domain,
+							End:    true,
+						}
+					}
 				}
-			}(source)
+			}()
 		}
 
 		wg.Wait()
@@ -107,6 +787,37 @@ func (finder *Finder) Find(domain string) (results chan sources.Result) {
 	return
 }
 
+// DomainResult is a sources.Result tagged with the target domain it was found for,
+// as streamed by FindMany and FindFrom.
+//
+// Fields:
+//   - Domain (string): The target domain.
+//   - End (bool): Marks that every result of Domain has been sent, in which case Result
+//     is empty. Only sent when requested with WithDomainEnds.
+//   - Result (sources.Result): The result.
+type DomainResult struct {
+	Domain string
+	End    bool
+
+	sources.Result
+}
+
+// SourceInfo describes a source of a Finder, as returned by Describe.
+//
+// Fields:
+//   - Name (string): The name of the source.
+//   - Keyed (bool): Whether the source takes API keys.
+//   - Keys (int): The number of API keys configured for the source. A keyed source without
+//     keys reports a missing key error instead of results.
+//   - Enricher (bool): Whether the source derives results from the subdomains found by the
+//     other sources, as a sources.Enricher.
+type SourceInfo struct {
+	Name     string
+	Keyed    bool
+	Keys     int
+	Enricher bool
+}
+
 type ClientConfiguration struct {
 	UserAgent string
 }
@@ -118,11 +829,27 @@ type ClientConfiguration struct {
 //   - SourcesToUSe ([]string): List of source names to be used for enumeration.
 //   - SourcesToExclude ([]string): List of source names to be excluded from enumeration.
 //   - Keys (sources.Keys): API keys for authenticated sources.
+//   - DNSSEC (sources.DNSSECConfiguration): Settings for the DNSSEC zone-walking source.
+//   - CTLogs (sources.CTLogsConfiguration): Settings for the Certificate Transparency log source.
+//   - TLS (sources.TLSConfiguration): Settings for the TLS certificate harvesting source.
+//   - Imports (sources.ImportsConfiguration): Files read by the import sources.
+//   - ResultTypes ([]sources.ResultType): The result types to emit in addition to subdomains
+//     and errors, e.g. sources.ResultIP. Results of other types are discarded.
+//   - ExcludeExpired (bool): Certificate-based sources skip names from expired certificates
+//     instead of flagging them with sources.MetadataExpired.
+//   - Scoring (ScoringConfiguration): The source weights used to score subdomains.
 type Configuration struct {
 	Client           *ClientConfiguration
 	SourcesToUSe     []string
 	SourcesToExclude []string
 	Keys             sources.Keys
+	DNSSEC           sources.DNSSECConfiguration
+	CTLogs           sources.CTLogsConfiguration
+	TLS              sources.TLSConfiguration
+	Imports          sources.ImportsConfiguration
+	ResultTypes      []sources.ResultType
+	ExcludeExpired   bool
+	Scoring          ScoringConfiguration
 }
 
 // New initializes a new Finder instance with the specified configuration.
@@ -138,20 +865,23 @@ func New(cfg *Configuration) (finder *Finder, err error) {
 	finder = &Finder{
 		sources: map[string]sources.Source{},
 		configuration: &sources.Configuration{
-			Keys: cfg.Keys,
+			Keys:           cfg.Keys,
+			DNSSEC:         cfg.DNSSEC,
+			CTLogs:         cfg.CTLogs,
+			TLS:            cfg.TLS,
+			Imports:        cfg.Imports,
+			ResultTypes:    cfg.ResultTypes,
+			ExcludeExpired: cfg.ExcludeExpired,
 		},
+		scorer: newScorer(cfg.Scoring),
 	}
 
-	cc := hqgohttp.DefaultSprayingClientConfiguration
-
-	cc.Headers = []hqgohttp.Header{}
-	cc.Timeout = 1 * time.Hour
-
-	if cfg.Client != nil && cfg.Client.UserAgent != "" {
-		cc.Headers = append(cc.Headers, hqgohttp.NewSetHeader(hqgohttpheader.UserAgent.String(), cfg.Client.UserAgent))
-	}
+	// Each Finder has its own HTTP clients, handed to its sources through their
+	// configuration, so Finders never share or replace each other's. Each call gets a
+	// client bound to its context; see newRun.
+	finder.client = newClient(cfg)
 
-	hqgohttp.DefaultClient, err = hqgohttp.NewClient(cc)
+	finder.configuration.HTTPClient, err = finder.client.bind(context.Background())
 	if err != nil {
 		return
 	}
@@ -182,14 +912,24 @@ func New(cfg *Configuration) (finder *Finder, err error) {
 			finder.sources[source] = &driftnet.Source{}
 		case sources.CRTSH:
 			finder.sources[source] = &crtsh.Source{}
+		case sources.CTLOGS:
+			finder.sources[source] = &ctlogs.Source{}
+		case sources.DNSSEC:
+			finder.sources[source] = &dnssec.Source{}
+		case sources.FDNSFILE:
+			finder.sources[source] = &imports.FDNSSource{}
 		case sources.FULLHUNT:
 			finder.sources[source] = &fullhunt.Source{}
 		case sources.GITHUB:
 			finder.sources[source] = &github.Source{}
 		case sources.HACKERTARGET:
 			finder.sources[source] = &hackertarget.Source{}
+		case sources.HOSTSFILE:
+			finder.sources[source] = &imports.HostsSource{}
 		case sources.INTELLIGENCEX:
 			finder.sources[source] = &intelx.Source{}
+		case sources.JSONLFILE:
+			finder.sources[source] = &imports.JSONLSource{}
 		case sources.LEAKIX:
 			finder.sources[source] = &leakix.Source{}
 		case sources.OPENTHREATEXCHANGE:
@@ -200,12 +940,16 @@ func New(cfg *Configuration) (finder *Finder, err error) {
 			finder.sources[source] = &shodan.Source{}
 		case sources.SUBDOMAINCENTER:
 			finder.sources[source] = &subdomaincenter.Source{}
+		case sources.TLS:
+			finder.sources[source] = &tls.Source{}
 		case sources.URLSCAN:
 			finder.sources[source] = &urlscan.Source{}
 		case sources.VIRUSTOTAL:
 			finder.sources[source] = &virustotal.Source{}
 		case sources.WAYBACK:
 			finder.sources[source] = &wayback.Source{}
+		case sources.ZONEFILE:
+			finder.sources[source] = &imports.ZoneSource{}
 		}
 	}
 
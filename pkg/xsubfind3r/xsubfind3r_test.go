@@ -0,0 +1,374 @@
+package xsubfind3r
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hueristiq/xsubfind3r/pkg/xsubfind3r/sources"
+)
+
+// agents is a local Certificate Transparency log with no entries that records, for each
+// log path, the User-Agent headers it was requested with.
+type agents struct {
+	mu   sync.Mutex
+	seen map[string][]string
+}
+
+func (a *agents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+
+	a.seen[strings.Split(r.URL.Path, "/")[1]] = append(a.seen[strings.Split(r.URL.Path, "/")[1]], r.UserAgent())
+
+	a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	fmt.Fprint(w, `{"tree_size":0}`)
+}
+
+// newTestFinder returns a Finder reading hosts and the log at server.URL/name, sending
+// requests as name.
+func newTestFinder(t *testing.T, server *httptest.Server, name, hosts string) (finder *Finder) {
+	t.Helper()
+
+	finder, err := New(&Configuration{
+		Client:       &ClientConfiguration{UserAgent: name},
+		SourcesToUSe: []string{sources.CTLOGS, sources.HOSTSFILE},
+		CTLogs: sources.CTLogsConfiguration{
+			Logs: []sources.CTLogConfiguration{{URL: server.URL + "/" + name + "/"}},
+		},
+		Imports: sources.ImportsConfiguration{
+			Hosts: []string{hosts},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// TestFindConcurrent runs concurrent calls on one Finder and on two Finders, while more
+// Finders are created. Run with -race.
+func TestFindConcurrent(t *testing.T) {
+	log := &agents{seen: map[string][]string{}}
+
+	server := httptest.NewServer(log)
+
+	defer server.Close()
+
+	hosts := filepath.Join(t.TempDir(), "hosts")
+
+	if err := os.WriteFile(hosts, []byte("127.0.0.1 a.example.com\n127.0.0.2 b.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	finders := map[string]*Finder{
+		"first":  newTestFinder(t, server, "first", hosts),
+		"second": newTestFinder(t, server, "second", hosts),
+	}
+
+	wg := &sync.WaitGroup{}
+
+	for name, finder := range finders {
+		for range 4 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				found := map[string]bool{}
+
+				for result := range finder.Find("example.com") {
+					if result.Type == sources.ResultError {
+						t.Errorf("%s: %v", name, result.Error)
+					}
+
+					found[result.Value] = true
+				}
+
+				if !found["a.example.com"] || !found["b.example.com"] {
+					t.Errorf("%s: got %v", name, found)
+				}
+			}()
+		}
+	}
+
+	// Finders created while others run, e.g. on a configuration reload, leave the
+	// running ones alone.
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			newTestFinder(t, server, "third", hosts)
+		}()
+	}
+
+	wg.Wait()
+
+	log.mu.Lock()
+
+	defer log.mu.Unlock()
+
+	for name := range finders {
+		if len(log.seen[name]) != 4 {
+			t.Errorf("%s: log requested %d times, want 4", name, len(log.seen[name]))
+		}
+
+		for _, agent := range log.seen[name] {
+			if agent != name {
+				t.Errorf("%s: log requested with the client of %q", name, agent)
+			}
+		}
+	}
+}
+
+// TestFindAbortsRequests stops a call while a source waits on a response, and checks the
+// request is aborted rather than left to complete.
+func TestFindAbortsRequests(t *testing.T) {
+	aborted := make(chan struct{})
+
+	once := &sync.Once{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			once.Do(func() { close(aborted) })
+		case <-time.After(time.Minute):
+		}
+	}))
+
+	defer server.Close()
+
+	finder, err := New(&Configuration{
+		SourcesToUSe: []string{sources.CTLOGS},
+		CTLogs: sources.CTLogsConfiguration{
+			Logs: []sources.CTLogConfiguration{{URL: server.URL + "/"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range finder.Find("example.com", WithTimeout(100*time.Millisecond)) {
+	}
+
+	select {
+	case <-aborted:
+	case <-time.After(10 * time.Second):
+		t.Fatal("request in flight not aborted once the call stopped")
+	}
+}
+
+// TestAcceptMaxAgeOrder checks that whether a subdomain passes the cutoff does not depend
+// on the order old and undated reports arrive in.
+func TestAcceptMaxAgeOrder(t *testing.T) {
+	old := sources.Result{
+		Type:     sources.ResultSubdomain,
+		Source:   sources.CRTSH,
+		Value:    "a.example.com",
+		LastSeen: time.Now().Add(-365 * 24 * time.Hour),
+	}
+
+	undated := sources.Result{
+		Type:   sources.ResultSubdomain,
+		Source: sources.ANUBIS,
+		Value:  "a.example.com",
+	}
+
+	for name, order := range map[string][]sources.Result{
+		"old first":     {old, undated},
+		"undated first": {undated, old},
+	} {
+		r := newRun("example.com", &sources.Configuration{}, newClient(&Configuration{}), newScorer(ScoringConfiguration{}), []FindOption{WithMaxAge(90 * 24 * time.Hour)})
+
+		accepted := 0
+
+		for _, result := range order {
+			if r.accept(&result) {
+				accepted++
+			}
+		}
+
+		if accepted != 1 {
+			t.Errorf("%s: accepted %d times, want once", name, accepted)
+		}
+
+		r.stop()
+	}
+
+	r := newRun("example.com", &sources.Configuration{}, newClient(&Configuration{}), newScorer(ScoringConfiguration{}), []FindOption{WithMaxAge(90 * 24 * time.Hour)})
+
+	defer r.stop()
+
+	if result := old; r.accept(&result) {
+		t.Error("subdomain only reported long ago accepted")
+	}
+}
+
+// TestAcceptExpired checks that a name is only flagged as expired if no source found it
+// other than in an expired certificate, whatever order the reports arrive in.
+func TestAcceptExpired(t *testing.T) {
+	r := newRun("example.com", &sources.Configuration{}, newClient(&Configuration{}), newScorer(ScoringConfiguration{}), nil)
+
+	defer r.stop()
+
+	expired := func(name string) *sources.Result {
+		return &sources.Result{
+			Type:     sources.ResultSubdomain,
+			Source:   sources.CRTSH,
+			Value:    name,
+			Metadata: map[string]string{sources.MetadataExpired: "true"},
+		}
+	}
+
+	if r.accept(expired("a.example.com")) || r.accept(expired("b.example.com")) {
+		t.Fatal("names only found in expired certificates emitted before every source reported")
+	}
+
+	valid := &sources.Result{Type: sources.ResultSubdomain, Source: sources.CERTSPOTTER, Value: "a.example.com"}
+
+	if !r.accept(valid) || valid.Metadata[sources.MetadataExpired] != "" {
+		t.Errorf("name found in a valid certificate: got %+v", valid)
+	}
+
+	settled := r.settle()
+
+	if len(settled) != 1 || settled[0].Value != "b.example.com" || settled[0].Metadata[sources.MetadataExpired] != "true" {
+		t.Fatalf("got %+v, want b.example.com flagged as expired", settled)
+	}
+
+	// Once settled, names are no longer held back.
+	if result := expired("c.example.com"); !r.accept(result) || result.Metadata[sources.MetadataExpired] != "true" {
+		t.Errorf("got %+v, want c.example.com flagged as expired", result)
+	}
+}
+
+// TestCollectFinalSources checks that a report carries every source that reported a
+// subdomain, not only those known when it was emitted.
+func TestCollectFinalSources(t *testing.T) {
+	dir := t.TempDir()
+
+	hosts := filepath.Join(dir, "hosts")
+	zone := filepath.Join(dir, "zone")
+
+	if err := os.WriteFile(hosts, []byte("127.0.0.1 a.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(zone, []byte("a.example.com. 300 IN A 127.0.0.1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	finder, err := New(&Configuration{
+		SourcesToUSe: []string{sources.HOSTSFILE, sources.ZONEFILE},
+		Imports: sources.ImportsConfiguration{
+			Hosts: []string{hosts},
+			Zones: []string{zone},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := finder.Collect("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := report.Sources["a.example.com"]; len(got) != 2 {
+		t.Errorf("got sources %v, want both import sources", got)
+	}
+
+	if report.Scores["a.example.com"] <= 0 {
+		t.Errorf("got score %v", report.Scores["a.example.com"])
+	}
+}
+
+// TestAcceptReports checks that, with WithReports, each further source reporting an
+// emitted subdomain is reported once.
+func TestAcceptReports(t *testing.T) {
+	r := newRun("example.com", &sources.Configuration{}, newClient(&Configuration{}), newScorer(ScoringConfiguration{}), []FindOption{WithReports()})
+
+	defer r.stop()
+
+	report := func(source string) *sources.Result {
+		return &sources.Result{Type: sources.ResultSubdomain, Source: source, Value: "a.example.com"}
+	}
+
+	if result := report(sources.CRTSH); !r.accept(result) || result.Type != sources.ResultSubdomain {
+		t.Fatalf("got %+v, want the subdomain", result)
+	}
+
+	if result := report(sources.ANUBIS); !r.accept(result) || result.Type != sources.ResultReport || result.Source != sources.ANUBIS {
+		t.Errorf("got %+v, want a report by %s", result, sources.ANUBIS)
+	}
+
+	if r.accept(report(sources.ANUBIS)) || r.accept(report(sources.CRTSH)) {
+		t.Error("source reported twice")
+	}
+}
+
+// redirect sends every request to target instead.
+type redirect struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *redirect) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	req = req.Clone(req.Context())
+
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = ""
+
+	return t.base.RoundTrip(req)
+}
+
+// TestFindResolvedScore checks that a subdomain its source resolves scores higher than one
+// it does not, although addresses are not requested.
+func TestFindResolvedScore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		fmt.Fprint(w, `{"domain":"example.com","subdomains":["a","b"],"data":[{"subdomain":"a","type":"A","value":"192.0.2.1"}]}`)
+	}))
+
+	defer server.Close()
+
+	finder, err := New(&Configuration{
+		SourcesToUSe: []string{sources.SHODAN},
+		Keys:         sources.Keys{Shodan: []string{"key"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, _ := url.Parse(server.URL)
+
+	finder.client.transport = &redirect{target: target, base: finder.client.transport}
+
+	scores := map[string]float64{}
+
+	for result := range finder.Find("example.com") {
+		if result.Type != sources.ResultSubdomain {
+			t.Fatalf("got %+v, want subdomains only", result)
+		}
+
+		scores[result.Value] = result.Score
+	}
+
+	if scores["a.example.com"] <= scores["b.example.com"] {
+		t.Errorf("got scores %v, want a.example.com, resolved, to score higher", scores)
+	}
+}